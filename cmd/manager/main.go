@@ -10,8 +10,11 @@ import (
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	"github.com/3scale/3scale-operator/pkg/apis"
+	operatorcache "github.com/3scale/3scale-operator/pkg/cache"
 	"github.com/3scale/3scale-operator/pkg/controller"
+	"github.com/3scale/3scale-operator/pkg/controller/apimanager"
 
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	"github.com/operator-framework/operator-sdk/pkg/leader"
@@ -20,6 +23,9 @@ import (
 	"github.com/operator-framework/operator-sdk/pkg/restmapper"
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
 	"github.com/spf13/pflag"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
@@ -62,11 +68,19 @@ func main() {
 
 	printVersion()
 
-	namespace, err := k8sutil.GetWatchNamespace()
+	// WATCH_NAMESPACE is a namespace, a comma separated list of namespaces
+	// or empty to watch all namespaces
+	watchNamespace, err := k8sutil.GetWatchNamespace()
 	if err != nil {
 		log.Error(err, "Failed to get watch namespace")
 		os.Exit(1)
 	}
+	namespaces := operatorcache.WatchNamespaces(watchNamespace)
+	if len(namespaces) == 0 {
+		log.Info("Watching all namespaces")
+	} else {
+		log.Info(fmt.Sprintf("Watching namespaces %v", namespaces))
+	}
 
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
@@ -77,6 +91,11 @@ func main() {
 
 	ctx := context.TODO()
 
+	if err := loadReleaseCatalogOverride(cfg, namespaces); err != nil {
+		log.Error(err, "Failed to load the release catalog")
+		os.Exit(1)
+	}
+
 	// Become the leader before proceeding
 	err = leader.Become(ctx, "3scale-operator-lock")
 	if err != nil {
@@ -84,12 +103,24 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{
-		Namespace:          namespace,
+	options := manager.Options{
 		MapperProvider:     restmapper.NewDynamicRESTMapper,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
-	})
+		// Secrets are read from the API server, so the cache does not hold
+		// all the Secrets of the watched namespaces
+		NewClient: operatorcache.UncachedClientBuilder(&v1.Secret{}, &v1.SecretList{}),
+	}
+	switch len(namespaces) {
+	case 0:
+		// The cache watches all namespaces
+	case 1:
+		options.Namespace = namespaces[0]
+	default:
+		options.NewCache = operatorcache.MultiNamespacedCacheBuilder(namespaces)
+	}
+
+	// Create a new Cmd to provide shared dependencies and start components
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		log.Error(err, "")
 		os.Exit(1)
@@ -123,3 +154,29 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// loadReleaseCatalogOverride loads the releases of the catalog ConfigMap
+// referenced by the RELEASE_CATALOG_CONFIGMAP environment variable, if any.
+// The APIManager controller reloads it when it changes
+func loadReleaseCatalogOverride(cfg *rest.Config, watchNamespaces []string) error {
+	configMapNN, err := apimanager.ReleaseCatalogConfigMap(watchNamespaces)
+	if err != nil || configMapNN == nil {
+		return err
+	}
+
+	// The manager cache is not started yet
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return err
+	}
+	data, err := apimanager.ReadReleaseCatalog(c, *configMapNN)
+	if err != nil {
+		return err
+	}
+	if err := product.LoadCatalogOverride([]byte(data)); err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Loaded release catalog ConfigMap %s", configMapNN))
+	return nil
+}
@@ -0,0 +1,159 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/3scale/3scale-operator/pkg/apis"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/controller/apimanager"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var diffKubeconfig string
+var diffNamespace string
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <apimanager name>",
+	Short: "Compare the objects of an APIManager in the cluster with the ones the operator would create",
+	Long: `Compare the objects of an APIManager in the cluster with the ones the operator
+would create for it.
+
+The APIManager is read from the cluster and its objects are generated with the
+same logic the operator uses, reading the Secrets from the cluster. Only the
+fields set by the operator are compared, so fields populated by the server are
+ignored. The differences are printed per kind:
+
+  ~ changed objects, with the fields whose live value differs
+  - missing objects, that the operator would create
+  + leftover objects, controlled by the APIManager but not created by the
+    operator anymore, like the objects of a disabled feature
+
+Values of Secrets are not printed. The command exits with status 1 when
+differences are found.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		differs, err := runDiff(args[0], os.Stdout)
+		if err != nil {
+			return err
+		}
+		if differs {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func runDiff(apiManagerName string, out io.Writer) (bool, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = diffKubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+
+	namespace := diffNamespace
+	if namespace == "" {
+		var err error
+		namespace, _, err = clientConfig.Namespace()
+		if err != nil {
+			return false, err
+		}
+	}
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return false, err
+	}
+
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		return false, err
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		return false, err
+	}
+	c, err := client.New(config, client.Options{Scheme: s})
+	if err != nil {
+		return false, err
+	}
+
+	cr := &appsv1alpha1.APIManager{}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: apiManagerName, Namespace: namespace}, cr)
+	if err != nil {
+		return false, err
+	}
+	if _, err := cr.SetDefaults(); err != nil {
+		return false, fmt.Errorf("error setting defaults: %v", err)
+	}
+
+	diffs, err := apimanager.DiffAPIManagerObjects(c, s, cr)
+	if err != nil {
+		return false, err
+	}
+
+	printObjectDiffs(diffs, out)
+	return len(diffs) > 0, nil
+}
+
+// printObjectDiffs prints the differences grouped by kind, in the order the
+// kinds are first found
+func printObjectDiffs(diffs []apimanager.ObjectDiff, out io.Writer) {
+	if len(diffs) == 0 {
+		fmt.Fprintln(out, "No differences found")
+		return
+	}
+
+	kinds := []string{}
+	diffsByKind := map[string][]apimanager.ObjectDiff{}
+	for _, diff := range diffs {
+		if _, ok := diffsByKind[diff.Kind]; !ok {
+			kinds = append(kinds, diff.Kind)
+		}
+		diffsByKind[diff.Kind] = append(diffsByKind[diff.Kind], diff)
+	}
+
+	for _, kind := range kinds {
+		fmt.Fprintf(out, "%s:\n", kind)
+		for _, diff := range diffsByKind[kind] {
+			switch diff.Type {
+			case apimanager.ObjectDiffChanged:
+				fmt.Fprintf(out, "  ~ %s\n", diff.Name)
+				for _, field := range diff.Fields {
+					fmt.Fprintf(out, "      %s\n", field.Path)
+					fmt.Fprintf(out, "        desired: %s\n", field.Desired)
+					fmt.Fprintf(out, "        live:    %s\n", field.Live)
+				}
+			case apimanager.ObjectDiffMissing:
+				fmt.Fprintf(out, "  - %s (missing)\n", diff.Name)
+			case apimanager.ObjectDiffLeftover:
+				fmt.Fprintf(out, "  + %s (leftover)\n", diff.Name)
+			}
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVar(&diffKubeconfig, "kubeconfig", "", "path to the kubeconfig file. Defaults to the KUBECONFIG environment variable or $HOME/.kube/config")
+	diffCmd.Flags().StringVarP(&diffNamespace, "namespace", "n", "", "namespace of the APIManager. Defaults to the namespace of the current kubeconfig context")
+}
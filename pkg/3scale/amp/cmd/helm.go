@@ -0,0 +1,254 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	templatev1 "github.com/openshift/api/template/v1"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	helmChartAPIVersion = "v1"
+	helmChartVersion    = "0.1.0"
+)
+
+// helmChart converts an OpenShift Template into a Helm chart. Template
+// parameters become values and their references in the objects become
+// template actions reading them
+type helmChart struct {
+	template   *templatev1.Template
+	parameters map[string]templatev1.Parameter
+	// secretKeys are the Secret keys that hold the value of the generated
+	// parameters, by parameter name
+	secretKeys map[string]templateSecretKey
+}
+
+// writeHelmChart writes the Helm chart of the template to dir. objects are
+// the serialized template objects
+func writeHelmChart(template *templatev1.Template, objects []interface{}, dir string) error {
+	chart := &helmChart{
+		template:   template,
+		parameters: templateParametersByName(template),
+		secretKeys: generatedParameterSecretKeys(template, objects),
+	}
+
+	manifest, err := chart.manifest(objects)
+	if err != nil {
+		return err
+	}
+
+	templatesDir := filepath.Join(dir, "templates")
+	err = os.MkdirAll(templatesDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		path    string
+		content []byte
+	}{
+		{filepath.Join(dir, "Chart.yaml"), chart.chartMetadata()},
+		{filepath.Join(dir, "values.yaml"), chart.values()},
+		{filepath.Join(templatesDir, "NOTES.txt"), chart.notes()},
+		{filepath.Join(templatesDir, template.Name+".yaml"), manifest},
+	}
+	for _, file := range files {
+		err = ioutil.WriteFile(file.path, file.content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *helmChart) chartMetadata() []byte {
+	metadata := yaml.MapSlice{
+		{Key: "apiVersion", Value: helmChartAPIVersion},
+		{Key: "name", Value: c.template.Name},
+		{Key: "version", Value: helmChartVersion},
+	}
+	if release, ok := c.parameters["AMP_RELEASE"]; ok && release.Value != "" {
+		metadata = append(metadata, yaml.MapItem{Key: "appVersion", Value: release.Value})
+	}
+	if description := c.template.Annotations["description"]; description != "" {
+		metadata = append(metadata, yaml.MapItem{Key: "description", Value: description})
+	}
+
+	result, _ := yaml.Marshal(metadata)
+	return result
+}
+
+// values returns the values.yaml of the chart, with an entry per template
+// parameter. Generated parameters are empty and get a random value on
+// install unless they are set
+func (c *helmChart) values() []byte {
+	var buffer bytes.Buffer
+	for _, parameter := range c.template.Parameters {
+		if parameter.Description != "" {
+			fmt.Fprintf(&buffer, "# %s\n", strings.Join(strings.Fields(parameter.Description), " "))
+		}
+		if parameter.Generate != "" {
+			fmt.Fprintf(&buffer, "# Generated from %s when empty\n", parameter.From)
+		}
+		value, _ := yaml.Marshal(parameter.Value)
+		fmt.Fprintf(&buffer, "%s: %s", parameterValueName(parameter.Name), value)
+	}
+	return buffer.Bytes()
+}
+
+func (c *helmChart) notes() []byte {
+	var buffer bytes.Buffer
+	for _, part := range splitParameterReferences(c.template.Message, c.parameters) {
+		if part.parameter != "" {
+			fmt.Fprintf(&buffer, "{{ %s }}", helmValueReference(part.parameter))
+		} else {
+			buffer.WriteString(strings.Replace(part.literal, "{{", `{{ "{{" }}`, -1))
+		}
+	}
+	buffer.WriteString("\n")
+	return buffer.Bytes()
+}
+
+// manifest returns the chart template with the objects. String fields with
+// parameter references are replaced by placeholders before serializing
+// the objects, so the YAML encoder does not quote the template actions
+// that replace them afterwards
+func (c *helmChart) manifest(objects []interface{}) ([]byte, error) {
+	header, err := c.manifestHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders := []string{}
+	mapFunc := func(path []string, str string) interface{} {
+		parts := splitParameterReferences(str, c.parameters)
+		if !hasParameterReferences(parts) && !strings.Contains(str, "{{") {
+			return str
+		}
+		placeholder := fmt.Sprintf("HELM_EXPRESSION_%d_", len(placeholders)/2)
+		placeholders = append(placeholders, placeholder, helmExpression(parts))
+		return placeholder
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write(header)
+	for _, object := range objects {
+		serializedObject, err := yaml.Marshal(mapStrings(object, nil, mapFunc))
+		if err != nil {
+			return nil, err
+		}
+		buffer.WriteString("---\n")
+		buffer.Write(serializedObject)
+	}
+
+	return []byte(strings.NewReplacer(placeholders...).Replace(buffer.String())), nil
+}
+
+// manifestHeader returns the actions that fail the install when a required
+// value is not set and set the generated values. .Values is shared by all
+// the objects, so a generated value is the same in all of them.
+// Generated values that are not set are read from the Secret that holds
+// them, so upgrades keep the installed credentials. They are only given a
+// random value on install
+func (c *helmChart) manifestHeader() ([]byte, error) {
+	var buffer bytes.Buffer
+	for _, parameter := range c.template.Parameters {
+		valueName := parameterValueName(parameter.Name)
+		if parameter.Generate != "" {
+			generateExpression, err := helmGenerateExpression(parameter.From)
+			if err != nil {
+				return nil, fmt.Errorf("parameter %s: %v", parameter.Name, err)
+			}
+			fmt.Fprintf(&buffer, "{{- if not %s }}\n", helmValueReference(parameter.Name))
+			failMessage := fmt.Sprintf("A value for %s is required on upgrade", valueName)
+			if secretKey, ok := c.secretKeys[parameter.Name]; ok {
+				fmt.Fprintf(&buffer, "{{- $secret := lookup \"v1\" \"Secret\" .Release.Namespace %q | default dict }}\n", secretKey.secret)
+				buffer.WriteString("{{- $secretData := get $secret \"data\" | default dict }}\n")
+				fmt.Fprintf(&buffer, "{{- if hasKey $secretData %q }}\n", secretKey.key)
+				fmt.Fprintf(&buffer, "{{- $_ := set .Values %q (get $secretData %q | b64dec) }}\n", valueName, secretKey.key)
+				buffer.WriteString("{{- else if .Release.IsInstall }}\n")
+				failMessage = fmt.Sprintf("A value for %s is required on upgrade when the %s Secret does not have %s", valueName, secretKey.secret, secretKey.key)
+			} else {
+				buffer.WriteString("{{- if .Release.IsInstall }}\n")
+			}
+			fmt.Fprintf(&buffer, "{{- $_ := set .Values %q (%s) }}\n", valueName, generateExpression)
+			buffer.WriteString("{{- else }}\n")
+			fmt.Fprintf(&buffer, "{{- fail %q }}\n", failMessage)
+			buffer.WriteString("{{- end }}\n")
+			buffer.WriteString("{{- end }}\n")
+		} else if parameter.Required && parameter.Value == "" {
+			fmt.Fprintf(&buffer, "{{- $_ := required %q %s }}\n", "A value for "+valueName+" is required", helmValueReference(parameter.Name))
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+func helmValueReference(parameterName string) string {
+	return ".Values." + parameterValueName(parameterName)
+}
+
+// helmExpression returns the template action that renders a string with
+// parameter references. Strings that are a single ${{PARAM}} reference are
+// rendered unquoted, so the value can be a boolean or null
+func helmExpression(parts []templateStringPart) string {
+	if len(parts) == 1 && parts[0].parameter != "" {
+		if parts[0].nonString {
+			return fmt.Sprintf("{{ %s }}", helmValueReference(parts[0].parameter))
+		}
+		return fmt.Sprintf("{{ %s | quote }}", helmValueReference(parts[0].parameter))
+	}
+
+	format := ""
+	args := []string{}
+	for _, part := range parts {
+		if part.parameter != "" {
+			format += "%v"
+			args = append(args, helmValueReference(part.parameter))
+		} else {
+			format += strings.Replace(part.literal, "%", "%%", -1)
+		}
+	}
+	return fmt.Sprintf("{{ printf %s | quote }}", strings.Join(append([]string{strconv.Quote(format)}, args...), " "))
+}
+
+// helmGenerateExpression returns the template function call that generates
+// a value for the [charset]{length} expression of a generated parameter
+func helmGenerateExpression(expression string) (string, error) {
+	charset, length, err := parseGenerateExpression(expression)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case strings.Trim(charset, "0123456789abcdef") == "":
+		// sha256sum returns 64 hexadecimal characters
+		hashes := []string{}
+		for idx := 0; idx < length; idx += 64 {
+			hashes = append(hashes, "(randAlphaNum 64 | sha256sum)")
+		}
+		return fmt.Sprintf("print %s | trunc %d", strings.Join(hashes, " "), length), nil
+	case strings.Trim(charset, "0123456789abcdefghijklmnopqrstuvwxyz") == "":
+		return fmt.Sprintf("randAlphaNum %d | lower", length), nil
+	default:
+		return fmt.Sprintf("randAlphaNum %d", length), nil
+	}
+}
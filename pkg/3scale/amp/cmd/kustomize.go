@@ -0,0 +1,426 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	templatev1 "github.com/openshift/api/template/v1"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	kustomizationFileName                  = "kustomization.yaml"
+	kustomizeConfigurationFileName         = "kustomizeconfig.yaml"
+	kustomizeParametersConfigMapNameSuffix = "-parameters"
+	kustomizeCredentialsSecretNameSuffix   = "-credentials"
+	kustomizeCredentialsEnvFileName        = "credentials.env"
+	kustomizeEnvFileExampleSuffix          = ".example"
+)
+
+// kustomizeBase converts an OpenShift Template into a Kustomize base. The
+// template parameters are set in a generated ConfigMap and their references
+// in the objects become Kustomize vars reading it. Generated parameters are
+// credentials and are never written to the base: the Secrets that hold them
+// are generated from env files supplied by the user, and the objects that
+// are not Secrets read them from the <template>-credentials Secret
+type kustomizeBase struct {
+	template   *templatev1.Template
+	parameters map[string]templatev1.Parameter
+	// usedParameters are the parameters referenced as vars
+	usedParameters map[string]bool
+	// varReferences are the fields with vars, as kind/path keys
+	varReferences map[string]kustomizeFieldSpec
+	// usedCredentials are the generated parameters read from the
+	// credentials Secret
+	usedCredentials map[string]bool
+	// secretGenerators are the generators of the template Secrets that
+	// hold credentials
+	secretGenerators []yaml.MapSlice
+}
+
+type kustomizeFieldSpec struct {
+	Kind string `yaml:"kind"`
+	Path string `yaml:"path"`
+}
+
+// writeKustomizeBase writes the Kustomize base of the template to dir.
+// objects are the serialized template objects
+func writeKustomizeBase(template *templatev1.Template, objects []interface{}, dir string) error {
+	base := &kustomizeBase{
+		template:        template,
+		parameters:      templateParametersByName(template),
+		usedParameters:  map[string]bool{},
+		varReferences:   map[string]kustomizeFieldSpec{},
+		usedCredentials: map[string]bool{},
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	resources := []string{}
+	for _, object := range objects {
+		objectFields, _ := object.(map[string]interface{})
+		kind, _ := objectFields["kind"].(string)
+		metadata, _ := objectFields["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+
+		if kind == "Secret" && base.credentialReference(objectFields, nil) != "" {
+			envFileExample, err := base.secretGenerator(name, objectFields)
+			if err != nil {
+				return err
+			}
+			err = ioutil.WriteFile(filepath.Join(dir, name+".env"+kustomizeEnvFileExampleSuffix), envFileExample, 0644)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		objectFields, err = base.readCredentialsFromSecret(kind, objectFields)
+		if err != nil {
+			return err
+		}
+		content, err := yaml.Marshal(base.object(kind, objectFields))
+		if err != nil {
+			return err
+		}
+		fileName := strings.ToLower(fmt.Sprintf("%s-%s.yaml", kind, name))
+		err = ioutil.WriteFile(filepath.Join(dir, fileName), content, 0644)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, fileName)
+	}
+
+	if len(base.usedCredentials) > 0 {
+		err = ioutil.WriteFile(filepath.Join(dir, kustomizeCredentialsEnvFileName+kustomizeEnvFileExampleSuffix), base.credentialsEnvFileExample(), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	kustomization, err := base.kustomization(resources)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, kustomizationFileName), kustomization, 0644)
+	if err != nil {
+		return err
+	}
+
+	configuration, err := base.configuration()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, kustomizeConfigurationFileName), configuration, 0644)
+}
+
+func (k *kustomizeBase) isCredential(parameterName string) bool {
+	return k.parameters[parameterName].Generate != ""
+}
+
+func (k *kustomizeBase) credentialsSecretName() string {
+	return k.template.Name + kustomizeCredentialsSecretNameSuffix
+}
+
+// credentialReference returns the first generated parameter referenced by
+// a string of value, or an empty string when there is none. path is
+// updated with the map keys leading to the reference
+func (k *kustomizeBase) credentialReference(value interface{}, path *[]string) string {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(typedValue) {
+			if parameter := k.credentialReference(typedValue[key], path); parameter != "" {
+				if path != nil {
+					*path = append([]string{key}, *path...)
+				}
+				return parameter
+			}
+		}
+	case []interface{}:
+		for _, elem := range typedValue {
+			if parameter := k.credentialReference(elem, path); parameter != "" {
+				return parameter
+			}
+		}
+	case string:
+		for _, part := range splitParameterReferences(typedValue, k.parameters) {
+			if part.parameter != "" && k.isCredential(part.parameter) {
+				return part.parameter
+			}
+		}
+	}
+	return ""
+}
+
+// secretGenerator adds the generator of a template Secret that holds
+// credentials and returns the example of its env file, with the template
+// value of each key
+func (k *kustomizeBase) secretGenerator(name string, secret map[string]interface{}) ([]byte, error) {
+	metadata, _ := secret["metadata"].(map[string]interface{})
+	if data, _ := secret["data"].(map[string]interface{}); len(data) > 0 {
+		return nil, fmt.Errorf("secret %s: credentials can only be set in stringData", name)
+	}
+
+	options := yaml.MapSlice{{Key: "disableNameSuffixHash", Value: true}}
+	if labels, ok := metadata["labels"]; ok {
+		// Vars are substituted in the labels of the generated Secret
+		options = append(options, yaml.MapItem{Key: "labels", Value: mapStrings(labels, []string{"metadata", "labels"}, k.varMapFunc("Secret"))})
+	}
+	generator := yaml.MapSlice{
+		{Key: "name", Value: name},
+		{Key: "envs", Value: []string{name + ".env"}},
+	}
+	if secretType, _ := secret["type"].(string); secretType != "" && secretType != "Opaque" {
+		generator = append(generator, yaml.MapItem{Key: "type", Value: secretType})
+	}
+	generator = append(generator, yaml.MapItem{Key: "options", Value: options})
+	k.secretGenerators = append(k.secretGenerators, generator)
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "# Keys of the %s Secret. Copy to %s.env and replace the ${PARAMETER} references\n", name, name)
+	stringData, _ := secret["stringData"].(map[string]interface{})
+	for _, key := range sortedKeys(stringData) {
+		value, _ := stringData[key].(string)
+		fmt.Fprintf(&buffer, "%s=%s\n", key, value)
+	}
+	return buffer.Bytes(), nil
+}
+
+// readCredentialsFromSecret returns a copy of the object where the
+// credentials are read from the credentials Secret. Env vars set to a
+// credential read its key, and credentials in commands and args are
+// replaced by references to an env var reading it, which are expanded by
+// the container runtime. Other credential references fail, as they would
+// be written to the base
+func (k *kustomizeBase) readCredentialsFromSecret(kind string, object map[string]interface{}) (map[string]interface{}, error) {
+	result := mapStrings(object, nil, func(path []string, str string) interface{} { return str }).(map[string]interface{})
+	k.readContainerCredentialsFromSecret(result)
+
+	path := []string{}
+	if parameter := k.credentialReference(result, &path); parameter != "" {
+		return nil, fmt.Errorf("parameter %s is referenced in %s %s, where it cannot be read from a Secret", parameter, kind, strings.Join(path, "/"))
+	}
+	return result, nil
+}
+
+func (k *kustomizeBase) readContainerCredentialsFromSecret(value interface{}) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		env, _ := typedValue["env"].([]interface{})
+		for _, envVar := range env {
+			envVarFields, _ := envVar.(map[string]interface{})
+			str, _ := envVarFields["value"].(string)
+			parts := splitParameterReferences(str, k.parameters)
+			if len(parts) == 1 && parts[0].parameter != "" && k.isCredential(parts[0].parameter) {
+				delete(envVarFields, "value")
+				envVarFields["valueFrom"] = k.credentialKeyRef(parts[0].parameter)
+			}
+		}
+
+		for _, field := range []string{"command", "args"} {
+			values, _ := typedValue[field].([]interface{})
+			for idx, elem := range values {
+				str, ok := elem.(string)
+				if !ok {
+					continue
+				}
+				result := ""
+				for _, part := range splitParameterReferences(str, k.parameters) {
+					switch {
+					case part.parameter == "":
+						result += part.literal
+					case k.isCredential(part.parameter):
+						result += fmt.Sprintf("$(%s)", part.parameter)
+						env = appendCredentialEnvVar(env, part.parameter, k.credentialKeyRef(part.parameter))
+						typedValue["env"] = env
+					case part.nonString:
+						result += fmt.Sprintf("${{%s}}", part.parameter)
+					default:
+						result += fmt.Sprintf("${%s}", part.parameter)
+					}
+				}
+				values[idx] = result
+			}
+		}
+
+		for _, elem := range typedValue {
+			k.readContainerCredentialsFromSecret(elem)
+		}
+	case []interface{}:
+		for _, elem := range typedValue {
+			k.readContainerCredentialsFromSecret(elem)
+		}
+	}
+}
+
+func (k *kustomizeBase) credentialKeyRef(parameterName string) map[string]interface{} {
+	k.usedCredentials[parameterName] = true
+	return map[string]interface{}{
+		"secretKeyRef": map[string]interface{}{
+			"name": k.credentialsSecretName(),
+			"key":  parameterName,
+		},
+	}
+}
+
+// appendCredentialEnvVar adds an env var reading a credential, unless the
+// env already has a var with its name
+func appendCredentialEnvVar(env []interface{}, name string, valueFrom map[string]interface{}) []interface{} {
+	for _, envVar := range env {
+		if envVarFields, _ := envVar.(map[string]interface{}); envVarFields["name"] == name {
+			return env
+		}
+	}
+	return append(env, map[string]interface{}{"name": name, "valueFrom": valueFrom})
+}
+
+// credentialsEnvFileExample returns the example of the env file of the
+// credentials Secret, with a key per credential read from it
+func (k *kustomizeBase) credentialsEnvFileExample() []byte {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "# Keys of the %s Secret. Copy to %s and set the values\n", k.credentialsSecretName(), kustomizeCredentialsEnvFileName)
+	for _, parameter := range k.template.Parameters {
+		if !k.usedCredentials[parameter.Name] {
+			continue
+		}
+		if parameter.Description != "" {
+			fmt.Fprintf(&buffer, "# %s\n", strings.Join(strings.Fields(parameter.Description), " "))
+		}
+		fmt.Fprintf(&buffer, "%s=\n", parameter.Name)
+	}
+	return buffer.Bytes()
+}
+
+// object replaces the parameter references of the object by vars. Vars are
+// always substituted as strings, so fields that are a single ${{PARAM}}
+// reference are set to the parameter value instead
+func (k *kustomizeBase) object(kind string, object map[string]interface{}) interface{} {
+	return mapStrings(object, nil, k.varMapFunc(kind))
+}
+
+// varMapFunc returns the mapStrings function that replaces the parameter
+// references of the strings of an object of the given kind by vars
+func (k *kustomizeBase) varMapFunc(kind string) func(path []string, str string) interface{} {
+	return func(path []string, str string) interface{} {
+		parts := splitParameterReferences(str, k.parameters)
+		if !hasParameterReferences(parts) {
+			return str
+		}
+
+		if len(parts) == 1 && parts[0].nonString {
+			var value interface{}
+			if err := yaml.Unmarshal([]byte(k.parameters[parts[0].parameter].Value), &value); err != nil {
+				return k.parameters[parts[0].parameter].Value
+			}
+			return value
+		}
+
+		result := ""
+		for _, part := range parts {
+			if part.parameter != "" {
+				result += fmt.Sprintf("$(%s)", part.parameter)
+				k.usedParameters[part.parameter] = true
+			} else {
+				result += part.literal
+			}
+		}
+		fieldSpec := kustomizeFieldSpec{Kind: kind, Path: strings.Join(path, "/")}
+		k.varReferences[fieldSpec.Kind+"/"+fieldSpec.Path] = fieldSpec
+		return result
+	}
+}
+
+// kustomization returns the kustomization.yaml of the base. Generated
+// parameters are read from the env files of the Secret generators
+func (k *kustomizeBase) kustomization(resources []string) ([]byte, error) {
+	configMapName := k.template.Name + kustomizeParametersConfigMapNameSuffix
+
+	literals := []string{}
+	vars := []yaml.MapSlice{}
+	for _, parameter := range k.template.Parameters {
+		if k.isCredential(parameter.Name) {
+			continue
+		}
+		literals = append(literals, fmt.Sprintf("%s=%s", parameter.Name, parameter.Value))
+
+		if k.usedParameters[parameter.Name] {
+			vars = append(vars, yaml.MapSlice{
+				{Key: "name", Value: parameter.Name},
+				{Key: "objref", Value: yaml.MapSlice{
+					{Key: "apiVersion", Value: "v1"},
+					{Key: "kind", Value: "ConfigMap"},
+					{Key: "name", Value: configMapName},
+				}},
+				{Key: "fieldref", Value: yaml.MapSlice{
+					{Key: "fieldpath", Value: "data." + parameter.Name},
+				}},
+			})
+		}
+	}
+
+	secretGenerators := []yaml.MapSlice{}
+	if len(k.usedCredentials) > 0 {
+		secretGenerators = append(secretGenerators, yaml.MapSlice{
+			{Key: "name", Value: k.credentialsSecretName()},
+			{Key: "envs", Value: []string{kustomizeCredentialsEnvFileName}},
+			{Key: "options", Value: yaml.MapSlice{{Key: "disableNameSuffixHash", Value: true}}},
+		})
+	}
+	secretGenerators = append(secretGenerators, k.secretGenerators...)
+
+	kustomization := yaml.MapSlice{
+		{Key: "apiVersion", Value: "kustomize.config.k8s.io/v1beta1"},
+		{Key: "kind", Value: "Kustomization"},
+		{Key: "resources", Value: resources},
+		{Key: "configMapGenerator", Value: []yaml.MapSlice{
+			{
+				{Key: "name", Value: configMapName},
+				{Key: "literals", Value: literals},
+			},
+		}},
+	}
+	if len(secretGenerators) > 0 {
+		kustomization = append(kustomization, yaml.MapItem{Key: "secretGenerator", Value: secretGenerators})
+	}
+	kustomization = append(kustomization,
+		yaml.MapItem{Key: "configurations", Value: []string{kustomizeConfigurationFileName}},
+		yaml.MapItem{Key: "vars", Value: vars},
+	)
+	return yaml.Marshal(kustomization)
+}
+
+// configuration returns the Kustomize configuration with the fields where
+// vars are substituted, which are not known by Kustomize
+func (k *kustomizeBase) configuration() ([]byte, error) {
+	keys := []string{}
+	for key := range k.varReferences {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	varReferences := []kustomizeFieldSpec{}
+	for _, key := range keys {
+		varReferences = append(varReferences, k.varReferences[key])
+	}
+	return yaml.Marshal(map[string]interface{}{"varReference": varReferences})
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	templatev1 "github.com/openshift/api/template/v1"
+)
+
+func TestKustomizeBaseObject(t *testing.T) {
+	template := &templatev1.Template{
+		Parameters: []templatev1.Parameter{
+			{Name: "APP_LABEL", Value: "3scale-api-management"},
+			{Name: "WILDCARD_DOMAIN"},
+			{Name: "INSECURE", Value: "false"},
+		},
+	}
+	base := &kustomizeBase{
+		template:       template,
+		parameters:     templateParametersByName(template),
+		usedParameters: map[string]bool{},
+		varReferences:  map[string]kustomizeFieldSpec{},
+	}
+
+	object := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "${APP_LABEL}"},
+		},
+		"spec": map[string]interface{}{
+			"host": "master.${WILDCARD_DOMAIN}",
+			"tags": []interface{}{
+				map[string]interface{}{"insecure": "${{INSECURE}}", "name": "${UNKNOWN}"},
+			},
+		},
+	}
+	expected := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "$(APP_LABEL)"},
+		},
+		"spec": map[string]interface{}{
+			"host": "master.$(WILDCARD_DOMAIN)",
+			"tags": []interface{}{
+				map[string]interface{}{"insecure": false, "name": "${UNKNOWN}"},
+			},
+		},
+	}
+
+	result := base.object("Route", object)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected object %v, got %v", expected, result)
+	}
+
+	expectedVarReferences := map[string]kustomizeFieldSpec{
+		"Route/metadata/labels/app": {Kind: "Route", Path: "metadata/labels/app"},
+		"Route/spec/host":           {Kind: "Route", Path: "spec/host"},
+	}
+	if !reflect.DeepEqual(base.varReferences, expectedVarReferences) {
+		t.Errorf("expected var references %v, got %v", expectedVarReferences, base.varReferences)
+	}
+	if !base.usedParameters["APP_LABEL"] || !base.usedParameters["WILDCARD_DOMAIN"] || base.usedParameters["INSECURE"] {
+		t.Errorf("unexpected used parameters %v", base.usedParameters)
+	}
+}
+
+func TestWriteKustomizeBaseCredentials(t *testing.T) {
+	template := &templatev1.Template{
+		Parameters: []templatev1.Parameter{
+			{Name: "APP_LABEL", Value: "3scale-api-management"},
+			{Name: "MASTER_ACCESS_TOKEN", Generate: "expression", From: "[a-z0-9]{8}"},
+			{Name: "ROOT_PASSWORD", Generate: "expression", From: "[a-z0-9]{8}"},
+		},
+	}
+	template.Name = "amp"
+	objects := []interface{}{
+		map[string]interface{}{
+			"kind": "Secret",
+			"metadata": map[string]interface{}{
+				"name":   "system-seed",
+				"labels": map[string]interface{}{"app": "${APP_LABEL}"},
+			},
+			"stringData": map[string]interface{}{"MASTER_ACCESS_TOKEN": "${MASTER_ACCESS_TOKEN}"},
+		},
+		map[string]interface{}{
+			"kind":     "DeploymentConfig",
+			"metadata": map[string]interface{}{"name": "system-app"},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"command": []interface{}{"deploy MASTER_ACCESS_TOKEN=\"${MASTER_ACCESS_TOKEN}\""},
+						"env": []interface{}{
+							map[string]interface{}{"name": "MYSQL_ROOT_PASSWORD", "value": "${ROOT_PASSWORD}"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "kustomize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := writeKustomizeBase(template, objects, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFiles := []string{
+		"credentials.env.example",
+		"deploymentconfig-system-app.yaml",
+		"kustomization.yaml",
+		"kustomizeconfig.yaml",
+		"system-seed.env.example",
+	}
+	fileNames := []string{}
+	for _, file := range files {
+		fileNames = append(fileNames, filepath.Base(file))
+	}
+	if !reflect.DeepEqual(fileNames, expectedFiles) {
+		t.Fatalf("expected files %v, got %v", expectedFiles, fileNames)
+	}
+
+	kustomization := readTestFile(t, dir, kustomizationFileName)
+	for _, credential := range []string{"MASTER_ACCESS_TOKEN", "ROOT_PASSWORD"} {
+		if strings.Contains(kustomization, credential+"=") {
+			t.Errorf("credential %s in kustomization:\n%s", credential, kustomization)
+		}
+	}
+	expectedGenerators := `secretGenerator:
+- name: amp-credentials
+  envs:
+  - credentials.env
+  options:
+    disableNameSuffixHash: true
+- name: system-seed
+  envs:
+  - system-seed.env
+  options:
+    disableNameSuffixHash: true
+    labels:
+      app: $(APP_LABEL)
+`
+	if !strings.Contains(kustomization, expectedGenerators) {
+		t.Errorf("expected secret generators:\n%s\ngot kustomization:\n%s", expectedGenerators, kustomization)
+	}
+
+	deploymentConfig := readTestFile(t, dir, "deploymentconfig-system-app.yaml")
+	expectedContainer := `  - command:
+    - deploy MASTER_ACCESS_TOKEN="$(MASTER_ACCESS_TOKEN)"
+    env:
+    - name: MYSQL_ROOT_PASSWORD
+      valueFrom:
+        secretKeyRef:
+          key: ROOT_PASSWORD
+          name: amp-credentials
+    - name: MASTER_ACCESS_TOKEN
+      valueFrom:
+        secretKeyRef:
+          key: MASTER_ACCESS_TOKEN
+          name: amp-credentials
+`
+	if !strings.Contains(deploymentConfig, expectedContainer) {
+		t.Errorf("expected container:\n%s\ngot deployment config:\n%s", expectedContainer, deploymentConfig)
+	}
+
+	if envFile := readTestFile(t, dir, "system-seed.env.example"); !strings.Contains(envFile, "\nMASTER_ACCESS_TOKEN=${MASTER_ACCESS_TOKEN}\n") {
+		t.Errorf("unexpected system-seed env file example:\n%s", envFile)
+	}
+}
+
+func TestWriteKustomizeBaseUnsupportedCredentialReference(t *testing.T) {
+	template := &templatev1.Template{
+		Parameters: []templatev1.Parameter{
+			{Name: "ADMIN_PASSWORD", Generate: "expression", From: "[a-z0-9]{8}"},
+		},
+	}
+	objects := []interface{}{
+		map[string]interface{}{
+			"kind":     "ConfigMap",
+			"metadata": map[string]interface{}{"name": "system"},
+			"data":     map[string]interface{}{"password": "${ADMIN_PASSWORD}"},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "kustomize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := writeKustomizeBase(template, objects, dir); err == nil {
+		t.Error("expected error on credential referenced from a ConfigMap")
+	}
+}
+
+func readTestFile(t *testing.T, dir, name string) string {
+	content, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
@@ -0,0 +1,216 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	templatev1 "github.com/openshift/api/template/v1"
+)
+
+// parameterReferenceRegexp matches the ${PARAM} and ${{PARAM}} references
+// of OpenShift Template parameters
+var parameterReferenceRegexp = regexp.MustCompile(`\$\{\{([A-Za-z0-9_]+)\}\}|\$\{([A-Za-z0-9_]+)\}`)
+
+// generateExpressionRegexp matches the [charset]{length} expressions of
+// generated parameters
+var generateExpressionRegexp = regexp.MustCompile(`^\[([^\]]+)\]\{([0-9]+)\}$`)
+
+// templateStringPart is either a literal part of a string or a reference
+// to a template parameter
+type templateStringPart struct {
+	literal string
+	// parameter is the name of the referenced parameter. Empty for
+	// literal parts
+	parameter string
+	// nonString is true for ${{PARAM}} references, whose value is not
+	// quoted as a string
+	nonString bool
+}
+
+// splitParameterReferences splits a string into literal parts and
+// references to the given parameters. References to unknown parameters are
+// kept as literals, as OpenShift Templates do
+func splitParameterReferences(str string, parameters map[string]templatev1.Parameter) []templateStringPart {
+	parts := []templateStringPart{}
+	literal := ""
+	lastIdx := 0
+	for _, match := range parameterReferenceRegexp.FindAllStringSubmatchIndex(str, -1) {
+		literal += str[lastIdx:match[0]]
+		lastIdx = match[1]
+
+		part := templateStringPart{}
+		if match[2] >= 0 {
+			part.parameter = str[match[2]:match[3]]
+			part.nonString = true
+		} else {
+			part.parameter = str[match[4]:match[5]]
+		}
+		if _, ok := parameters[part.parameter]; !ok {
+			literal += str[match[0]:match[1]]
+			continue
+		}
+
+		if literal != "" {
+			parts = append(parts, templateStringPart{literal: literal})
+			literal = ""
+		}
+		parts = append(parts, part)
+	}
+	literal += str[lastIdx:]
+	if literal != "" {
+		parts = append(parts, templateStringPart{literal: literal})
+	}
+
+	return parts
+}
+
+func hasParameterReferences(parts []templateStringPart) bool {
+	for _, part := range parts {
+		if part.parameter != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// mapStrings returns a copy of the given serialized object where each
+// string value has been replaced by the result of mapFunc. The path
+// received by mapFunc holds the map keys from the object root to the
+// value, without list indexes
+func mapStrings(value interface{}, path []string, mapFunc func(path []string, str string) interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, elem := range typedValue {
+			elemPath := append(append([]string{}, path...), key)
+			result[key] = mapStrings(elem, elemPath, mapFunc)
+		}
+		return result
+	case []interface{}:
+		result := []interface{}{}
+		for _, elem := range typedValue {
+			result = append(result, mapStrings(elem, path, mapFunc))
+		}
+		return result
+	case string:
+		return mapFunc(path, typedValue)
+	default:
+		return value
+	}
+}
+
+func templateParametersByName(template *templatev1.Template) map[string]templatev1.Parameter {
+	parameters := map[string]templatev1.Parameter{}
+	for _, parameter := range template.Parameters {
+		parameters[parameter.Name] = parameter
+	}
+	return parameters
+}
+
+// parseGenerateExpression returns the expanded charset and the length of
+// a generated parameter expression, like [a-z0-9]{8}
+func parseGenerateExpression(expression string) (string, int, error) {
+	match := generateExpressionRegexp.FindStringSubmatch(expression)
+	if match == nil {
+		return "", 0, fmt.Errorf("unsupported generate expression '%s'", expression)
+	}
+	length, err := strconv.Atoi(match[2])
+	if err != nil {
+		return "", 0, err
+	}
+
+	class := match[1]
+	charset := ""
+	for idx := 0; idx < len(class); idx++ {
+		if idx+2 < len(class) && class[idx+1] == '-' {
+			for c := class[idx]; c <= class[idx+2]; c++ {
+				charset += string(c)
+			}
+			idx += 2
+			continue
+		}
+		charset += string(class[idx])
+	}
+
+	return charset, length, nil
+}
+
+// parameterValueName returns the camel case name of a parameter, as used
+// in Helm values. For example AMP_RELEASE is ampRelease
+func parameterValueName(parameterName string) string {
+	words := strings.Split(strings.ToLower(parameterName), "_")
+	for idx := 1; idx < len(words); idx++ {
+		if words[idx] != "" {
+			words[idx] = strings.ToUpper(words[idx][:1]) + words[idx][1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// templateSecretKey is a key of a Secret of the template
+type templateSecretKey struct {
+	secret string
+	key    string
+}
+
+// generatedParameterSecretKeys returns the first Secret key whose value is
+// a generated parameter, by parameter name. objects are the serialized
+// template objects
+func generatedParameterSecretKeys(template *templatev1.Template, objects []interface{}) map[string]templateSecretKey {
+	parameters := templateParametersByName(template)
+	secretKeys := map[string]templateSecretKey{}
+	for _, object := range objects {
+		name, stringData, ok := templateSecretStringData(object)
+		if !ok {
+			continue
+		}
+		for _, key := range sortedKeys(stringData) {
+			value, _ := stringData[key].(string)
+			parts := splitParameterReferences(value, parameters)
+			if len(parts) != 1 || parts[0].parameter == "" || parameters[parts[0].parameter].Generate == "" {
+				continue
+			}
+			if _, ok := secretKeys[parts[0].parameter]; !ok {
+				secretKeys[parts[0].parameter] = templateSecretKey{secret: name, key: key}
+			}
+		}
+	}
+	return secretKeys
+}
+
+// templateSecretStringData returns the name and the stringData of a
+// serialized Secret. ok is false for other kinds of objects
+func templateSecretStringData(object interface{}) (name string, stringData map[string]interface{}, ok bool) {
+	objectFields, _ := object.(map[string]interface{})
+	if kind, _ := objectFields["kind"].(string); kind != "Secret" {
+		return "", nil, false
+	}
+	metadata, _ := objectFields["metadata"].(map[string]interface{})
+	name, _ = metadata["name"].(string)
+	stringData, _ = objectFields["stringData"].(map[string]interface{})
+	return name, stringData, true
+}
+
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
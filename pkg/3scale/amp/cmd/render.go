@@ -0,0 +1,176 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/controller/apimanager"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var renderSecretFiles []string
+var renderNamespace string
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render <apimanager file>",
+	Short: "Render the objects the operator would create for an APIManager",
+	Long: `Render the objects the operator would create for an APIManager custom resource,
+without connecting to a cluster.
+
+The APIManager defaults are set and the objects are generated with the same
+logic the operator uses. The Secrets read by the APIManager are looked up in the
+files given with --secret. Secrets not provided are generated as the operator
+would do, so their values change on each run.
+
+The objects are printed as a YAML stream in the order the operator creates them:
+the DeploymentConfigs last, by rollout phase. The DeploymentConfigs include the
+apps.3scale.net/config-hash annotation computed from the rendered Secrets and
+ConfigMaps.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRender(args[0], os.Stdout)
+	},
+}
+
+func runRender(apiManagerFile string, out io.Writer) error {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		return err
+	}
+	if err := appsv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		return err
+	}
+
+	cr, err := readAPIManager(s, apiManagerFile)
+	if err != nil {
+		return err
+	}
+	if cr.Namespace == "" {
+		cr.Namespace = renderNamespace
+	}
+	if _, err := cr.SetDefaults(); err != nil {
+		return fmt.Errorf("error setting defaults: %v", err)
+	}
+
+	secrets := []runtime.Object{}
+	for _, secretFile := range renderSecretFiles {
+		fileSecrets, err := readSecrets(secretFile, cr.Namespace)
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, fileSecrets...)
+	}
+
+	objs, err := apimanager.RenderAPIManagerObjects(k8sfake.NewFakeClientWithScheme(s, secrets...), s, cr)
+	if err != nil {
+		return err
+	}
+
+	ec := yaml.NewEncoder(out)
+	for idx := range objs {
+		serializedObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(objs[idx].Object)
+		if err != nil {
+			return err
+		}
+		if err := ec.Encode(serializedObj); err != nil {
+			return err
+		}
+	}
+
+	return ec.Close()
+}
+
+func readAPIManager(s *runtime.Scheme, fileName string) (*appsv1alpha1.APIManager, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, _, err := serializer.NewCodecFactory(s).UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %v", fileName, err)
+	}
+	cr, ok := obj.(*appsv1alpha1.APIManager)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain an APIManager", fileName)
+	}
+
+	return cr, nil
+}
+
+// readSecrets reads the Secrets of a YAML or JSON file, which can contain
+// several documents. Secrets without namespace are set the given one and
+// their stringData is merged into data, as the API server does
+func readSecrets(fileName, namespace string) ([]runtime.Object, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := []runtime.Object{}
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		secret := &v1.Secret{}
+		err := decoder.Decode(secret)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s: %v", fileName, err)
+		}
+		if secret.Kind == "" && secret.Name == "" {
+			// Empty document
+			continue
+		}
+		if secret.Kind != "Secret" {
+			return nil, fmt.Errorf("%s: object %s of kind '%s' is not a Secret", fileName, secret.Name, secret.Kind)
+		}
+		if secret.Namespace == "" {
+			secret.Namespace = namespace
+		}
+		if len(secret.StringData) > 0 {
+			if secret.Data == nil {
+				secret.Data = map[string][]byte{}
+			}
+			for key, value := range secret.StringData {
+				secret.Data[key] = []byte(value)
+			}
+			secret.StringData = nil
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+
+	renderCmd.Flags().StringArrayVar(&renderSecretFiles, "secret", []string{}, "file with the Secrets read by the APIManager. Can be repeated")
+	renderCmd.Flags().StringVarP(&renderNamespace, "namespace", "n", "default", "namespace of the APIManager when not set in its metadata")
+}
@@ -14,14 +14,17 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
+var releaseCatalogFile string
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -48,12 +51,13 @@ func Execute() {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initConfig, initReleaseCatalog)
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.generator.yaml)")
+	rootCmd.PersistentFlags().StringVar(&releaseCatalogFile, "release-catalog", "", "release catalog file whose releases override the built-in ones")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -85,3 +89,20 @@ func initConfig() {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
 	}
 }
+
+// initReleaseCatalog loads the release catalog file if set
+func initReleaseCatalog() {
+	if releaseCatalogFile == "" {
+		return
+	}
+
+	data, err := ioutil.ReadFile(releaseCatalogFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := product.LoadCatalogOverride(data); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -29,6 +29,16 @@ const (
 	componentsSeparator = "+"
 )
 
+// Output formats of the template command
+const (
+	templateOutputFormat  = "template"
+	helmOutputFormat      = "helm"
+	kustomizeOutputFormat = "kustomize"
+)
+
+var outputFormat string
+var outputDir string
+
 // templateCmd represents the template command
 var templateCmd = &cobra.Command{
 	Use:   getUsage(),
@@ -109,6 +119,15 @@ func runCommand(cmd *cobra.Command, args []string) {
 			panic("invalid component")
 		}
 	}
+	switch outputFormat {
+	case templateOutputFormat:
+	case helmOutputFormat, kustomizeOutputFormat:
+		if outputDir == "" {
+			panic("no output directory has been provided")
+		}
+	default:
+		panic("invalid output format")
+	}
 	for _, element := range availableOrderedComponents {
 		if _, ok := inputComponents[string(element)]; ok {
 			res := component.NewComponent(string(element), componentOptions)
@@ -146,14 +165,21 @@ func runCommand(cmd *cobra.Command, args []string) {
 	// being set
 	addDoubleBraceExpansionFieldsToResult(serializedResult)
 
-	// Print the results in YAML format. Cannot use the NewYAMLSerializer from the
-	// kubernetes apimachinery library because the methods to serialize
-	// require a kubernetes object, which is incompatible with having
-	// double braces expansion
-	ec := yaml.NewEncoder(os.Stdout)
-	ec.Encode(serializedResult)
-	if err != nil {
-		panic(err)
+	switch outputFormat {
+	case templateOutputFormat:
+		// Print the results in YAML format. Cannot use the NewYAMLSerializer from the
+		// kubernetes apimachinery library because the methods to serialize
+		// require a kubernetes object, which is incompatible with having
+		// double braces expansion
+		ec := yaml.NewEncoder(os.Stdout)
+		ec.Encode(serializedResult)
+		if err != nil {
+			panic(err)
+		}
+	case helmOutputFormat:
+		check(writeHelmChart(template, serializedResult["objects"].([]interface{}), outputDir))
+	case kustomizeOutputFormat:
+		check(writeKustomizeBase(template, serializedResult["objects"].([]interface{}), outputDir))
 	}
 }
 
@@ -208,6 +234,8 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// templateCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	templateCmd.Flags().StringVarP(&outputFormat, "output", "o", templateOutputFormat, "output format: template, helm or kustomize")
+	templateCmd.Flags().StringVar(&outputDir, "output-dir", "", "directory where the Helm chart or the Kustomize base is written")
 }
 
 func generateTemplate() *templatev1.Template {
@@ -3,6 +3,7 @@ package component
 import (
 	"fmt"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	imagev1 "github.com/openshift/api/image/v1"
 	templatev1 "github.com/openshift/api/template/v1"
 	v1 "k8s.io/api/core/v1"
@@ -32,6 +33,7 @@ type AmpImagesOptions struct {
 	systemRedisImage     string
 	systemMemcachedImage string
 	insecureImportPolicy bool
+	imagePullSecrets     []v1.LocalObjectReference
 }
 
 func NewAmpImages(options []string) *AmpImages {
@@ -526,48 +528,58 @@ func (ampImages *AmpImages) buildDeploymentsServiceAccount() *v1.ServiceAccount
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "amp",
 		},
-		ImagePullSecrets: []v1.LocalObjectReference{
-			v1.LocalObjectReference{
-				Name: "threescale-registry-auth"}}}
+		ImagePullSecrets: ampImages.Options.imagePullSecrets}
+}
+
+// templateImageProvider returns the images of a release of the catalog. The
+// template image parameters default to the upstream images, which the
+// productized templates replace with the ones of the latest release
+func templateImageProvider(productVersion product.Version) product.ImageProvider {
+	imageProvider, err := product.NewImageProvider(productVersion)
+	if err != nil {
+		panic(err)
+	}
+	return imageProvider
 }
 
 func (ampImages *AmpImages) buildParameters(template *templatev1.Template) {
+	imageProvider := templateImageProvider(product.ProductUpstream)
 	parameters := []templatev1.Parameter{
 		templatev1.Parameter{
 			Name:     "AMP_BACKEND_IMAGE",
 			Required: true,
-			Value:    "quay.io/3scale/apisonator:nightly",
+			Value:    imageProvider.GetBackendImage(),
 		},
 		templatev1.Parameter{
 			Name:     "AMP_ZYNC_IMAGE",
-			Value:    "quay.io/3scale/zync:nightly",
+			Value:    imageProvider.GetZyncImage(),
 			Required: true,
 		},
 		templatev1.Parameter{
 			Name:     "AMP_APICAST_IMAGE",
-			Value:    "quay.io/3scale/apicast:nightly",
+			Value:    imageProvider.GetApicastImage(),
 			Required: true,
 		},
 		templatev1.Parameter{
 			Name:     "AMP_ROUTER_IMAGE",
-			Value:    "quay.io/3scale/wildcard-router:nightly",
+			Value:    imageProvider.GetWildcardRouterImage(),
 			Required: true,
 		},
 		templatev1.Parameter{
 			Name:     "AMP_SYSTEM_IMAGE",
-			Value:    "quay.io/3scale/porta:nightly",
+			Value:    imageProvider.GetSystemImage(),
 			Required: true,
 		},
 		templatev1.Parameter{
 			Name:        "ZYNC_DATABASE_IMAGE",
 			Description: "Zync's PostgreSQL image to use",
-			Value:       "registry.access.redhat.com/rhscl/postgresql-10-rhel7",
+			Value:       imageProvider.GetZyncPostgreSQLImage(),
 			Required:    true,
 		},
 		templatev1.Parameter{
 			Name:        "MEMCACHED_IMAGE",
 			Description: "Memcached image to use",
-			Value:       "registry.access.redhat.com/3scale-amp20/memcached",
+			Value:       imageProvider.GetSystemMemcachedImage(),
 			Required:    true,
 		},
 		templatev1.Parameter{
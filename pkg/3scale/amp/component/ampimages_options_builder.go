@@ -1,6 +1,10 @@
 package component
 
-import "fmt"
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
 
 type AmpImagesOptionsBuilder struct {
 	options AmpImagesOptions
@@ -54,6 +58,12 @@ func (ampImages *AmpImagesOptionsBuilder) InsecureImportPolicy(insecureImportPol
 	ampImages.options.insecureImportPolicy = insecureImportPolicy
 }
 
+// ImagePullSecret adds a secret to the image pull secrets of the
+// deployments service account
+func (ampImages *AmpImagesOptionsBuilder) ImagePullSecret(name string) {
+	ampImages.options.imagePullSecrets = append(ampImages.options.imagePullSecrets, v1.LocalObjectReference{Name: name})
+}
+
 func (ampImages *AmpImagesOptionsBuilder) Build() (*AmpImagesOptions, error) {
 	if ampImages.options.appLabel == "" {
 		return nil, fmt.Errorf("no AppLabel has been provided")
@@ -89,5 +99,18 @@ func (ampImages *AmpImagesOptionsBuilder) Build() (*AmpImagesOptions, error) {
 		return nil, fmt.Errorf("no System Memcached image has been provided")
 	}
 
+	ampImages.setNonRequiredOptions()
+
 	return &ampImages.options, nil
 }
+
+func (ampImages *AmpImagesOptionsBuilder) setNonRequiredOptions() {
+	defaultImagePullSecret := v1.LocalObjectReference{Name: "threescale-registry-auth"}
+
+	for _, secret := range ampImages.options.imagePullSecrets {
+		if secret.Name == defaultImagePullSecret.Name {
+			return
+		}
+	}
+	ampImages.options.imagePullSecrets = append([]v1.LocalObjectReference{defaultImagePullSecret}, ampImages.options.imagePullSecrets...)
+}
@@ -148,7 +148,7 @@ func (apicast *Apicast) buildApicastStagingRoute() *routev1.Route {
 			APIVersion: "route.openshift.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   "api-apicast-staging",
+			Name:   ApicastStagingRouteName,
 			Labels: map[string]string{"app": apicast.Options.appLabel, "threescale_component": "apicast", "threescale_component_element": "staging"},
 		},
 		Spec: routev1.RouteSpec{
@@ -208,7 +208,7 @@ func (apicast *Apicast) buildApicastProductionRoute() *routev1.Route {
 			APIVersion: "route.openshift.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   "api-apicast-production",
+			Name:   ApicastProductionRouteName,
 			Labels: map[string]string{"app": apicast.Options.appLabel, "threescale_component": "apicast", "threescale_component_element": "production"},
 		},
 		Spec: routev1.RouteSpec{
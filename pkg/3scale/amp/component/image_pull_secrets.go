@@ -0,0 +1,47 @@
+package component
+
+import (
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ImagePullSecrets adds image pull secrets to the pods of every
+// DeploymentConfig built by the other components
+type ImagePullSecrets struct {
+	Options *ImagePullSecretsOptions
+}
+
+type ImagePullSecretsOptions struct {
+	imagePullSecretsRequiredOptions
+}
+
+type imagePullSecretsRequiredOptions struct {
+	imagePullSecrets []v1.LocalObjectReference
+}
+
+func (i *ImagePullSecrets) PostProcessObjects(objects []runtime.RawExtension) []runtime.RawExtension {
+	for rawExtIdx := range objects {
+		dc, ok := objects[rawExtIdx].Object.(*appsv1.DeploymentConfig)
+		if !ok {
+			continue
+		}
+		podSpec := &dc.Spec.Template.Spec
+		for _, secret := range i.Options.imagePullSecrets {
+			if !hasLocalObjectReference(podSpec.ImagePullSecrets, secret.Name) {
+				podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, secret)
+			}
+		}
+	}
+
+	return objects
+}
+
+func hasLocalObjectReference(references []v1.LocalObjectReference, name string) bool {
+	for _, reference := range references {
+		if reference.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package component
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+type ImagePullSecretsOptionsBuilder struct {
+	options ImagePullSecretsOptions
+}
+
+func (i *ImagePullSecretsOptionsBuilder) ImagePullSecret(name string) {
+	i.options.imagePullSecrets = append(i.options.imagePullSecrets, v1.LocalObjectReference{Name: name})
+}
+
+func (i *ImagePullSecretsOptionsBuilder) Build() (*ImagePullSecretsOptions, error) {
+	err := i.setRequiredOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return &i.options, nil
+}
+
+func (i *ImagePullSecretsOptionsBuilder) setRequiredOptions() error {
+	if len(i.options.imagePullSecrets) == 0 {
+		return fmt.Errorf("no image pull secrets have been provided")
+	}
+	for _, secret := range i.options.imagePullSecrets {
+		if secret.Name == "" {
+			return fmt.Errorf("image pull secrets must have a name")
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package component
 import (
 	"fmt"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	imagev1 "github.com/openshift/api/image/v1"
 	templatev1 "github.com/openshift/api/template/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -79,19 +80,20 @@ func (productized *Productized) PostProcessObjects(objects []runtime.RawExtensio
 }
 
 func (productized *Productized) updateAmpImagesParameters(template *templatev1.Template) {
+	imageProvider := templateImageProvider(product.LatestRelease())
 	for paramIdx := range template.Parameters {
 		param := &template.Parameters[paramIdx]
 		switch param.Name {
 		case "AMP_SYSTEM_IMAGE":
-			param.Value = "registry.access.redhat.com/3scale-amp25/system"
+			param.Value = imageProvider.GetSystemImage()
 		case "AMP_BACKEND_IMAGE":
-			param.Value = "registry.access.redhat.com/3scale-amp25/backend"
+			param.Value = imageProvider.GetBackendImage()
 		case "AMP_APICAST_IMAGE":
-			param.Value = "registry.access.redhat.com/3scale-amp25/apicast-gateway"
+			param.Value = imageProvider.GetApicastImage()
 		case "AMP_ROUTER_IMAGE":
-			param.Value = "registry.access.redhat.com/3scale-amp22/wildcard-router"
+			param.Value = imageProvider.GetWildcardRouterImage()
 		case "AMP_ZYNC_IMAGE":
-			param.Value = "registry.access.redhat.com/3scale-amp25/zync"
+			param.Value = imageProvider.GetZyncImage()
 		}
 	}
 }
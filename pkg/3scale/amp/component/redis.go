@@ -3,6 +3,7 @@ package component
 import (
 	"fmt"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	appsv1 "github.com/openshift/api/apps/v1"
 	templatev1 "github.com/openshift/api/template/v1"
 	v1 "k8s.io/api/core/v1"
@@ -96,7 +97,7 @@ func (redis *Redis) buildParameters(template *templatev1.Template) {
 			Name:        "REDIS_IMAGE",
 			Description: "Redis image to use",
 			Required:    true,
-			Value:       "registry.access.redhat.com/rhscl/redis-32-rhel7:3.2",
+			Value:       templateImageProvider(product.ProductUpstream).GetBackendRedisImage(),
 		},
 	}
 	template.Parameters = append(template.Parameters, parameters...)
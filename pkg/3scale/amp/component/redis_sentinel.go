@@ -0,0 +1,450 @@
+package component
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	redisSentinelObjectMetaName      = "redis-sentinel"
+	redisSentinelScriptsName         = "redis-sentinel-scripts"
+	redisSentinelScriptsMountPath    = "/opt/redis-sentinel"
+	redisSentinelRedisStartScript    = "redis-start.sh"
+	redisSentinelSentinelStartScript = "sentinel-start.sh"
+	redisSentinelPort                = 26379
+	redisPort                        = 6379
+	systemRedisObjectMetaName        = "system-redis"
+	redisReplicaSuffix               = "-replica"
+	redisPrimarySuffix               = "-primary"
+	redisDefaultBinDir               = "/opt/rh/rh-redis32/root/usr/bin"
+)
+
+// redisSentinelGroups are the Redis DeploymentConfigs monitored by the
+// deployed Sentinels. Their names are used as Sentinel group names
+var redisSentinelGroups = []string{backendRedisObjectMetaName, systemRedisObjectMetaName}
+
+// RedisSentinel points backend and system to Sentinel-managed Redis.
+// When deployed, the backend and system Redis become the initial primaries
+// of primary/replica sets monitored by Sentinels inside the cluster.
+// Otherwise the internal Redis objects are removed in favor of the external
+// Sentinels
+type RedisSentinel struct {
+	Options *RedisSentinelOptions
+}
+
+type RedisSentinelOptions struct {
+	redisSentinelNonRequiredOptions
+	redisSentinelRequiredOptions
+}
+
+type redisSentinelRequiredOptions struct {
+	appLabel      string
+	deployed      bool
+	replicas      int32
+	sentinels     int32
+	sentinelHosts string
+}
+
+type redisSentinelNonRequiredOptions struct {
+	sentinelRole             *string
+	backendStorageMasterName *string
+	backendQueuesMasterName  *string
+	systemMasterName         *string
+}
+
+func (r *RedisSentinel) PostProcessObjects(objects []runtime.RawExtension) []runtime.RawExtension {
+	res := objects
+	if r.Options.deployed {
+		res = r.addSentinelObjects(res)
+	} else {
+		res = r.deleteInternalRedisObjects(res)
+	}
+	r.updateRedisSecrets(res)
+
+	return res
+}
+
+func (r *RedisSentinel) updateRedisSecrets(objects []runtime.RawExtension) {
+	for rawExtIdx := range objects {
+		secret, ok := objects[rawExtIdx].Object.(*v1.Secret)
+		if !ok {
+			continue
+		}
+		switch secret.Name {
+		case BackendSecretBackendRedisSecretName:
+			secret.StringData[BackendSecretBackendRedisStorageURLFieldName] = fmt.Sprintf("redis://%s/0", *r.Options.backendStorageMasterName)
+			secret.StringData[BackendSecretBackendRedisQueuesURLFieldName] = fmt.Sprintf("redis://%s/1", *r.Options.backendQueuesMasterName)
+			secret.StringData[BackendSecretBackendRedisStorageSentinelHostsFieldName] = r.Options.sentinelHosts
+			secret.StringData[BackendSecretBackendRedisStorageSentinelRoleFieldName] = *r.Options.sentinelRole
+			secret.StringData[BackendSecretBackendRedisQueuesSentinelHostsFieldName] = r.Options.sentinelHosts
+			secret.StringData[BackendSecretBackendRedisQueuesSentinelRoleFieldName] = *r.Options.sentinelRole
+		case SystemSecretSystemRedisSecretName:
+			// The message bus uses the system Redis when its URL is empty
+			secret.StringData[SystemSecretSystemRedisURLFieldName] = fmt.Sprintf("redis://%s/1", *r.Options.systemMasterName)
+			secret.StringData[SystemSecretSystemRedisSentinelHosts] = r.Options.sentinelHosts
+			secret.StringData[SystemSecretSystemRedisSentinelRole] = *r.Options.sentinelRole
+			secret.StringData[SystemSecretSystemRedisMessageBusRedisURLFieldName] = ""
+			secret.StringData[SystemSecretSystemRedisMessageBusSentinelHosts] = ""
+			secret.StringData[SystemSecretSystemRedisMessageBusSentinelRole] = ""
+		}
+	}
+}
+
+func (r *RedisSentinel) deleteInternalRedisObjects(objects []runtime.RawExtension) []runtime.RawExtension {
+	internalRedisObjects := map[string]bool{
+		backendRedisObjectMetaName:    true,
+		systemRedisObjectMetaName:     true,
+		backendRedisStorageVolumeName: true,
+		"system-redis-storage":        true,
+		backendRedisConfigVolumeName:  true,
+	}
+
+	keepObjects := []runtime.RawExtension{}
+	for rawExtIdx := range objects {
+		objectMeta, ok := objects[rawExtIdx].Object.(metav1.Object)
+		if ok && internalRedisObjects[objectMeta.GetName()] {
+			switch objects[rawExtIdx].Object.(type) {
+			case *appsv1.DeploymentConfig, *v1.Service, *v1.PersistentVolumeClaim, *v1.ConfigMap:
+				continue
+			}
+		}
+		keepObjects = append(keepObjects, objects[rawExtIdx])
+	}
+
+	return keepObjects
+}
+
+func (r *RedisSentinel) addSentinelObjects(objects []runtime.RawExtension) []runtime.RawExtension {
+	res := objects
+	var sentinelImageSource *appsv1.DeploymentConfig
+	for _, group := range redisSentinelGroups {
+		primary := findDeploymentConfig(res, group)
+		if primary == nil {
+			continue
+		}
+		if sentinelImageSource == nil {
+			sentinelImageSource = primary
+		}
+
+		replica := r.buildReplicaDeploymentConfig(primary)
+		r.runRedisStartScript(primary, "")
+		r.runRedisStartScript(replica, group+redisPrimarySuffix)
+
+		res = append(res,
+			runtime.RawExtension{Object: replica},
+			runtime.RawExtension{Object: r.buildPrimaryService(primary)},
+		)
+	}
+
+	if sentinelImageSource == nil {
+		return res
+	}
+
+	res = append(res,
+		runtime.RawExtension{Object: r.buildScriptsConfigMap()},
+		runtime.RawExtension{Object: r.buildSentinelService()},
+		runtime.RawExtension{Object: r.buildSentinelDeploymentConfig(sentinelImageSource)},
+	)
+	return res
+}
+
+func findDeploymentConfig(objects []runtime.RawExtension, name string) *appsv1.DeploymentConfig {
+	for rawExtIdx := range objects {
+		dc, ok := objects[rawExtIdx].Object.(*appsv1.DeploymentConfig)
+		if ok && dc.Name == name {
+			return dc
+		}
+	}
+	return nil
+}
+
+// buildReplicaDeploymentConfig copies the primary DeploymentConfig. Replicas
+// store their data in an emptyDir because they resynchronize from the primary
+func (r *RedisSentinel) buildReplicaDeploymentConfig(primary *appsv1.DeploymentConfig) *appsv1.DeploymentConfig {
+	replica := primary.DeepCopy()
+	name := primary.Name + redisReplicaSuffix
+
+	replica.Name = name
+	replica.Spec.Replicas = r.Options.replicas
+	replica.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.DeploymentStrategyTypeRolling}
+	replica.Spec.Selector = map[string]string{"deploymentConfig": name}
+	replica.Spec.Template.Labels["deploymentConfig"] = name
+
+	for idx := range replica.Spec.Template.Spec.Volumes {
+		volume := &replica.Spec.Template.Spec.Volumes[idx]
+		if volume.PersistentVolumeClaim != nil {
+			volume.VolumeSource = v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}
+		}
+	}
+
+	return replica
+}
+
+// runRedisStartScript makes the Redis container start as a replica of the
+// primary known by the Sentinels, or of fallbackPrimary when the Sentinels
+// do not know it yet. An empty fallbackPrimary starts a primary
+func (r *RedisSentinel) runRedisStartScript(dc *appsv1.DeploymentConfig, fallbackPrimary string) {
+	podSpec := &dc.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, r.buildScriptsVolume())
+
+	container := &podSpec.Containers[0]
+	container.Env = append(container.Env,
+		envVarFromValue("REDIS_BIN_DIR", redisBinDir(container)),
+		envVarFromValue("REDIS_SENTINEL_HOST", redisSentinelObjectMetaName),
+	)
+	container.Command = []string{"/bin/bash", path.Join(redisSentinelScriptsMountPath, redisSentinelRedisStartScript)}
+	container.Args = []string{sentinelGroupName(dc.Name), fallbackPrimary}
+	container.VolumeMounts = append(container.VolumeMounts, r.buildScriptsVolumeMount())
+	// After a failover any pod can be a read-only replica
+	container.ReadinessProbe = &v1.Probe{
+		Handler: v1.Handler{
+			Exec: &v1.ExecAction{
+				Command: []string{"container-entrypoint", "bash", "-c", "redis-cli ping | grep PONG"},
+			},
+		},
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      5,
+	}
+}
+
+func sentinelGroupName(dcName string) string {
+	for _, group := range redisSentinelGroups {
+		if dcName == group || dcName == group+redisReplicaSuffix {
+			return group
+		}
+	}
+	return dcName
+}
+
+func redisBinDir(container *v1.Container) string {
+	if len(container.Command) > 0 && path.IsAbs(container.Command[0]) {
+		return path.Dir(container.Command[0])
+	}
+	return redisDefaultBinDir
+}
+
+// buildPrimaryService is a headless Service resolving to the pod of the
+// initial primary, which Sentinels monitor by IP address
+func (r *RedisSentinel) buildPrimaryService(primary *appsv1.DeploymentConfig) *v1.Service {
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   primary.Name + redisPrimarySuffix,
+			Labels: primary.Labels,
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP: v1.ClusterIPNone,
+			Ports: []v1.ServicePort{
+				v1.ServicePort{
+					Port:       redisPort,
+					TargetPort: intstr.FromInt(redisPort),
+					Protocol:   v1.ProtocolTCP,
+				},
+			},
+			Selector: map[string]string{"deploymentConfig": primary.Name},
+		},
+	}
+}
+
+func (r *RedisSentinel) buildSentinelLabels() map[string]string {
+	return map[string]string{
+		"app":                          r.Options.appLabel,
+		"threescale_component":         "redis",
+		"threescale_component_element": "sentinel",
+	}
+}
+
+func (r *RedisSentinel) buildSentinelService() *v1.Service {
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   redisSentinelObjectMetaName,
+			Labels: r.buildSentinelLabels(),
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				v1.ServicePort{
+					Port:       redisSentinelPort,
+					TargetPort: intstr.FromInt(redisSentinelPort),
+					Protocol:   v1.ProtocolTCP,
+				},
+			},
+			Selector: map[string]string{"deploymentConfig": redisSentinelObjectMetaName},
+		},
+	}
+}
+
+// buildSentinelDeploymentConfig runs the Sentinels with the Redis image
+// of the given DeploymentConfig
+func (r *RedisSentinel) buildSentinelDeploymentConfig(imageSource *appsv1.DeploymentConfig) *appsv1.DeploymentConfig {
+	sourceContainer := imageSource.Spec.Template.Spec.Containers[0]
+	podLabels := r.buildSentinelLabels()
+	podLabels["deploymentConfig"] = redisSentinelObjectMetaName
+
+	triggers := appsv1.DeploymentTriggerPolicies{
+		appsv1.DeploymentTriggerPolicy{Type: appsv1.DeploymentTriggerOnConfigChange},
+	}
+	for _, trigger := range imageSource.Spec.Triggers {
+		if trigger.Type == appsv1.DeploymentTriggerOnImageChange && trigger.ImageChangeParams != nil {
+			imageTrigger := *trigger.DeepCopy()
+			imageTrigger.ImageChangeParams.ContainerNames = []string{redisSentinelObjectMetaName}
+			triggers = append(triggers, imageTrigger)
+		}
+	}
+
+	return &appsv1.DeploymentConfig{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DeploymentConfig",
+			APIVersion: "apps.openshift.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   redisSentinelObjectMetaName,
+			Labels: r.buildSentinelLabels(),
+		},
+		Spec: appsv1.DeploymentConfigSpec{
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.DeploymentStrategyTypeRolling},
+			Replicas: r.Options.sentinels,
+			Selector: map[string]string{"deploymentConfig": redisSentinelObjectMetaName},
+			Triggers: triggers,
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: v1.PodSpec{
+					ServiceAccountName: imageSource.Spec.Template.Spec.ServiceAccountName,
+					Volumes:            []v1.Volume{r.buildScriptsVolume()},
+					Containers: []v1.Container{
+						v1.Container{
+							Name:            redisSentinelObjectMetaName,
+							Image:           sourceContainer.Image,
+							ImagePullPolicy: v1.PullIfNotPresent,
+							Command:         []string{"/bin/bash", path.Join(redisSentinelScriptsMountPath, redisSentinelSentinelStartScript)},
+							Args:            redisSentinelGroups,
+							Env: []v1.EnvVar{
+								envVarFromValue("REDIS_BIN_DIR", redisBinDir(&sourceContainer)),
+								envVarFromValue("REDIS_SENTINEL_HOST", redisSentinelObjectMetaName),
+								envVarFromValue("REDIS_SENTINEL_QUORUM", strconv.Itoa(int(r.Options.sentinels/2+1))),
+							},
+							Ports: []v1.ContainerPort{
+								v1.ContainerPort{ContainerPort: redisSentinelPort, Protocol: v1.ProtocolTCP},
+							},
+							VolumeMounts: []v1.VolumeMount{r.buildScriptsVolumeMount()},
+							ReadinessProbe: &v1.Probe{
+								Handler: v1.Handler{
+									Exec: &v1.ExecAction{
+										Command: []string{"container-entrypoint", "bash", "-c", fmt.Sprintf("redis-cli -p %d ping | grep PONG", redisSentinelPort)},
+									},
+								},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       10,
+								TimeoutSeconds:      5,
+							},
+							LivenessProbe: &v1.Probe{
+								Handler: v1.Handler{
+									TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(redisSentinelPort)},
+								},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       10,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *RedisSentinel) buildScriptsVolume() v1.Volume {
+	defaultMode := int32(0755)
+	return v1.Volume{
+		Name: redisSentinelScriptsName,
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: redisSentinelScriptsName},
+				DefaultMode:          &defaultMode,
+			},
+		},
+	}
+}
+
+func (r *RedisSentinel) buildScriptsVolumeMount() v1.VolumeMount {
+	return v1.VolumeMount{
+		Name:      redisSentinelScriptsName,
+		MountPath: redisSentinelScriptsMountPath,
+		ReadOnly:  true,
+	}
+}
+
+func (r *RedisSentinel) buildScriptsConfigMap() *v1.ConfigMap {
+	return &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   redisSentinelScriptsName,
+			Labels: r.buildSentinelLabels(),
+		},
+		Data: map[string]string{
+			redisSentinelRedisStartScript:    redisStartScript,
+			redisSentinelSentinelStartScript: sentinelStartScript,
+		},
+	}
+}
+
+const redisStartScript = `#!/bin/bash
+# Usage: redis-start.sh GROUP [FALLBACK_PRIMARY]
+# Starts a Redis of the GROUP Sentinel group. It replicates the primary known
+# by the Sentinels, or FALLBACK_PRIMARY when the Sentinels do not know it yet.
+# Without both, it starts as the primary.
+group="$1"
+primary="$2"
+
+current=$("${REDIS_BIN_DIR}/redis-cli" -h "${REDIS_SENTINEL_HOST}" -p 26379 --raw sentinel get-master-addr-by-name "${group}" 2>/dev/null | head -n 1)
+if [ -n "${current}" ]; then
+  primary="${current}"
+fi
+
+args=(/etc/redis.d/redis.conf --daemonize no)
+if [ -n "${primary}" ] && [ "${primary}" != "$(hostname -i)" ]; then
+  args+=(--slaveof "${primary}" 6379)
+fi
+exec "${REDIS_BIN_DIR}/redis-server" "${args[@]}"
+`
+
+const sentinelStartScript = `#!/bin/bash
+# Usage: sentinel-start.sh GROUP...
+# Starts a Sentinel monitoring the primary of every GROUP. The primaries are
+# taken from the running Sentinels or, on first start, from the headless
+# GROUP-primary Services.
+conf=/tmp/sentinel.conf
+echo "port 26379" > "${conf}"
+
+for group in "$@"; do
+  primary=$("${REDIS_BIN_DIR}/redis-cli" -h "${REDIS_SENTINEL_HOST}" -p 26379 --raw sentinel get-master-addr-by-name "${group}" 2>/dev/null | head -n 1)
+  until [ -n "${primary}" ]; do
+    primary=$(getent hosts "${group}-primary" | awk '{ print $1 }')
+    [ -n "${primary}" ] || sleep 2
+  done
+  cat >> "${conf}" <<EOF
+sentinel monitor ${group} ${primary} 6379 ${REDIS_SENTINEL_QUORUM}
+sentinel down-after-milliseconds ${group} 5000
+sentinel failover-timeout ${group} 60000
+sentinel parallel-syncs ${group} 1
+EOF
+done
+
+exec "${REDIS_BIN_DIR}/redis-server" "${conf}" --sentinel
+`
@@ -0,0 +1,89 @@
+package component
+
+import "fmt"
+
+type RedisSentinelOptionsBuilder struct {
+	options RedisSentinelOptions
+}
+
+func (r *RedisSentinelOptionsBuilder) AppLabel(appLabel string) {
+	r.options.appLabel = appLabel
+}
+
+// Deployed deploys the Redis replicas and Sentinels inside the cluster
+func (r *RedisSentinelOptionsBuilder) Deployed(replicas, sentinels int32) {
+	r.options.deployed = true
+	r.options.replicas = replicas
+	r.options.sentinels = sentinels
+}
+
+func (r *RedisSentinelOptionsBuilder) SentinelHosts(hosts string) {
+	r.options.sentinelHosts = hosts
+}
+
+func (r *RedisSentinelOptionsBuilder) SentinelRole(role string) {
+	r.options.sentinelRole = &role
+}
+
+func (r *RedisSentinelOptionsBuilder) BackendStorageMasterName(name string) {
+	r.options.backendStorageMasterName = &name
+}
+
+func (r *RedisSentinelOptionsBuilder) BackendQueuesMasterName(name string) {
+	r.options.backendQueuesMasterName = &name
+}
+
+func (r *RedisSentinelOptionsBuilder) SystemMasterName(name string) {
+	r.options.systemMasterName = &name
+}
+
+func (r *RedisSentinelOptionsBuilder) Build() (*RedisSentinelOptions, error) {
+	err := r.setRequiredOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	r.setNonRequiredOptions()
+
+	return &r.options, nil
+}
+
+func (r *RedisSentinelOptionsBuilder) setRequiredOptions() error {
+	if r.options.appLabel == "" {
+		return fmt.Errorf("no AppLabel has been provided")
+	}
+	if r.options.deployed {
+		if r.options.replicas < 1 {
+			return fmt.Errorf("at least one Redis replica is required")
+		}
+		if r.options.sentinels < 1 {
+			return fmt.Errorf("at least one Redis Sentinel is required")
+		}
+	} else if r.options.sentinelHosts == "" {
+		return fmt.Errorf("no Redis Sentinel hosts have been provided")
+	}
+
+	return nil
+}
+
+func (r *RedisSentinelOptionsBuilder) setNonRequiredOptions() {
+	defaultSentinelRole := "master"
+	defaultBackendMasterName := backendRedisObjectMetaName
+	defaultSystemMasterName := systemRedisObjectMetaName
+
+	if r.options.deployed {
+		r.options.sentinelHosts = fmt.Sprintf("redis://%s:%d", redisSentinelObjectMetaName, redisSentinelPort)
+	}
+	if r.options.sentinelRole == nil {
+		r.options.sentinelRole = &defaultSentinelRole
+	}
+	if r.options.backendStorageMasterName == nil {
+		r.options.backendStorageMasterName = &defaultBackendMasterName
+	}
+	if r.options.backendQueuesMasterName == nil {
+		r.options.backendQueuesMasterName = &defaultBackendMasterName
+	}
+	if r.options.systemMasterName == nil {
+		r.options.systemMasterName = &defaultSystemMasterName
+	}
+}
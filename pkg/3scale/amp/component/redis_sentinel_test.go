@@ -0,0 +1,108 @@
+package component
+
+import (
+	"testing"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func testRedisObjects(t *testing.T) []runtime.RawExtension {
+	redisOpts, err := (&CLIRedisOptionsProvider{}).GetRedisOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects, err := (&Redis{Options: redisOpts}).GetObjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{BackendSecretBackendRedisSecretName, SystemSecretSystemRedisSecretName} {
+		objects = append(objects, runtime.RawExtension{Object: &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			StringData: map[string]string{},
+		}})
+	}
+	return objects
+}
+
+func findTestSecret(t *testing.T, objects []runtime.RawExtension, name string) *v1.Secret {
+	for idx := range objects {
+		if secret, ok := objects[idx].Object.(*v1.Secret); ok && secret.Name == name {
+			return secret
+		}
+	}
+	t.Fatalf("secret %s not found", name)
+	return nil
+}
+
+func TestRedisSentinelDeployed(t *testing.T) {
+	b := RedisSentinelOptionsBuilder{}
+	b.AppLabel("3scale-api-management")
+	b.Deployed(2, 3)
+	opts, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects := (&RedisSentinel{Options: opts}).PostProcessObjects(testRedisObjects(t))
+
+	for _, name := range []string{"backend-redis", "backend-redis-replica", "system-redis", "system-redis-replica", "redis-sentinel"} {
+		dc := findDeploymentConfig(objects, name)
+		if dc == nil {
+			t.Fatalf("DeploymentConfig %s not found", name)
+		}
+		for _, volume := range dc.Spec.Template.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && name != "backend-redis" && name != "system-redis" {
+				t.Errorf("DeploymentConfig %s uses PersistentVolumeClaim %s", name, volume.PersistentVolumeClaim.ClaimName)
+			}
+		}
+	}
+	if replicas := findDeploymentConfig(objects, "system-redis-replica").Spec.Replicas; replicas != 2 {
+		t.Errorf("expected 2 system-redis replicas, got %d", replicas)
+	}
+	if sentinels := findDeploymentConfig(objects, "redis-sentinel").Spec.Replicas; sentinels != 3 {
+		t.Errorf("expected 3 sentinels, got %d", sentinels)
+	}
+
+	backendSecret := findTestSecret(t, objects, BackendSecretBackendRedisSecretName)
+	if url := backendSecret.StringData[BackendSecretBackendRedisStorageURLFieldName]; url != "redis://backend-redis/0" {
+		t.Errorf("unexpected backend storage URL %s", url)
+	}
+	if hosts := backendSecret.StringData[BackendSecretBackendRedisQueuesSentinelHostsFieldName]; hosts != "redis://redis-sentinel:26379" {
+		t.Errorf("unexpected backend queues sentinel hosts %s", hosts)
+	}
+	systemSecret := findTestSecret(t, objects, SystemSecretSystemRedisSecretName)
+	if role := systemSecret.StringData[SystemSecretSystemRedisSentinelRole]; role != "master" {
+		t.Errorf("unexpected system sentinel role %s", role)
+	}
+}
+
+func TestRedisSentinelExternal(t *testing.T) {
+	b := RedisSentinelOptionsBuilder{}
+	b.AppLabel("3scale-api-management")
+	b.SentinelHosts("redis://sentinel-0:26379,redis://sentinel-1:26379")
+	b.SystemMasterName("system")
+	opts, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects := (&RedisSentinel{Options: opts}).PostProcessObjects(testRedisObjects(t))
+
+	for idx := range objects {
+		switch obj := objects[idx].Object.(type) {
+		case *appsv1.DeploymentConfig, *v1.Service, *v1.PersistentVolumeClaim, *v1.ConfigMap:
+			t.Errorf("internal Redis object %s not removed", obj.(metav1.Object).GetName())
+		}
+	}
+
+	systemSecret := findTestSecret(t, objects, SystemSecretSystemRedisSecretName)
+	if url := systemSecret.StringData[SystemSecretSystemRedisURLFieldName]; url != "redis://system/1" {
+		t.Errorf("unexpected system URL %s", url)
+	}
+	if hosts := systemSecret.StringData[SystemSecretSystemRedisSentinelHosts]; hosts != "redis://sentinel-0:26379,redis://sentinel-1:26379" {
+		t.Errorf("unexpected system sentinel hosts %s", hosts)
+	}
+}
@@ -0,0 +1,77 @@
+package component
+
+import (
+	routev1 "github.com/openshift/api/route/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	SystemMasterRouteName      = "system-master"
+	SystemProviderRouteName    = "system-provider-admin"
+	SystemDeveloperRouteName   = "system-developer"
+	ApicastStagingRouteName    = "api-apicast-staging"
+	ApicastProductionRouteName = "api-apicast-production"
+)
+
+// ConfiguredRouteAnnotation marks the Routes whose host or TLS settings are
+// configured in the APIManager, so they are reset to the defaults once the
+// configuration is removed
+const ConfiguredRouteAnnotation = "apps.3scale.net/configured-route"
+
+// Routes overrides the host and the TLS termination of the Routes built by
+// the other components
+type Routes struct {
+	Options *RoutesOptions
+}
+
+type RoutesOptions struct {
+	routesNonRequiredOptions
+}
+
+type routesNonRequiredOptions struct {
+	routes map[string]RouteOptions
+}
+
+// RouteOptions are the configurable fields of a Route. Unset fields keep
+// the component defaults. The certificates and keys are PEM encoded
+type RouteOptions struct {
+	Host          *string
+	Certificate   string
+	Key           string
+	CACertificate string
+}
+
+func (r *Routes) PostProcessObjects(objects []runtime.RawExtension) []runtime.RawExtension {
+	for rawExtIdx := range objects {
+		route, ok := objects[rawExtIdx].Object.(*routev1.Route)
+		if !ok {
+			continue
+		}
+		if routeOptions, ok := r.Options.routes[route.Name]; ok {
+			r.applyRouteOptions(route, routeOptions)
+		}
+	}
+
+	return objects
+}
+
+func (r *Routes) applyRouteOptions(route *routev1.Route, routeOptions RouteOptions) {
+	if route.Annotations == nil {
+		route.Annotations = map[string]string{}
+	}
+	route.Annotations[ConfiguredRouteAnnotation] = "true"
+
+	if routeOptions.Host != nil {
+		route.Spec.Host = *routeOptions.Host
+	}
+
+	if route.Spec.TLS == nil {
+		route.Spec.TLS = &routev1.TLSConfig{
+			Termination:                   routev1.TLSTerminationEdge,
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyAllow,
+		}
+	}
+	route.Spec.TLS.Certificate = routeOptions.Certificate
+	route.Spec.TLS.Key = routeOptions.Key
+	route.Spec.TLS.CACertificate = routeOptions.CACertificate
+}
@@ -0,0 +1,24 @@
+package component
+
+type RoutesOptionsBuilder struct {
+	options RoutesOptions
+}
+
+func (r *RoutesOptionsBuilder) Route(routeName string, routeOptions RouteOptions) {
+	if r.options.routes == nil {
+		r.options.routes = map[string]RouteOptions{}
+	}
+	r.options.routes[routeName] = routeOptions
+}
+
+func (r *RoutesOptionsBuilder) Build() (*RoutesOptions, error) {
+	r.setNonRequiredOptions()
+
+	return &r.options, nil
+}
+
+func (r *RoutesOptionsBuilder) setNonRequiredOptions() {
+	if r.options.routes == nil {
+		r.options.routes = map[string]RouteOptions{}
+	}
+}
@@ -13,6 +13,12 @@ import (
 const (
 	S3SecretAWSAccessKeyIdFieldName     = "AWS_ACCESS_KEY_ID"
 	S3SecretAWSSecretAccessKeyFieldName = "AWS_SECRET_ACCESS_KEY"
+	S3CACertificateSecretFieldName      = "ca.crt"
+)
+
+const (
+	s3CACertificateVolumeName = "s3-ca-certificate"
+	s3CACertificateMountPath  = "/etc/pki/3scale/s3"
 )
 
 type S3 struct {
@@ -35,6 +41,11 @@ type s3RequiredOptions struct {
 }
 
 type s3NonRequiredOptions struct {
+	awsHostname             *string
+	awsProtocol             *string
+	awsPathStyle            *string
+	awsServerSideEncryption *string
+	caCertificateSecret     *string
 }
 
 func NewS3(options []string) *S3 {
@@ -58,6 +69,10 @@ func (o *CLIS3OptionsProvider) GetS3Options() (*S3Options, error) {
 	sob.AwsBucket("${AWS_BUCKET}")
 	sob.FileUploadStorage("${FILE_UPLOAD_STORAGE}")
 	sob.AWSCredentialsSecret("aws-auth")
+	sob.AwsHostname("${AWS_HOSTNAME}")
+	sob.AwsProtocol("${AWS_PROTOCOL}")
+	sob.AwsPathStyle("${AWS_PATH_STYLE}")
+	sob.AwsServerSideEncryption("${AWS_SERVER_SIDE_ENCRYPTION}")
 	res, err := sob.Build()
 	if err != nil {
 		return nil, fmt.Errorf("unable to create S3 Options - %s", err)
@@ -160,6 +175,10 @@ func (s3 *S3) getNewCfgMapElements() []v1.EnvVar {
 		envVarFromSecret("AWS_SECRET_ACCESS_KEY", s3.Options.awsCredentialsSecret, S3SecretAWSSecretAccessKeyFieldName),
 		envVarFromConfigMap("AWS_BUCKET", "system-environment", "AWS_BUCKET"),
 		envVarFromConfigMap("AWS_REGION", "system-environment", "AWS_REGION"),
+		envVarFromConfigMap("AWS_HOSTNAME", "system-environment", "AWS_HOSTNAME"),
+		envVarFromConfigMap("AWS_PROTOCOL", "system-environment", "AWS_PROTOCOL"),
+		envVarFromConfigMap("AWS_PATH_STYLE", "system-environment", "AWS_PATH_STYLE"),
+		envVarFromConfigMap("AWS_SERVER_SIDE_ENCRYPTION", "system-environment", "AWS_SERVER_SIDE_ENCRYPTION"),
 	}
 }
 
@@ -200,6 +219,59 @@ func (s3 *S3) addS3PostprocessOptionsToSystemEnvironmentCfgMap(objects []runtime
 	systemEnvCfgMap.Data["FILE_UPLOAD_STORAGE"] = s3.Options.fileUploadStorage
 	systemEnvCfgMap.Data["AWS_BUCKET"] = s3.Options.awsBucket
 	systemEnvCfgMap.Data["AWS_REGION"] = s3.Options.awsRegion
+	systemEnvCfgMap.Data["AWS_HOSTNAME"] = *s3.Options.awsHostname
+	systemEnvCfgMap.Data["AWS_PROTOCOL"] = *s3.Options.awsProtocol
+	systemEnvCfgMap.Data["AWS_PATH_STYLE"] = *s3.Options.awsPathStyle
+	systemEnvCfgMap.Data["AWS_SERVER_SIDE_ENCRYPTION"] = *s3.Options.awsServerSideEncryption
+}
+
+// Mount the CA certificates of the S3 endpoint into the system-app and
+// system-sidekiq pods and point the AWS client to them with AWS_CA_BUNDLE
+func (s3 *S3) addCACertificate(objects []runtime.RawExtension) {
+	if s3.Options.caCertificateSecret == nil {
+		return
+	}
+
+	volume := v1.Volume{
+		Name: s3CACertificateVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: *s3.Options.caCertificateSecret,
+				Items: []v1.KeyToPath{
+					v1.KeyToPath{
+						Key:  S3CACertificateSecretFieldName,
+						Path: S3CACertificateSecretFieldName,
+					},
+				},
+			},
+		},
+	}
+	volumeMount := v1.VolumeMount{
+		Name:      s3CACertificateVolumeName,
+		ReadOnly:  true,
+		MountPath: s3CACertificateMountPath,
+	}
+	envVar := envVarFromValue("AWS_CA_BUNDLE", fmt.Sprintf("%s/%s", s3CACertificateMountPath, S3CACertificateSecretFieldName))
+
+	for _, rawExtension := range objects {
+		dc, ok := rawExtension.Object.(*appsv1.DeploymentConfig)
+		if !ok || (dc.ObjectMeta.Name != "system-app" && dc.ObjectMeta.Name != "system-sidekiq") {
+			continue
+		}
+
+		dc.Spec.Template.Spec.Volumes = append(dc.Spec.Template.Spec.Volumes, volume)
+		for containerIdx := range dc.Spec.Template.Spec.Containers {
+			container := &dc.Spec.Template.Spec.Containers[containerIdx]
+			container.VolumeMounts = append(container.VolumeMounts, volumeMount)
+			container.Env = append(container.Env, envVar)
+		}
+
+		if dc.ObjectMeta.Name == "system-app" {
+			preHook := dc.Spec.Strategy.RollingParams.Pre.ExecNewPod
+			preHook.Volumes = append(preHook.Volumes, s3CACertificateVolumeName)
+			preHook.Env = append(preHook.Env, envVar)
+		}
+	}
 }
 
 func (s3 *S3) removeSystemStoragePVC(objects []runtime.RawExtension) []runtime.RawExtension {
@@ -237,6 +309,7 @@ func (s3 *S3) PostProcessObjects(objects []runtime.RawExtension) []runtime.RawEx
 	s3.removeSystemStorageReferences(res)
 	s3.addS3PostprocessOptionsToSystemEnvironmentCfgMap(res)
 	s3.addCfgMapElemsToSystemBaseEnv(res)
+	s3.addCACertificate(res)
 
 	return res
 }
@@ -268,6 +341,27 @@ func (s3 *S3) buildParameters(template *templatev1.Template) {
 			Description: "AWS Region to use in S3 Storage for assets.",
 			Required:    false,
 		},
+		templatev1.Parameter{
+			Name:        "AWS_HOSTNAME",
+			Description: "Hostname of an S3-compatible service to use in S3 Storage for assets. Amazon S3 is used when empty.",
+			Required:    false,
+		},
+		templatev1.Parameter{
+			Name:        "AWS_PROTOCOL",
+			Description: "Protocol used to connect to the S3-compatible service. Either 'http' or 'https'.",
+			Required:    false,
+		},
+		templatev1.Parameter{
+			Name:        "AWS_PATH_STYLE",
+			Description: "Address the S3 bucket in the path of the requests instead of in the hostname.",
+			Value:       "false",
+			Required:    false,
+		},
+		templatev1.Parameter{
+			Name:        "AWS_SERVER_SIDE_ENCRYPTION",
+			Description: "Server-side encryption algorithm of the assets stored in S3. Either 'AES256' or 'aws:kms'. Disabled when empty.",
+			Required:    false,
+		},
 	}
 	template.Parameters = append(template.Parameters, parameters...)
 }
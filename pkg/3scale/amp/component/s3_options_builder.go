@@ -30,6 +30,26 @@ func (s3 *S3OptionsBuilder) AWSCredentialsSecret(awsCredentials string) {
 	s3.options.awsCredentialsSecret = awsCredentials
 }
 
+func (s3 *S3OptionsBuilder) AwsHostname(awsHostname string) {
+	s3.options.awsHostname = &awsHostname
+}
+
+func (s3 *S3OptionsBuilder) AwsProtocol(awsProtocol string) {
+	s3.options.awsProtocol = &awsProtocol
+}
+
+func (s3 *S3OptionsBuilder) AwsPathStyle(awsPathStyle string) {
+	s3.options.awsPathStyle = &awsPathStyle
+}
+
+func (s3 *S3OptionsBuilder) AwsServerSideEncryption(awsServerSideEncryption string) {
+	s3.options.awsServerSideEncryption = &awsServerSideEncryption
+}
+
+func (s3 *S3OptionsBuilder) CACertificateSecret(caCertificateSecret string) {
+	s3.options.caCertificateSecret = &caCertificateSecret
+}
+
 func (s3 *S3OptionsBuilder) Build() (*S3Options, error) {
 	err := s3.setRequiredOptions()
 	if err != nil {
@@ -66,5 +86,21 @@ func (s3 *S3OptionsBuilder) setRequiredOptions() error {
 }
 
 func (s3 *S3OptionsBuilder) setNonRequiredOptions() {
+	defaultAwsHostname := ""
+	defaultAwsProtocol := ""
+	defaultAwsPathStyle := "false"
+	defaultAwsServerSideEncryption := ""
 
+	if s3.options.awsHostname == nil {
+		s3.options.awsHostname = &defaultAwsHostname
+	}
+	if s3.options.awsProtocol == nil {
+		s3.options.awsProtocol = &defaultAwsProtocol
+	}
+	if s3.options.awsPathStyle == nil {
+		s3.options.awsPathStyle = &defaultAwsPathStyle
+	}
+	if s3.options.awsServerSideEncryption == nil {
+		s3.options.awsServerSideEncryption = &defaultAwsServerSideEncryption
+	}
 }
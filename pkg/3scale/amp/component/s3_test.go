@@ -0,0 +1,119 @@
+package component
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func testSystemObjects(t *testing.T) []runtime.RawExtension {
+	systemOpts, err := (&CLISystemOptionsProvider{}).GetSystemOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects, err := (&System{Options: systemOpts}).GetObjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return objects
+}
+
+func testS3OptionsBuilder() S3OptionsBuilder {
+	b := S3OptionsBuilder{}
+	b.AwsAccessKeyId("accessKeyId")
+	b.AwsSecretAccessKey("secretAccessKey")
+	b.AwsRegion("us-east-1")
+	b.AwsBucket("assets")
+	b.FileUploadStorage("s3")
+	b.AWSCredentialsSecret("aws-auth")
+	return b
+}
+
+func TestS3CompatibleEndpoint(t *testing.T) {
+	b := testS3OptionsBuilder()
+	b.AwsHostname("minio.example.com:9000")
+	b.AwsProtocol("https")
+	b.AwsPathStyle("true")
+	b.AwsServerSideEncryption("AES256")
+	b.CACertificateSecret("minio-ca")
+	opts, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects := (&S3{Options: opts}).PostProcessObjects(testSystemObjects(t))
+
+	var systemEnvironment *v1.ConfigMap
+	for idx := range objects {
+		if cfgmap, ok := objects[idx].Object.(*v1.ConfigMap); ok && cfgmap.Name == "system-environment" {
+			systemEnvironment = cfgmap
+		}
+	}
+	if systemEnvironment == nil {
+		t.Fatal("ConfigMap system-environment not found")
+	}
+	expectedData := map[string]string{
+		"AWS_HOSTNAME":               "minio.example.com:9000",
+		"AWS_PROTOCOL":               "https",
+		"AWS_PATH_STYLE":             "true",
+		"AWS_SERVER_SIDE_ENCRYPTION": "AES256",
+	}
+	for key, expected := range expectedData {
+		if value := systemEnvironment.Data[key]; value != expected {
+			t.Errorf("system-environment %s: expected %q, got %q", key, expected, value)
+		}
+	}
+
+	for _, name := range []string{"system-app", "system-sidekiq"} {
+		dc := findDeploymentConfig(objects, name)
+		if dc == nil {
+			t.Fatalf("DeploymentConfig %s not found", name)
+		}
+		found := false
+		for _, volume := range dc.Spec.Template.Spec.Volumes {
+			if volume.Name == s3CACertificateVolumeName && volume.Secret != nil && volume.Secret.SecretName == "minio-ca" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("DeploymentConfig %s does not mount the CA certificate secret", name)
+		}
+		for _, container := range dc.Spec.Template.Spec.Containers {
+			if !hasEnvVar(container.Env, "AWS_CA_BUNDLE") {
+				t.Errorf("container %s of DeploymentConfig %s has no AWS_CA_BUNDLE", container.Name, name)
+			}
+		}
+	}
+
+	preHook := findDeploymentConfig(objects, "system-app").Spec.Strategy.RollingParams.Pre.ExecNewPod
+	if !hasEnvVar(preHook.Env, "AWS_CA_BUNDLE") {
+		t.Error("system-app pre-hook has no AWS_CA_BUNDLE")
+	}
+}
+
+func TestS3Defaults(t *testing.T) {
+	b := testS3OptionsBuilder()
+	opts, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects := (&S3{Options: opts}).PostProcessObjects(testSystemObjects(t))
+
+	dc := findDeploymentConfig(objects, "system-app")
+	for _, volume := range dc.Spec.Template.Spec.Volumes {
+		if volume.Name == s3CACertificateVolumeName {
+			t.Error("CA certificate volume added without a CA certificate secret")
+		}
+	}
+}
+
+func hasEnvVar(envVars []v1.EnvVar, name string) bool {
+	for _, envVar := range envVars {
+		if envVar.Name == name {
+			return true
+		}
+	}
+	return false
+}
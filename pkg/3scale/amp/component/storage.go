@@ -0,0 +1,72 @@
+package component
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	SystemStoragePVCName       = "system-storage"
+	MySQLStoragePVCName        = "mysql-storage"
+	PostgreSQLStoragePVCName   = "postgresql-data"
+	BackendRedisStoragePVCName = backendRedisStorageVolumeName
+	SystemRedisStoragePVCName  = "system-redis-storage"
+)
+
+// Storage overrides the size, storage class, access modes and selector of
+// the PersistentVolumeClaims built by the other components
+type Storage struct {
+	Options *StorageOptions
+}
+
+type StorageOptions struct {
+	storageNonRequiredOptions
+}
+
+type storageNonRequiredOptions struct {
+	persistentVolumeClaims map[string]PersistentVolumeClaimOptions
+}
+
+// PersistentVolumeClaimOptions are the configurable fields of a
+// PersistentVolumeClaim. Unset fields keep the component defaults
+type PersistentVolumeClaimOptions struct {
+	Size             *resource.Quantity
+	StorageClassName *string
+	AccessModes      []v1.PersistentVolumeAccessMode
+	Selector         *metav1.LabelSelector
+}
+
+func (s *Storage) PostProcessObjects(objects []runtime.RawExtension) []runtime.RawExtension {
+	for rawExtIdx := range objects {
+		pvc, ok := objects[rawExtIdx].Object.(*v1.PersistentVolumeClaim)
+		if !ok {
+			continue
+		}
+		if pvcOptions, ok := s.Options.persistentVolumeClaims[pvc.Name]; ok {
+			s.applyPersistentVolumeClaimOptions(pvc, pvcOptions)
+		}
+	}
+
+	return objects
+}
+
+func (s *Storage) applyPersistentVolumeClaimOptions(pvc *v1.PersistentVolumeClaim, pvcOptions PersistentVolumeClaimOptions) {
+	if pvcOptions.Size != nil {
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = v1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[v1.ResourceStorage] = pvcOptions.Size.DeepCopy()
+	}
+	if pvcOptions.StorageClassName != nil {
+		storageClassName := *pvcOptions.StorageClassName
+		pvc.Spec.StorageClassName = &storageClassName
+	}
+	if len(pvcOptions.AccessModes) > 0 {
+		pvc.Spec.AccessModes = append([]v1.PersistentVolumeAccessMode{}, pvcOptions.AccessModes...)
+	}
+	if pvcOptions.Selector != nil {
+		pvc.Spec.Selector = pvcOptions.Selector.DeepCopy()
+	}
+}
@@ -0,0 +1,24 @@
+package component
+
+type StorageOptionsBuilder struct {
+	options StorageOptions
+}
+
+func (s *StorageOptionsBuilder) PersistentVolumeClaim(claimName string, pvcOptions PersistentVolumeClaimOptions) {
+	if s.options.persistentVolumeClaims == nil {
+		s.options.persistentVolumeClaims = map[string]PersistentVolumeClaimOptions{}
+	}
+	s.options.persistentVolumeClaims[claimName] = pvcOptions
+}
+
+func (s *StorageOptionsBuilder) Build() (*StorageOptions, error) {
+	s.setNonRequiredOptions()
+
+	return &s.options, nil
+}
+
+func (s *StorageOptionsBuilder) setNonRequiredOptions() {
+	if s.options.persistentVolumeClaims == nil {
+		s.options.persistentVolumeClaims = map[string]PersistentVolumeClaimOptions{}
+	}
+}
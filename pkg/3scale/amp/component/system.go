@@ -46,6 +46,10 @@ const (
 	SystemSecretSystemRedisMessageBusRedisURLFieldName = "MESSAGE_BUS_URL"
 	SystemSecretSystemRedisNamespace                   = "NAMESPACE"
 	SystemSecretSystemRedisMessageBusRedisNamespace    = "MESSAGE_BUS_NAMESPACE"
+	SystemSecretSystemRedisSentinelHosts               = "SENTINEL_HOSTS"
+	SystemSecretSystemRedisSentinelRole                = "SENTINEL_ROLE"
+	SystemSecretSystemRedisMessageBusSentinelHosts     = "MESSAGE_BUS_SENTINEL_HOSTS"
+	SystemSecretSystemRedisMessageBusSentinelRole      = "MESSAGE_BUS_SENTINEL_ROLE"
 )
 
 const (
@@ -111,6 +115,10 @@ type systemNonRequiredOptions struct {
 	redisNamespace                         *string
 	messageBusRedisNamespace               *string
 	messageBusRedisURL                     *string
+	redisSentinelHosts                     *string
+	redisSentinelRole                      *string
+	messageBusRedisSentinelHosts           *string
+	messageBusRedisSentinelRole            *string
 	apicastSystemMasterProxyConfigEndpoint *string
 	apicastSystemMasterBaseURL             *string
 	adminEmail                             *string
@@ -420,6 +428,10 @@ func (system *System) SystemRedisEnvVars() []v1.EnvVar {
 		envVarFromSecret("MESSAGE_BUS_REDIS_URL", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisMessageBusRedisURLFieldName),
 		envVarFromSecret("REDIS_NAMESPACE", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisNamespace),
 		envVarFromSecret("MESSAGE_BUS_REDIS_NAMESPACE", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisMessageBusRedisNamespace),
+		envVarFromSecret("REDIS_SENTINEL_HOSTS", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisSentinelHosts),
+		envVarFromSecret("REDIS_SENTINEL_ROLE", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisSentinelRole),
+		envVarFromSecret("MESSAGE_BUS_REDIS_SENTINEL_HOSTS", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisMessageBusSentinelHosts),
+		envVarFromSecret("MESSAGE_BUS_REDIS_SENTINEL_ROLE", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisMessageBusSentinelRole),
 	)
 	return result
 }
@@ -591,6 +603,10 @@ func (system *System) buildSystemRedisSecrets() *v1.Secret {
 			SystemSecretSystemRedisMessageBusRedisURLFieldName: *system.Options.messageBusRedisURL,
 			SystemSecretSystemRedisNamespace:                   *system.Options.redisNamespace,
 			SystemSecretSystemRedisMessageBusRedisNamespace:    *system.Options.messageBusRedisNamespace,
+			SystemSecretSystemRedisSentinelHosts:               *system.Options.redisSentinelHosts,
+			SystemSecretSystemRedisSentinelRole:                *system.Options.redisSentinelRole,
+			SystemSecretSystemRedisMessageBusSentinelHosts:     *system.Options.messageBusRedisSentinelHosts,
+			SystemSecretSystemRedisMessageBusSentinelRole:      *system.Options.messageBusRedisSentinelRole,
 		},
 		Type: v1.SecretTypeOpaque,
 	}
@@ -1051,6 +1067,10 @@ func (system *System) buildSystemSidekiqDeploymentConfig() *appsv1.DeploymentCon
 								envVarFromSecret("MESSAGE_BUS_REDIS_URL", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisMessageBusRedisURLFieldName),
 								envVarFromSecret("REDIS_NAMESPACE", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisNamespace),
 								envVarFromSecret("MESSAGE_BUS_REDIS_NAMESPACE", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisMessageBusRedisNamespace),
+								envVarFromSecret("REDIS_SENTINEL_HOSTS", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisSentinelHosts),
+								envVarFromSecret("REDIS_SENTINEL_ROLE", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisSentinelRole),
+								envVarFromSecret("MESSAGE_BUS_REDIS_SENTINEL_HOSTS", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisMessageBusSentinelHosts),
+								envVarFromSecret("MESSAGE_BUS_REDIS_SENTINEL_ROLE", SystemSecretSystemRedisSecretName, SystemSecretSystemRedisMessageBusSentinelRole),
 							},
 						},
 					},
@@ -1307,7 +1327,7 @@ func (system *System) buildSystemProviderRoute() *routev1.Route {
 			APIVersion: "route.openshift.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   "system-provider-admin",
+			Name:   SystemProviderRouteName,
 			Labels: map[string]string{"app": system.Options.appLabel, "threescale_component": "system", "threescale_component_element": "provider-ui"},
 		},
 		Spec: routev1.RouteSpec{
@@ -1333,7 +1353,7 @@ func (system *System) buildSystemMasterRoute() *routev1.Route {
 			APIVersion: "route.openshift.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   "system-master",
+			Name:   SystemMasterRouteName,
 			Labels: map[string]string{"app": system.Options.appLabel, "threescale_component": "system", "threescale_component_element": "master-ui"},
 		},
 		Spec: routev1.RouteSpec{
@@ -1359,7 +1379,7 @@ func (system *System) buildSystemDeveloperRoute() *routev1.Route {
 			APIVersion: "route.openshift.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   "system-developer",
+			Name:   SystemDeveloperRouteName,
 			Labels: map[string]string{"app": system.Options.appLabel, "threescale_component": "system", "threescale_component_element": "developer-ui"},
 		},
 		Spec: routev1.RouteSpec{
@@ -3,6 +3,7 @@ package component
 import (
 	"fmt"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	imagev1 "github.com/openshift/api/image/v1"
 	templatev1 "github.com/openshift/api/template/v1"
 	v1 "k8s.io/api/core/v1"
@@ -131,7 +132,7 @@ func (s *SystemMySQLImage) buildParameters(template *templatev1.Template) {
 		templatev1.Parameter{
 			Name:        "SYSTEM_DATABASE_IMAGE",
 			Description: "System MySQL image to use",
-			Value:       "registry.access.redhat.com/rhscl/mysql-57-rhel7:5.7",
+			Value:       templateImageProvider(product.ProductUpstream).GetSystemMySQLImage(),
 			Required:    true,
 		},
 	}
@@ -106,6 +106,22 @@ func (s *SystemOptionsBuilder) MessageBusRedisNamespace(namespace string) {
 	s.options.messageBusRedisNamespace = &namespace
 }
 
+func (s *SystemOptionsBuilder) RedisSentinelHosts(hosts string) {
+	s.options.redisSentinelHosts = &hosts
+}
+
+func (s *SystemOptionsBuilder) RedisSentinelRole(role string) {
+	s.options.redisSentinelRole = &role
+}
+
+func (s *SystemOptionsBuilder) MessageBusRedisSentinelHosts(hosts string) {
+	s.options.messageBusRedisSentinelHosts = &hosts
+}
+
+func (s *SystemOptionsBuilder) MessageBusRedisSentinelRole(role string) {
+	s.options.messageBusRedisSentinelRole = &role
+}
+
 func (s *SystemOptionsBuilder) ApicastSystemMasterProxyConfigEndpoint(endpoint string) {
 	s.options.apicastSystemMasterProxyConfigEndpoint = &endpoint
 }
@@ -182,6 +198,10 @@ func (s *SystemOptionsBuilder) setNonRequiredOptions() {
 	defaultMessageBusRedisURL := ""
 	defaultRedisNamespace := ""
 	defaultMessageBusRedisNamespace := ""
+	defaultRedisSentinelHosts := ""
+	defaultRedisSentinelRole := ""
+	defaultMessageBusRedisSentinelHosts := ""
+	defaultMessageBusRedisSentinelRole := ""
 
 	defaultApicastSystemMasterProxyConfigEndpoint := "http://" + s.options.apicastAccessToken + "@system-master:3000/master/api/proxy/configs"
 	defaultApicastSystemMasterBaseURL := "http://" + s.options.apicastAccessToken + "@system-master:3000"
@@ -211,6 +231,22 @@ func (s *SystemOptionsBuilder) setNonRequiredOptions() {
 		s.options.messageBusRedisNamespace = &defaultMessageBusRedisNamespace
 	}
 
+	if s.options.redisSentinelHosts == nil {
+		s.options.redisSentinelHosts = &defaultRedisSentinelHosts
+	}
+
+	if s.options.redisSentinelRole == nil {
+		s.options.redisSentinelRole = &defaultRedisSentinelRole
+	}
+
+	if s.options.messageBusRedisSentinelHosts == nil {
+		s.options.messageBusRedisSentinelHosts = &defaultMessageBusRedisSentinelHosts
+	}
+
+	if s.options.messageBusRedisSentinelRole == nil {
+		s.options.messageBusRedisSentinelRole = &defaultMessageBusRedisSentinelRole
+	}
+
 	if s.options.apicastSystemMasterProxyConfigEndpoint == nil {
 		s.options.apicastSystemMasterProxyConfigEndpoint = &defaultApicastSystemMasterProxyConfigEndpoint
 	}
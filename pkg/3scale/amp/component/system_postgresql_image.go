@@ -3,6 +3,7 @@ package component
 import (
 	"fmt"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	imagev1 "github.com/openshift/api/image/v1"
 	templatev1 "github.com/openshift/api/template/v1"
 	v1 "k8s.io/api/core/v1"
@@ -132,7 +133,7 @@ func (s *SystemPostgreSQLImage) buildParameters(template *templatev1.Template) {
 			Name:        "SYSTEM_DATABASE_IMAGE",
 			Description: "System PostgreSQL image to use",
 			Required:    true,
-			Value:       "registry.access.redhat.com/rhscl/postgresql-10-rhel7",
+			Value:       templateImageProvider(product.ProductUpstream).GetSystemPostgreSQLImage(),
 		},
 	}
 	template.Parameters = append(template.Parameters, parameters...)
@@ -4,14 +4,13 @@ import (
 	"fmt"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
-	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 )
 
 func (o *OperatorAmpImagesOptionsProvider) GetAmpImagesOptions() (*component.AmpImagesOptions, error) {
 	optProv := component.AmpImagesOptionsBuilder{}
 
 	productVersion := o.APIManagerSpec.ProductVersion
-	imageProvider, err := product.NewImageProvider(productVersion)
+	imageProvider, err := newImageProvider(o.APIManagerSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +72,11 @@ func (o *OperatorAmpImagesOptionsProvider) GetAmpImagesOptions() (*component.Amp
 	}
 
 	optProv.InsecureImportPolicy(*o.APIManagerSpec.ImageStreamTagImportInsecure)
+	if o.APIManagerSpec.Registry != nil {
+		for _, secret := range o.APIManagerSpec.Registry.ImagePullSecrets {
+			optProv.ImagePullSecret(secret.Name)
+		}
+	}
 	res, err := optProv.Build()
 	if err != nil {
 		return nil, fmt.Errorf("unable to create AMPImages Options - %s", err)
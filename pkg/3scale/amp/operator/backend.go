@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
-	oprand "github.com/3scale/3scale-operator/pkg/crypto/rand"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/errors"
 )
 
@@ -44,8 +44,12 @@ func (o *OperatorBackendOptionsProvider) setSecretBasedOptions(b *component.Back
 }
 
 func (o *OperatorBackendOptionsProvider) setBackendInternalApiOptions(b *component.BackendOptionsBuilder) error {
+	defaultSystemBackendPassword, err := generateCredential(o.APIManagerSpec, appsv1alpha1.PasswordCredentialType)
+	if err != nil {
+		return err
+	}
+
 	defaultSystemBackendUsername := "3scale_api_user"
-	defaultSystemBackendPassword := oprand.String(8)
 
 	currSecret, err := getSecret(component.BackendSecretInternalApiSecretName, o.Namespace, o.Client)
 	if err != nil {
@@ -120,7 +124,7 @@ func (o *OperatorBackendOptionsProvider) setBackendRedisOptions(b *component.Bac
 		}
 		result = getSecretDataValue(secretData, component.BackendSecretBackendRedisQueuesSentinelHostsFieldName)
 		if result != nil {
-			b.RedisStorageSentinelHosts(*result)
+			b.RedisQueuesSentinelHosts(*result)
 		}
 		result = getSecretDataValue(secretData, component.BackendSecretBackendRedisQueuesSentinelRoleFieldName)
 		if result != nil {
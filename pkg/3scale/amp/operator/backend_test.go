@@ -0,0 +1,61 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBackendRedisSentinelOptionsFromSecret(t *testing.T) {
+	namespace := "operator-test"
+	appLabel := "3scale-api-management"
+	tenantName := "3scale"
+	spec := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+			AppLabel:       &appLabel,
+			TenantName:     &tenantName,
+			WildcardDomain: "example.com",
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.BackendSecretBackendRedisSecretName, Namespace: namespace},
+		Data: map[string][]byte{
+			component.BackendSecretBackendRedisStorageSentinelHostsFieldName: []byte("redis://storage-sentinel:26379"),
+			component.BackendSecretBackendRedisQueuesSentinelHostsFieldName:  []byte("redis://queues-sentinel:26379"),
+		},
+	}
+
+	optsProvider := OperatorBackendOptionsProvider{APIManagerSpec: spec, Namespace: namespace, Client: fake.NewFakeClient(secret)}
+	opts, err := optsProvider.GetBackendOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects, err := (&component.Backend{Options: opts}).GetObjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var redisSecret *v1.Secret
+	for _, object := range objects {
+		if s, ok := object.Object.(*v1.Secret); ok && s.Name == component.BackendSecretBackendRedisSecretName {
+			redisSecret = s
+		}
+	}
+	if redisSecret == nil {
+		t.Fatalf("secret %s not found", component.BackendSecretBackendRedisSecretName)
+	}
+
+	expected := map[string]string{
+		component.BackendSecretBackendRedisStorageSentinelHostsFieldName: "redis://storage-sentinel:26379",
+		component.BackendSecretBackendRedisQueuesSentinelHostsFieldName:  "redis://queues-sentinel:26379",
+	}
+	for field, value := range expected {
+		if redisSecret.StringData[field] != value {
+			t.Errorf("expected %s to be %q, got %q", field, value, redisSecret.StringData[field])
+		}
+	}
+}
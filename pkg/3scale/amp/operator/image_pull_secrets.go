@@ -0,0 +1,21 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+)
+
+func (o *OperatorImagePullSecretsOptionsProvider) GetImagePullSecretsOptions() (*component.ImagePullSecretsOptions, error) {
+	optProv := component.ImagePullSecretsOptionsBuilder{}
+
+	for _, secret := range o.APIManagerSpec.Registry.ImagePullSecrets {
+		optProv.ImagePullSecret(secret.Name)
+	}
+
+	res, err := optProv.Build()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Image Pull Secrets Options - %s", err)
+	}
+	return res, nil
+}
@@ -6,7 +6,7 @@ import (
 	"strings"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
-	oprand "github.com/3scale/3scale-operator/pkg/crypto/rand"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/errors"
 )
 
@@ -36,11 +36,18 @@ func (o *OperatorMysqlOptionsProvider) setSecretBasedOptions(builder *component.
 }
 
 func (o *OperatorMysqlOptionsProvider) setSystemDatabaseOptions(builder *component.MysqlOptionsBuilder) error {
+	defaultDatabaseRootPassword, err := generateCredential(o.APIManagerSpec, appsv1alpha1.PasswordCredentialType)
+	if err != nil {
+		return err
+	}
+	defaultDatabasePassword, err := generateCredential(o.APIManagerSpec, appsv1alpha1.PasswordCredentialType)
+	if err != nil {
+		return err
+	}
+
 	currSecret, err := getSecret(component.SystemSecretSystemDatabaseSecretName, o.Namespace, o.Client)
 	defaultDatabaseName := "system"
-	defaultDatabaseRootPassword := oprand.String(8)
 	defaultDatabaseUsername := "mysql"
-	defaultDatabasePassword := oprand.String(8)
 	// TODO is this correct?? in templates the user provides dbname and rootpassword
 	// but the secret is only the URL.
 	defaultDatabaseURL := "mysql2://root:" + defaultDatabaseRootPassword + "@system-mysql/" + defaultDatabaseName
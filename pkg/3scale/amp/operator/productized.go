@@ -4,14 +4,13 @@ import (
 	"fmt"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
-	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 )
 
 func (o *OperatorProductizedOptionsProvider) GetProductizedOptions() (*component.ProductizedOptions, error) {
 	pob := component.ProductizedOptionsBuilder{}
 
 	productVersion := o.APIManagerSpec.ProductVersion
-	imageProvider, err := product.NewImageProvider(productVersion)
+	imageProvider, err := newImageProvider(o.APIManagerSpec)
 	if err != nil {
 		return nil, err
 	}
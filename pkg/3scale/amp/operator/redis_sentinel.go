@@ -0,0 +1,38 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+)
+
+func (o *OperatorRedisSentinelOptionsProvider) GetRedisSentinelOptions() (*component.RedisSentinelOptions, error) {
+	optProv := component.RedisSentinelOptionsBuilder{}
+	optProv.AppLabel(*o.APIManagerSpec.AppLabel)
+
+	redisSpec := o.APIManagerSpec.Redis
+	if redisSpec.Sentinel != nil {
+		optProv.Deployed(*redisSpec.Sentinel.Replicas, *redisSpec.Sentinel.Sentinels)
+	}
+	if external := redisSpec.ExternalSentinel; external != nil {
+		optProv.SentinelHosts(external.Hosts)
+		if external.Role != nil {
+			optProv.SentinelRole(*external.Role)
+		}
+		if external.BackendStorageMasterName != nil {
+			optProv.BackendStorageMasterName(*external.BackendStorageMasterName)
+		}
+		if external.BackendQueuesMasterName != nil {
+			optProv.BackendQueuesMasterName(*external.BackendQueuesMasterName)
+		}
+		if external.SystemMasterName != nil {
+			optProv.SystemMasterName(*external.SystemMasterName)
+		}
+	}
+
+	res, err := optProv.Build()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Redis Sentinel Options - %s", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,135 @@
+package operator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+)
+
+var imageDigestRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+
+// newImageProvider returns the image provider of the APIManager release.
+// The images are pulled from the registry mirror and pinned to their
+// digests when the APIManager configures them
+func newImageProvider(spec *appsv1alpha1.APIManagerSpec) (product.ImageProvider, error) {
+	imageProvider, err := product.NewImageProvider(spec.ProductVersion)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Registry == nil {
+		return imageProvider, nil
+	}
+
+	digests := spec.Registry.ImageDigests
+	if digests == nil {
+		digests = &appsv1alpha1.ImageDigestsSpec{}
+	}
+	digestFields := map[string]*string{
+		"apicast":          digests.Apicast,
+		"backend":          digests.Backend,
+		"backendRedis":     digests.BackendRedis,
+		"system":           digests.System,
+		"systemRedis":      digests.SystemRedis,
+		"systemMySQL":      digests.SystemMySQL,
+		"systemPostgreSQL": digests.SystemPostgreSQL,
+		"systemMemcached":  digests.SystemMemcached,
+		"wildcardRouter":   digests.WildcardRouter,
+		"zync":             digests.Zync,
+		"zyncPostgreSQL":   digests.ZyncPostgreSQL,
+	}
+	for field, digest := range digestFields {
+		if digest != nil && !imageDigestRegexp.MatchString(*digest) {
+			return nil, fmt.Errorf("invalid image digest '%s' for %s. Digests have the form 'sha256:<hex>'", *digest, field)
+		}
+	}
+
+	mirror := ""
+	if spec.Registry.Mirror != nil {
+		mirror = strings.TrimSuffix(*spec.Registry.Mirror, "/")
+	}
+
+	return &registryImageProvider{imageProvider: imageProvider, mirror: mirror, digests: digests}, nil
+}
+
+type registryImageProvider struct {
+	imageProvider product.ImageProvider
+	mirror        string
+	digests       *appsv1alpha1.ImageDigestsSpec
+}
+
+func (r *registryImageProvider) image(image string, digest *string) string {
+	if r.mirror != "" {
+		image = r.mirror + "/" + imageRepositoryPath(image)
+	}
+	if digest != nil {
+		image = imageRepository(image) + "@" + *digest
+	}
+	return image
+}
+
+// imageRepositoryPath returns the image without its registry host
+func imageRepositoryPath(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[1]
+	}
+	return image
+}
+
+// imageRepository returns the image without its tag or digest
+func imageRepository(image string) string {
+	if idx := strings.Index(image, "@"); idx != -1 {
+		image = image[:idx]
+	}
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		image = image[:idx]
+	}
+	return image
+}
+
+func (r *registryImageProvider) GetApicastImage() string {
+	return r.image(r.imageProvider.GetApicastImage(), r.digests.Apicast)
+}
+
+func (r *registryImageProvider) GetBackendImage() string {
+	return r.image(r.imageProvider.GetBackendImage(), r.digests.Backend)
+}
+
+func (r *registryImageProvider) GetBackendRedisImage() string {
+	return r.image(r.imageProvider.GetBackendRedisImage(), r.digests.BackendRedis)
+}
+
+func (r *registryImageProvider) GetSystemImage() string {
+	return r.image(r.imageProvider.GetSystemImage(), r.digests.System)
+}
+
+func (r *registryImageProvider) GetSystemRedisImage() string {
+	return r.image(r.imageProvider.GetSystemRedisImage(), r.digests.SystemRedis)
+}
+
+func (r *registryImageProvider) GetSystemMySQLImage() string {
+	return r.image(r.imageProvider.GetSystemMySQLImage(), r.digests.SystemMySQL)
+}
+
+func (r *registryImageProvider) GetSystemPostgreSQLImage() string {
+	return r.image(r.imageProvider.GetSystemPostgreSQLImage(), r.digests.SystemPostgreSQL)
+}
+
+func (r *registryImageProvider) GetSystemMemcachedImage() string {
+	return r.image(r.imageProvider.GetSystemMemcachedImage(), r.digests.SystemMemcached)
+}
+
+func (r *registryImageProvider) GetWildcardRouterImage() string {
+	return r.image(r.imageProvider.GetWildcardRouterImage(), r.digests.WildcardRouter)
+}
+
+func (r *registryImageProvider) GetZyncImage() string {
+	return r.image(r.imageProvider.GetZyncImage(), r.digests.Zync)
+}
+
+func (r *registryImageProvider) GetZyncPostgreSQLImage() string {
+	return r.image(r.imageProvider.GetZyncPostgreSQLImage(), r.digests.ZyncPostgreSQL)
+}
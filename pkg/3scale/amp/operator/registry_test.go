@@ -0,0 +1,53 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+)
+
+func TestNewImageProviderRegistry(t *testing.T) {
+	mirror := "mirror.example.com:5000/3scale/"
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	spec := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{ProductVersion: product.ProductRelease_2_5},
+		Registry: &appsv1alpha1.RegistrySpec{
+			Mirror:       &mirror,
+			ImageDigests: &appsv1alpha1.ImageDigestsSpec{SystemRedis: &digest},
+		},
+	}
+
+	imageProvider, err := newImageProvider(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		image    string
+		expected string
+	}{
+		{imageProvider.GetBackendImage(), "mirror.example.com:5000/3scale/3scale-amp25/backend"},
+		{imageProvider.GetBackendRedisImage(), "mirror.example.com:5000/3scale/rhscl/redis-32-rhel7:3.2"},
+		{imageProvider.GetSystemRedisImage(), "mirror.example.com:5000/3scale/rhscl/redis-32-rhel7@" + digest},
+	}
+	for _, tc := range cases {
+		if tc.image != tc.expected {
+			t.Errorf("expected image %s, got %s", tc.expected, tc.image)
+		}
+	}
+}
+
+func TestNewImageProviderInvalidDigest(t *testing.T) {
+	digest := "latest"
+	spec := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{ProductVersion: product.ProductRelease_2_5},
+		Registry: &appsv1alpha1.RegistrySpec{
+			ImageDigests: &appsv1alpha1.ImageDigestsSpec{Apicast: &digest},
+		},
+	}
+
+	if _, err := newImageProvider(spec); err == nil {
+		t.Error("expected an error for an invalid image digest")
+	}
+}
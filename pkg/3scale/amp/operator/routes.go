@@ -0,0 +1,101 @@
+package operator
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// APIManagerRouteSpecs returns the RouteSpec of every configured Route,
+// indexed by Route name
+func APIManagerRouteSpecs(spec *appsv1alpha1.APIManagerSpec) map[string]*appsv1alpha1.RouteSpec {
+	result := map[string]*appsv1alpha1.RouteSpec{}
+	if spec.Routes == nil {
+		return result
+	}
+
+	routeSpecs := map[string]*appsv1alpha1.RouteSpec{
+		component.SystemMasterRouteName:      spec.Routes.SystemMaster,
+		component.SystemProviderRouteName:    spec.Routes.SystemProvider,
+		component.SystemDeveloperRouteName:   spec.Routes.SystemDeveloper,
+		component.ApicastStagingRouteName:    spec.Routes.ApicastStaging,
+		component.ApicastProductionRouteName: spec.Routes.ApicastProduction,
+	}
+	for routeName, routeSpec := range routeSpecs {
+		if routeSpec != nil {
+			result[routeName] = routeSpec
+		}
+	}
+	return result
+}
+
+func (o *OperatorRoutesOptionsProvider) GetRoutesOptions() (*component.RoutesOptions, error) {
+	optProv := component.RoutesOptionsBuilder{}
+
+	for routeName, routeSpec := range APIManagerRouteSpecs(o.APIManagerSpec) {
+		routeOptions, err := o.getRouteOptions(routeSpec)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create Route %s Options - %s", routeName, err)
+		}
+		optProv.Route(routeName, *routeOptions)
+	}
+
+	res, err := optProv.Build()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Routes Options - %s", err)
+	}
+	return res, nil
+}
+
+func (o *OperatorRoutesOptionsProvider) getRouteOptions(routeSpec *appsv1alpha1.RouteSpec) (*component.RouteOptions, error) {
+	routeOptions := &component.RouteOptions{
+		Host: routeSpec.Host,
+	}
+	if routeSpec.TLS == nil {
+		return routeOptions, nil
+	}
+
+	if routeSpec.TLS.CertificateSecret != nil {
+		err := o.setCertificateOptions(routeOptions, routeSpec.TLS.CertificateSecret.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return routeOptions, nil
+}
+
+func (o *OperatorRoutesOptionsProvider) setCertificateOptions(routeOptions *component.RouteOptions, secretName string) error {
+	currSecret, err := getSecret(secretName, o.Namespace, o.Client)
+	if err != nil {
+		return err
+	}
+
+	certificate := getSecretDataValue(currSecret.Data, v1.TLSCertKey)
+	if certificate == nil {
+		return fmt.Errorf("Secret field '%s' is required in secret '%s'", v1.TLSCertKey, secretName)
+	}
+	key := getSecretDataValue(currSecret.Data, v1.TLSPrivateKeyKey)
+	if key == nil {
+		return fmt.Errorf("Secret field '%s' is required in secret '%s'", v1.TLSPrivateKeyKey, secretName)
+	}
+	if _, err := tls.X509KeyPair([]byte(*certificate), []byte(*key)); err != nil {
+		return fmt.Errorf("invalid certificate and key in secret '%s' - %s", secretName, err)
+	}
+	routeOptions.Certificate = *certificate
+	routeOptions.Key = *key
+
+	caCertificate := getSecretDataValue(currSecret.Data, v1.ServiceAccountRootCAKey)
+	if caCertificate != nil {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(*caCertificate)) {
+			return fmt.Errorf("Secret field '%s' in secret '%s' does not contain any PEM encoded certificate", v1.ServiceAccountRootCAKey, secretName)
+		}
+		routeOptions.CACertificate = *caCertificate
+	}
+
+	return nil
+}
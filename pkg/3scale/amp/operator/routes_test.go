@@ -0,0 +1,49 @@
+package operator
+
+import (
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetRoutesOptionsCertificateSecret(t *testing.T) {
+	cases := []struct {
+		name  string
+		data  map[string][]byte
+		valid bool
+	}{
+		{"no-key", map[string][]byte{v1.TLSCertKey: []byte("certificate")}, false},
+		{"invalid-pair", map[string][]byte{v1.TLSCertKey: []byte("certificate"), v1.TLSPrivateKeyKey: []byte("key")}, false},
+	}
+	for _, tc := range cases {
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: tc.name, Namespace: "operator-test"}, Data: tc.data}
+		spec := &appsv1alpha1.APIManagerSpec{
+			Routes: &appsv1alpha1.RoutesSpec{
+				SystemProvider: &appsv1alpha1.RouteSpec{
+					TLS: &appsv1alpha1.RouteTLSSpec{CertificateSecret: &v1.LocalObjectReference{Name: tc.name}},
+				},
+			},
+		}
+		optsProvider := OperatorRoutesOptionsProvider{APIManagerSpec: spec, Namespace: "operator-test", Client: k8sfake.NewFakeClientWithScheme(scheme.Scheme, secret)}
+		_, err := optsProvider.GetRoutesOptions()
+		if tc.valid && err != nil {
+			t.Errorf("secret '%s': unexpected error: %v", tc.name, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("secret '%s': expected an error", tc.name)
+		}
+	}
+
+	// Without a certificate secret, the router's default certificate is used
+	spec := &appsv1alpha1.APIManagerSpec{
+		Routes: &appsv1alpha1.RoutesSpec{SystemProvider: &appsv1alpha1.RouteSpec{TLS: &appsv1alpha1.RouteTLSSpec{}}},
+	}
+	optsProvider := OperatorRoutesOptionsProvider{APIManagerSpec: spec, Namespace: "operator-test"}
+	if _, err := optsProvider.GetRoutesOptions(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,7 +1,10 @@
 package operator
 
 import (
+	"crypto/x509"
 	"fmt"
+	"net/url"
+	"strconv"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 )
@@ -13,7 +16,21 @@ func (o *OperatorS3OptionsProvider) GetS3Options() (*component.S3Options, error)
 	sob.AwsBucket(SystemS3Spec.AWSBucket)
 	sob.FileUploadStorage(SystemS3Spec.FileUploadStorage)
 
-	err := o.setSecretBasedOptions(&sob)
+	err := o.setEndpointOptions(&sob)
+	if err != nil {
+		return nil, err
+	}
+
+	if SystemS3Spec.ServerSideEncryption != nil {
+		switch *SystemS3Spec.ServerSideEncryption {
+		case "AES256", "aws:kms":
+			sob.AwsServerSideEncryption(*SystemS3Spec.ServerSideEncryption)
+		default:
+			return nil, fmt.Errorf("unsupported S3 server-side encryption '%s'. Supported values are 'AES256' and 'aws:kms'", *SystemS3Spec.ServerSideEncryption)
+		}
+	}
+
+	err = o.setSecretBasedOptions(&sob)
 	if err != nil {
 		return nil, err
 	}
@@ -25,12 +42,42 @@ func (o *OperatorS3OptionsProvider) GetS3Options() (*component.S3Options, error)
 	return res, nil
 }
 
+func (o *OperatorS3OptionsProvider) setEndpointOptions(sob *component.S3OptionsBuilder) error {
+	SystemS3Spec := o.APIManagerSpec.System.FileStorageSpec.S3
+
+	if SystemS3Spec.Endpoint != nil {
+		endpoint, err := url.Parse(*SystemS3Spec.Endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid S3 endpoint '%s' - %s", *SystemS3Spec.Endpoint, err)
+		}
+		if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+			return fmt.Errorf("invalid S3 endpoint '%s' - the scheme must be 'http' or 'https'", *SystemS3Spec.Endpoint)
+		}
+		if endpoint.Host == "" || (endpoint.Path != "" && endpoint.Path != "/") {
+			return fmt.Errorf("invalid S3 endpoint '%s' - only a scheme, a host and a port are allowed", *SystemS3Spec.Endpoint)
+		}
+		sob.AwsHostname(endpoint.Host)
+		sob.AwsProtocol(endpoint.Scheme)
+	}
+
+	if SystemS3Spec.ForcePathStyle != nil {
+		sob.AwsPathStyle(strconv.FormatBool(*SystemS3Spec.ForcePathStyle))
+	}
+
+	return nil
+}
+
 func (o *OperatorS3OptionsProvider) setSecretBasedOptions(sob *component.S3OptionsBuilder) error {
 	err := o.setAWSSecretOptions(sob)
 	if err != nil {
 		return fmt.Errorf("unable to create S3 Secret Options - %s", err)
 	}
 
+	err = o.setCACertificateSecretOptions(sob)
+	if err != nil {
+		return fmt.Errorf("unable to create S3 Secret Options - %s", err)
+	}
+
 	return nil
 }
 
@@ -46,13 +93,13 @@ func (o *OperatorS3OptionsProvider) setAWSSecretOptions(sob *component.S3Options
 	secretData := currSecret.Data
 	var result *string
 	result = getSecretDataValue(secretData, component.S3SecretAWSAccessKeyIdFieldName)
-	if result == nil {
+	if result == nil || *result == "" {
 		return fmt.Errorf("Secret field '%s' is required in secret '%s'", component.S3SecretAWSAccessKeyIdFieldName, awsCredentialsSecretName)
 	}
 	sob.AwsAccessKeyId(*result)
 
 	result = getSecretDataValue(secretData, component.S3SecretAWSSecretAccessKeyFieldName)
-	if result == nil {
+	if result == nil || *result == "" {
 		return fmt.Errorf("Secret field '%s' is required in secret '%s'", component.S3SecretAWSSecretAccessKeyFieldName, awsCredentialsSecretName)
 	}
 	sob.AwsSecretAccessKey(*result)
@@ -61,3 +108,26 @@ func (o *OperatorS3OptionsProvider) setAWSSecretOptions(sob *component.S3Options
 
 	return nil
 }
+
+func (o *OperatorS3OptionsProvider) setCACertificateSecretOptions(sob *component.S3OptionsBuilder) error {
+	caCertificateSecret := o.APIManagerSpec.System.FileStorageSpec.S3.CACertificateSecret
+	if caCertificateSecret == nil {
+		return nil
+	}
+
+	currSecret, err := getSecret(caCertificateSecret.Name, o.Namespace, o.Client)
+	if err != nil {
+		return err
+	}
+
+	result := getSecretDataValue(currSecret.Data, component.S3CACertificateSecretFieldName)
+	if result == nil {
+		return fmt.Errorf("Secret field '%s' is required in secret '%s'", component.S3CACertificateSecretFieldName, caCertificateSecret.Name)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM([]byte(*result)) {
+		return fmt.Errorf("Secret field '%s' in secret '%s' does not contain any PEM encoded certificate", component.S3CACertificateSecretFieldName, caCertificateSecret.Name)
+	}
+	sob.CACertificateSecret(caCertificateSecret.Name)
+
+	return nil
+}
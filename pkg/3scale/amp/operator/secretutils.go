@@ -2,11 +2,23 @@ package operator
 
 import (
 	"context"
+	"fmt"
 
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// generateCredential returns a new random value for the credential type,
+// following the APIManager credential policy
+func generateCredential(spec *appsv1alpha1.APIManagerSpec, credentialType appsv1alpha1.CredentialType) (string, error) {
+	value, err := spec.CredentialPolicy(credentialType).Generate()
+	if err != nil {
+		return "", fmt.Errorf("invalid %s credential policy: %v", credentialType, err)
+	}
+	return value, nil
+}
+
 func getSecretDataValueOrDefault(secretData map[string][]byte, fieldName string, defaultValue string) string {
 	if value, exists := secretData[fieldName]; exists {
 		return string(value)
@@ -0,0 +1,60 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+)
+
+func (o *OperatorStorageOptionsProvider) GetStorageOptions() (*component.StorageOptions, error) {
+	optProv := component.StorageOptionsBuilder{}
+
+	storageSpec := o.APIManagerSpec.Storage
+	if storageSpec == nil {
+		storageSpec = &appsv1alpha1.StorageSpec{}
+	}
+
+	systemStorage := storageSpec.SystemStorage
+	// The storage class of the system FileStorage PVC is used unless the
+	// system storage sets its own
+	if o.APIManagerSpec.System != nil && o.APIManagerSpec.System.FileStorageSpec != nil &&
+		o.APIManagerSpec.System.FileStorageSpec.PVC != nil && o.APIManagerSpec.System.FileStorageSpec.PVC.StorageClassName != nil {
+		if systemStorage == nil {
+			systemStorage = &appsv1alpha1.PersistentVolumeClaimSpec{}
+		} else {
+			systemStorage = systemStorage.DeepCopy()
+		}
+		if systemStorage.StorageClassName == nil {
+			systemStorage.StorageClassName = o.APIManagerSpec.System.FileStorageSpec.PVC.StorageClassName
+		}
+	}
+
+	pvcSpecs := []struct {
+		claimName string
+		spec      *appsv1alpha1.PersistentVolumeClaimSpec
+	}{
+		{component.SystemStoragePVCName, systemStorage},
+		{component.MySQLStoragePVCName, storageSpec.SystemDatabase},
+		{component.PostgreSQLStoragePVCName, storageSpec.SystemDatabase},
+		{component.BackendRedisStoragePVCName, storageSpec.BackendRedis},
+		{component.SystemRedisStoragePVCName, storageSpec.SystemRedis},
+	}
+	for _, pvcSpec := range pvcSpecs {
+		if pvcSpec.spec == nil {
+			continue
+		}
+		optProv.PersistentVolumeClaim(pvcSpec.claimName, component.PersistentVolumeClaimOptions{
+			Size:             pvcSpec.spec.Size,
+			StorageClassName: pvcSpec.spec.StorageClassName,
+			AccessModes:      pvcSpec.spec.AccessModes,
+			Selector:         pvcSpec.spec.Selector,
+		})
+	}
+
+	res, err := optProv.Build()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Storage Options - %s", err)
+	}
+	return res, nil
+}
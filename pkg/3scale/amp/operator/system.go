@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
-	oprand "github.com/3scale/3scale-operator/pkg/crypto/rand"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/errors"
 )
 
@@ -117,9 +117,12 @@ func (o *OperatorSystemOptionsProvider) setSystemRecaptchaOptions(builder *compo
 }
 
 func (o *OperatorSystemOptionsProvider) setSystemEventHookOptions(builder *component.SystemOptionsBuilder) error {
-	currSecret, err := getSecret(component.SystemSecretSystemEventsHookSecretName, o.Namespace, o.Client)
-	defaultBackendSharedSecret := oprand.String(8)
+	defaultBackendSharedSecret, err := generateCredential(o.APIManagerSpec, appsv1alpha1.SharedSecretCredentialType)
+	if err != nil {
+		return err
+	}
 
+	currSecret, err := getSecret(component.SystemSecretSystemEventsHookSecretName, o.Namespace, o.Client)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			builder.BackendSharedSecret(defaultBackendSharedSecret)
@@ -170,20 +173,43 @@ func (o *OperatorSystemOptionsProvider) setSystemRedisOptions(builder *component
 			builder.MessageBusRedisNamespace(*result)
 		}
 
+		result = getSecretDataValue(secretData, component.SystemSecretSystemRedisSentinelHosts)
+		if result != nil {
+			builder.RedisSentinelHosts(*result)
+		}
+
+		result = getSecretDataValue(secretData, component.SystemSecretSystemRedisSentinelRole)
+		if result != nil {
+			builder.RedisSentinelRole(*result)
+		}
+
+		result = getSecretDataValue(secretData, component.SystemSecretSystemRedisMessageBusSentinelHosts)
+		if result != nil {
+			builder.MessageBusRedisSentinelHosts(*result)
+		}
+
+		result = getSecretDataValue(secretData, component.SystemSecretSystemRedisMessageBusSentinelRole)
+		if result != nil {
+			builder.MessageBusRedisSentinelRole(*result)
+		}
+
 	}
 	return nil
 }
 
 func (o *OperatorSystemOptionsProvider) setSystemAppOptions(builder *component.SystemOptionsBuilder) error {
-	currSecret, err := getSecret(component.SystemSecretSystemAppSecretName, o.Namespace, o.Client)
 	// TODO is not exactly what we were generating
 	// in OpenShift templates. We were generating
 	// '[a-f0-9]{128}' . Ask system if there's some reason
 	// for that and if we can change it. If must be that range
 	// then we should create another function to generate
 	// hexadecimal lowercase string output
-	defaultSecretKeyBase := oprand.String(128)
+	defaultSecretKeyBase, err := generateCredential(o.APIManagerSpec, appsv1alpha1.SecretKeyBaseCredentialType)
+	if err != nil {
+		return err
+	}
 
+	currSecret, err := getSecret(component.SystemSecretSystemAppSecretName, o.Namespace, o.Client)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Do nothing because there are no required options for related to the Memcached servers secret
@@ -200,15 +226,28 @@ func (o *OperatorSystemOptionsProvider) setSystemAppOptions(builder *component.S
 }
 
 func (o *OperatorSystemOptionsProvider) setSystemSeedOptions(builder *component.SystemOptionsBuilder) error {
+	defaultMasterPassword, err := generateCredential(o.APIManagerSpec, appsv1alpha1.PasswordCredentialType)
+	if err != nil {
+		return err
+	}
+	defaultAdminPassword, err := generateCredential(o.APIManagerSpec, appsv1alpha1.PasswordCredentialType)
+	if err != nil {
+		return err
+	}
+	defaultAdminAccessToken, err := generateCredential(o.APIManagerSpec, appsv1alpha1.AccessTokenCredentialType)
+	if err != nil {
+		return err
+	}
+	defaultMasterAccessToken, err := generateCredential(o.APIManagerSpec, appsv1alpha1.AccessTokenCredentialType)
+	if err != nil {
+		return err
+	}
+
 	currSecret, err := getSecret(component.SystemSecretSystemSeedSecretName, o.Namespace, o.Client)
 	defaultMasterDomain := "master"
 	defaultMasterUser := "master"
-	defaultMasterPassword := oprand.String(8)
 	defaultAdminUser := "admin"
-	defaultAdminPassword := oprand.String(8)
-	defaultAdminAccessToken := oprand.String(16)
 	//defaultSeedTenantName := *o.APIManagerSpec.TenantName // Fix this. Why is TENANT_NAME a secret in system seed? Does not seem a secret so should be directly gathered from the value
-	defaultMasterAccessToken := oprand.String(8)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Do nothing because there are no required options for related to the Memcached servers secret
@@ -241,9 +280,12 @@ func (o *OperatorSystemOptionsProvider) setSystemSeedOptions(builder *component.
 }
 
 func (o *OperatorSystemOptionsProvider) setSystemMasterApicastOptions(builder *component.SystemOptionsBuilder) error {
-	currSecret, err := getSecret(component.SystemSecretSystemMasterApicastSecretName, o.Namespace, o.Client)
-	defaultSystemMasterApicastAccessToken := oprand.String(8)
+	defaultSystemMasterApicastAccessToken, err := generateCredential(o.APIManagerSpec, appsv1alpha1.AccessTokenCredentialType)
+	if err != nil {
+		return err
+	}
 
+	currSecret, err := getSecret(component.SystemSecretSystemMasterApicastSecretName, o.Namespace, o.Client)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Do nothing because there are no required options for related to the secret
@@ -4,13 +4,12 @@ import (
 	"fmt"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
-	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 )
 
 func (o *OperatorSystemMySQLImageOptionsProvider) GetSystemMySQLImageOptions() (*component.SystemMySQLImageOptions, error) {
 	optProv := component.SystemMySQLImageOptionsBuilder{}
 	productVersion := o.APIManagerSpec.ProductVersion
-	imageProvider, err := product.NewImageProvider(productVersion)
+	imageProvider, err := newImageProvider(o.APIManagerSpec)
 	if err != nil {
 		return nil, err
 	}
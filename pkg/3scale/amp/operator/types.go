@@ -86,3 +86,21 @@ type OperatorHighAvailabilityOptionsProvider struct {
 	Namespace      string
 	Client         k8sclient.Client
 }
+
+type OperatorRedisSentinelOptionsProvider struct {
+	APIManagerSpec *appsv1alpha1.APIManagerSpec
+}
+
+type OperatorStorageOptionsProvider struct {
+	APIManagerSpec *appsv1alpha1.APIManagerSpec
+}
+
+type OperatorImagePullSecretsOptionsProvider struct {
+	APIManagerSpec *appsv1alpha1.APIManagerSpec
+}
+
+type OperatorRoutesOptionsProvider struct {
+	APIManagerSpec *appsv1alpha1.APIManagerSpec
+	Namespace      string
+	Client         k8sclient.Client
+}
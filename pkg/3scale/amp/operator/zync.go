@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
-	oprand "github.com/3scale/3scale-operator/pkg/crypto/rand"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/errors"
 )
 
@@ -35,9 +35,18 @@ func (o *OperatorZyncOptionsProvider) setSecretBasedOptions(zob *component.ZyncO
 }
 
 func (o *OperatorZyncOptionsProvider) setZyncSecretOptions(zob *component.ZyncOptionsBuilder) error {
-	defaultZyncSecretKeyBase := oprand.String(16)
-	defaultZyncDatabasePassword := oprand.String(16)
-	defaultZyncAuthenticationToken := oprand.String(16)
+	defaultZyncSecretKeyBase, err := generateCredential(o.APIManagerSpec, appsv1alpha1.SecretKeyBaseCredentialType)
+	if err != nil {
+		return err
+	}
+	defaultZyncDatabasePassword, err := generateCredential(o.APIManagerSpec, appsv1alpha1.PasswordCredentialType)
+	if err != nil {
+		return err
+	}
+	defaultZyncAuthenticationToken, err := generateCredential(o.APIManagerSpec, appsv1alpha1.AccessTokenCredentialType)
+	if err != nil {
+		return err
+	}
 
 	currSecret, err := getSecret(component.ZyncSecretName, o.Namespace, o.Client)
 	if err != nil {
@@ -0,0 +1,194 @@
+package product
+
+import (
+	"fmt"
+	"sync"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// FeatureProductized marks the releases using the Red Hat product images
+	FeatureProductized = "productized"
+)
+
+// Catalog lists the product releases the operator and the templates can be
+// generated for
+type Catalog struct {
+	// LatestRelease is the release of the productized templates
+	LatestRelease Version   `yaml:"latestRelease"`
+	Releases      []Release `yaml:"releases"`
+}
+
+// Release is a product release of the catalog
+type Release struct {
+	Version Version       `yaml:"version"`
+	Images  ReleaseImages `yaml:"images"`
+	// UpgradesFrom are the releases that can be upgraded to this release
+	UpgradesFrom []Version       `yaml:"upgradesFrom,omitempty"`
+	Features     map[string]bool `yaml:"features,omitempty"`
+}
+
+type ReleaseImages struct {
+	Apicast          string `yaml:"apicast"`
+	Backend          string `yaml:"backend"`
+	BackendRedis     string `yaml:"backendRedis"`
+	System           string `yaml:"system"`
+	SystemRedis      string `yaml:"systemRedis"`
+	SystemMySQL      string `yaml:"systemMySQL"`
+	SystemPostgreSQL string `yaml:"systemPostgreSQL"`
+	SystemMemcached  string `yaml:"systemMemcached"`
+	WildcardRouter   string `yaml:"wildcardRouter"`
+	Zync             string `yaml:"zync"`
+	ZyncPostgreSQL   string `yaml:"zyncPostgreSQL"`
+}
+
+// builtinCatalog is the catalog built into the operator
+var builtinCatalog = mustParseCatalog(defaultCatalog)
+
+// catalog is the catalog in use. It is the built-in catalog merged with the
+// last loaded override
+var catalog = builtinCatalog
+
+// catalogLock guards catalog, which the operator reloads while running
+var catalogLock sync.RWMutex
+
+func currentCatalog() *Catalog {
+	catalogLock.RLock()
+	defer catalogLock.RUnlock()
+	return catalog
+}
+
+func mustParseCatalog(data string) *Catalog {
+	c, err := ParseCatalog([]byte(data))
+	if err != nil {
+		panic(fmt.Sprintf("invalid release catalog: %v", err))
+	}
+	return c
+}
+
+// ParseCatalog parses and validates a YAML or JSON release catalog
+func ParseCatalog(data []byte) (*Catalog, error) {
+	c := &Catalog{}
+	err := yaml.UnmarshalStrict(data, c)
+	if err != nil {
+		return nil, err
+	}
+	return c, c.validate()
+}
+
+func (c *Catalog) validate() error {
+	versions := map[Version]bool{}
+	for _, release := range c.Releases {
+		if release.Version == "" {
+			return fmt.Errorf("a release has no version")
+		}
+		if versions[release.Version] {
+			return fmt.Errorf("release '%s' is listed more than once", release.Version)
+		}
+		versions[release.Version] = true
+
+		images := map[string]string{
+			"apicast":          release.Images.Apicast,
+			"backend":          release.Images.Backend,
+			"backendRedis":     release.Images.BackendRedis,
+			"system":           release.Images.System,
+			"systemRedis":      release.Images.SystemRedis,
+			"systemMySQL":      release.Images.SystemMySQL,
+			"systemPostgreSQL": release.Images.SystemPostgreSQL,
+			"systemMemcached":  release.Images.SystemMemcached,
+			"wildcardRouter":   release.Images.WildcardRouter,
+			"zync":             release.Images.Zync,
+			"zyncPostgreSQL":   release.Images.ZyncPostgreSQL,
+		}
+		for name, image := range images {
+			if image == "" {
+				return fmt.Errorf("release '%s' has no %s image", release.Version, name)
+			}
+		}
+	}
+	if c.LatestRelease != "" && !versions[c.LatestRelease] {
+		return fmt.Errorf("latest release '%s' is not in the catalog", c.LatestRelease)
+	}
+	return nil
+}
+
+// merge returns a catalog with the releases of both catalogs. The releases
+// and the latest release of the override take precedence
+func (c *Catalog) merge(override *Catalog) *Catalog {
+	result := &Catalog{LatestRelease: c.LatestRelease}
+	if override.LatestRelease != "" {
+		result.LatestRelease = override.LatestRelease
+	}
+
+	overridden := map[Version]bool{}
+	for _, release := range override.Releases {
+		overridden[release.Version] = true
+	}
+	for _, release := range c.Releases {
+		if !overridden[release.Version] {
+			result.Releases = append(result.Releases, release)
+		}
+	}
+	result.Releases = append(result.Releases, override.Releases...)
+	return result
+}
+
+// LoadCatalogOverride adds the releases of the catalog data to the built-in
+// catalog, replacing the ones with the same version, and uses the result as
+// the catalog. A later override replaces the previous one. An invalid
+// override leaves the catalog in use unchanged
+func LoadCatalogOverride(data []byte) error {
+	override, err := ParseCatalog(data)
+	if err != nil {
+		return fmt.Errorf("invalid release catalog override: %v", err)
+	}
+	merged := builtinCatalog.merge(override)
+	if err := merged.validate(); err != nil {
+		return fmt.Errorf("invalid release catalog override: %v", err)
+	}
+
+	catalogLock.Lock()
+	defer catalogLock.Unlock()
+	catalog = merged
+	return nil
+}
+
+// GetRelease returns the release of the catalog with the given version
+func GetRelease(productVersion Version) (*Release, error) {
+	current := currentCatalog()
+	for idx := range current.Releases {
+		if current.Releases[idx].Version == productVersion {
+			return &current.Releases[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("Product version '%s' is not a valid product version", productVersion)
+}
+
+// LatestRelease returns the release of the productized templates
+func LatestRelease() Version {
+	return currentCatalog().LatestRelease
+}
+
+// CanUpgrade returns whether a deployment of the 'from' release can be
+// upgraded to the 'to' release
+func CanUpgrade(from, to Version) bool {
+	if from == to {
+		return true
+	}
+	release, err := GetRelease(to)
+	if err != nil {
+		return false
+	}
+	for _, version := range release.UpgradesFrom {
+		if version == from {
+			return true
+		}
+	}
+	return false
+}
+
+// FeatureEnabled returns whether the release enables the feature flag
+func (r *Release) FeatureEnabled(feature string) bool {
+	return r.Features[feature]
+}
@@ -0,0 +1,38 @@
+package product
+
+// defaultCatalog is the release catalog built into the operator and the
+// template generator
+const defaultCatalog = `
+latestRelease: "2.5"
+releases:
+- version: "2.5"
+  upgradesFrom:
+  - "2.4"
+  features:
+    productized: true
+  images:
+    apicast: registry.access.redhat.com/3scale-amp25/apicast-gateway
+    backend: registry.access.redhat.com/3scale-amp25/backend
+    backendRedis: registry.access.redhat.com/rhscl/redis-32-rhel7:3.2
+    system: registry.access.redhat.com/3scale-amp25/system
+    systemRedis: registry.access.redhat.com/rhscl/redis-32-rhel7:3.2
+    systemMySQL: registry.access.redhat.com/rhscl/mysql-57-rhel7:5.7
+    systemPostgreSQL: registry.access.redhat.com/rhscl/postgresql-10-rhel7
+    systemMemcached: registry.access.redhat.com/3scale-amp20/memcached
+    wildcardRouter: registry.access.redhat.com/3scale-amp22/wildcard-router
+    zync: registry.access.redhat.com/3scale-amp25/zync
+    zyncPostgreSQL: registry.access.redhat.com/rhscl/postgresql-10-rhel7
+- version: upstream
+  images:
+    apicast: quay.io/3scale/apicast:nightly
+    backend: quay.io/3scale/apisonator:nightly
+    backendRedis: registry.access.redhat.com/rhscl/redis-32-rhel7:3.2
+    system: quay.io/3scale/porta:nightly
+    systemRedis: registry.access.redhat.com/rhscl/redis-32-rhel7:3.2
+    systemMySQL: registry.access.redhat.com/rhscl/mysql-57-rhel7:5.7
+    systemPostgreSQL: registry.access.redhat.com/rhscl/postgresql-10-rhel7
+    systemMemcached: registry.access.redhat.com/3scale-amp20/memcached
+    wildcardRouter: quay.io/3scale/wildcard-router:nightly
+    zync: quay.io/3scale/zync:nightly
+    zyncPostgreSQL: registry.access.redhat.com/rhscl/postgresql-10-rhel7
+`
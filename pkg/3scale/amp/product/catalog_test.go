@@ -0,0 +1,121 @@
+package product
+
+import (
+	"testing"
+)
+
+const testCatalogOverride = `
+latestRelease: "2.5.1"
+releases:
+- version: "2.5.1"
+  upgradesFrom:
+  - "2.5"
+  features:
+    productized: true
+  images:
+    apicast: registry.example.com/3scale-amp25/apicast-gateway:1.10-4
+    backend: registry.example.com/3scale-amp25/backend:2.5-2
+    backendRedis: registry.example.com/rhscl/redis-32-rhel7:3.2
+    system: registry.example.com/3scale-amp25/system:2.5-3
+    systemRedis: registry.example.com/rhscl/redis-32-rhel7:3.2
+    systemMySQL: registry.example.com/rhscl/mysql-57-rhel7:5.7
+    systemPostgreSQL: registry.example.com/rhscl/postgresql-10-rhel7
+    systemMemcached: registry.example.com/3scale-amp20/memcached
+    wildcardRouter: registry.example.com/3scale-amp22/wildcard-router
+    zync: registry.example.com/3scale-amp25/zync:2.5-1
+    zyncPostgreSQL: registry.example.com/rhscl/postgresql-10-rhel7
+`
+
+func TestDefaultCatalog(t *testing.T) {
+	for _, version := range []Version{ProductRelease_2_5, ProductUpstream} {
+		if _, err := NewImageProvider(version); err != nil {
+			t.Errorf("release %s: %v", version, err)
+		}
+	}
+	if !IsProductizedVersion(ProductRelease_2_5) {
+		t.Errorf("release %s is expected to be productized", ProductRelease_2_5)
+	}
+	if IsProductizedVersion(ProductUpstream) {
+		t.Errorf("release %s is not expected to be productized", ProductUpstream)
+	}
+	if _, err := NewImageProvider("1.0"); err == nil {
+		t.Error("expected an error for a release not in the catalog")
+	}
+}
+
+func TestLoadCatalogOverride(t *testing.T) {
+	defaultCatalog := catalog
+	defer func() { catalog = defaultCatalog }()
+
+	if err := LoadCatalogOverride([]byte(testCatalogOverride)); err != nil {
+		t.Fatal(err)
+	}
+
+	if LatestRelease() != "2.5.1" {
+		t.Errorf("expected latest release 2.5.1, got %s", LatestRelease())
+	}
+	imageProvider, err := NewImageProvider("2.5.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image := imageProvider.GetSystemImage(); image != "registry.example.com/3scale-amp25/system:2.5-3" {
+		t.Errorf("unexpected system image %s", image)
+	}
+	if _, err := NewImageProvider(ProductRelease_2_5); err != nil {
+		t.Errorf("release %s is expected to be kept: %v", ProductRelease_2_5, err)
+	}
+
+	cases := []struct {
+		from     Version
+		to       Version
+		expected bool
+	}{
+		{"2.5", "2.5.1", true},
+		{"2.5.1", "2.5.1", true},
+		{"upstream", "2.5.1", false},
+		{"2.5.1", "2.5", false},
+	}
+	for _, tc := range cases {
+		if CanUpgrade(tc.from, tc.to) != tc.expected {
+			t.Errorf("upgrade from %s to %s: expected %t", tc.from, tc.to, tc.expected)
+		}
+	}
+}
+
+func TestReloadCatalogOverride(t *testing.T) {
+	defaultCatalog := catalog
+	defer func() { catalog = defaultCatalog }()
+
+	if err := LoadCatalogOverride([]byte(testCatalogOverride)); err != nil {
+		t.Fatal(err)
+	}
+	// The releases of the previous override are replaced
+	if err := LoadCatalogOverride([]byte(`releases: []`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetRelease("2.5.1"); err == nil {
+		t.Error("expected the release of the previous override to be removed")
+	}
+	if LatestRelease() != defaultCatalog.LatestRelease {
+		t.Errorf("expected latest release %s, got %s", defaultCatalog.LatestRelease, LatestRelease())
+	}
+}
+
+func TestLoadInvalidCatalogOverride(t *testing.T) {
+	defaultCatalog := catalog
+	defer func() { catalog = defaultCatalog }()
+
+	invalidCatalogs := []string{
+		`releases: [{version: "2.6"}]`,
+		`latestRelease: "2.7"`,
+		`unknownField: true`,
+	}
+	for _, data := range invalidCatalogs {
+		if err := LoadCatalogOverride([]byte(data)); err == nil {
+			t.Errorf("expected an error loading %q", data)
+		}
+	}
+	if catalog != defaultCatalog {
+		t.Error("invalid catalog overrides must not change the catalog")
+	}
+}
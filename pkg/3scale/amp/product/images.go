@@ -1,7 +1,5 @@
 package product
 
-import "fmt"
-
 type Version string
 
 const (
@@ -10,14 +8,11 @@ const (
 )
 
 func NewImageProvider(productVersion Version) (ImageProvider, error) {
-	switch productVersion {
-	case ProductRelease_2_5:
-		return &release_2_5{}, nil
-	case ProductUpstream:
-		return &upstream{}, nil
-	default:
-		return nil, fmt.Errorf("Product version '%s' is not a valid product version", productVersion)
+	release, err := GetRelease(productVersion)
+	if err != nil {
+		return nil, err
 	}
+	return &releaseImageProvider{images: release.Images}, nil
 }
 
 type ImageProvider interface {
@@ -35,5 +30,58 @@ type ImageProvider interface {
 }
 
 func IsProductizedVersion(productVersion Version) bool {
-	return productVersion != ProductUpstream
+	release, err := GetRelease(productVersion)
+	if err != nil {
+		return false
+	}
+	return release.FeatureEnabled(FeatureProductized)
+}
+
+// releaseImageProvider provides the images of a release of the catalog
+type releaseImageProvider struct {
+	images ReleaseImages
+}
+
+func (p *releaseImageProvider) GetApicastImage() string {
+	return p.images.Apicast
+}
+
+func (p *releaseImageProvider) GetBackendImage() string {
+	return p.images.Backend
+}
+
+func (p *releaseImageProvider) GetBackendRedisImage() string {
+	return p.images.BackendRedis
+}
+
+func (p *releaseImageProvider) GetSystemImage() string {
+	return p.images.System
+}
+
+func (p *releaseImageProvider) GetSystemRedisImage() string {
+	return p.images.SystemRedis
+}
+
+func (p *releaseImageProvider) GetSystemMySQLImage() string {
+	return p.images.SystemMySQL
+}
+
+func (p *releaseImageProvider) GetSystemPostgreSQLImage() string {
+	return p.images.SystemPostgreSQL
+}
+
+func (p *releaseImageProvider) GetSystemMemcachedImage() string {
+	return p.images.SystemMemcached
+}
+
+func (p *releaseImageProvider) GetWildcardRouterImage() string {
+	return p.images.WildcardRouter
+}
+
+func (p *releaseImageProvider) GetZyncImage() string {
+	return p.images.Zync
+}
+
+func (p *releaseImageProvider) GetZyncPostgreSQLImage() string {
+	return p.images.ZyncPostgreSQL
 }
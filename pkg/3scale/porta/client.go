@@ -0,0 +1,129 @@
+package porta
+
+import (
+	"crypto/tls"
+
+	"github.com/3scale/3scale-porta-go-client/client"
+)
+
+// Client defines the 3scale admin portal operations used by the operator.
+// It is implemented by *ThreeScaleClient and by the in-memory
+// admin portal in the fake package
+type Client interface {
+	ServiceClient
+	ProxyClient
+	MetricClient
+	PlanClient
+	LimitClient
+	MappingRuleClient
+	TenantClient
+	UserClient
+	ApplicationClient
+	AccessTokenClient
+}
+
+// ServiceClient defines the 3scale service operations
+type ServiceClient interface {
+	CreateService(name string) (client.Service, error)
+	UpdateService(id string, params client.Params) (client.Service, error)
+	DeleteService(id string) error
+	ListServices() (client.ServiceList, error)
+}
+
+// ProxyClient defines the 3scale service proxy operations
+type ProxyClient interface {
+	ReadProxy(svcID string) (client.Proxy, error)
+	UpdateProxy(svcID string, params client.Params) (client.Proxy, error)
+	GetLatestProxyConfig(svcID string, env string) (client.ProxyConfigElement, error)
+	PromoteProxyConfig(svcID string, env string, version string, toEnv string) (client.ProxyConfigElement, error)
+}
+
+// MetricClient defines the 3scale metric operations
+type MetricClient interface {
+	CreateMetric(svcID string, name string, description string, unit string) (client.Metric, error)
+	UpdateMetric(svcID string, id string, params client.Params) (client.Metric, error)
+	DeleteMetric(svcID string, id string) error
+	ListMetrics(svcID string) (client.MetricList, error)
+}
+
+// PlanClient defines the 3scale application plan operations
+type PlanClient interface {
+	CreateAppPlan(svcID string, name string, stateEvent string) (client.Plan, error)
+	UpdateAppPlan(svcID string, appPlanID string, name string, stateEvent string, params client.Params) (client.Plan, error)
+	DeleteAppPlan(svcID string, appPlanID string) error
+	ListAppPlanByServiceId(svcID string) (client.ApplicationPlansList, error)
+	SetDefaultPlan(svcID string, id string) (client.Plan, error)
+}
+
+// LimitClient defines the 3scale application plan limit operations
+type LimitClient interface {
+	CreateLimitAppPlan(appPlanID string, metricID string, period string, value int) (client.Limit, error)
+	UpdateLimitPerAppPlan(appPlanID string, metricID string, limitID string, p client.Params) (client.Limit, error)
+	DeleteLimitPerAppPlan(appPlanID string, metricID string, limitID string) error
+	ListLimitsPerAppPlan(appPlanID string) (client.LimitList, error)
+}
+
+// MappingRuleClient defines the 3scale proxy mapping rule operations
+type MappingRuleClient interface {
+	CreateMappingRule(svcID string, method string, pattern string, delta int, metricID string) (client.MappingRule, error)
+	UpdateMappingRule(svcID string, id string, params client.Params) (client.MappingRule, error)
+	DeleteMappingRule(svcID string, id string) error
+	ListMappingRule(svcID string) (client.MappingRuleList, error)
+}
+
+// TenantClient defines the 3scale master tenant operations
+type TenantClient interface {
+	CreateTenant(orgName, username, email, password string) (*client.Tenant, error)
+	ShowTenant(tenantID int64) (*client.Tenant, error)
+	UpdateTenant(tenantID int64, params client.Params) (*client.Tenant, error)
+	DeleteTenant(tenantID int64) error
+	SuspendTenant(tenantID int64) error
+}
+
+// UserClient defines the 3scale account user operations
+type UserClient interface {
+	ActivateUser(accountID, userID int64) error
+	ReadUser(accountID, userID int64) (*client.User, error)
+	ListUsers(accountID int64, filterParams client.Params) (*client.UserList, error)
+	UpdateUser(accountID int64, userID int64, userParams client.Params) (*client.User, error)
+	CreateUser(accountID int64, username, email, password string) (*client.User, error)
+	DeleteUser(accountID, userID int64) error
+	SuspendUser(accountID, userID int64) error
+	UnsuspendUser(accountID, userID int64) error
+	ChangeUserRole(accountID, userID int64, role string) error
+}
+
+// ApplicationClient defines the 3scale application operations
+type ApplicationClient interface {
+	ListApplications(accountID int64) (*client.ApplicationList, error)
+}
+
+// AccessTokenClient defines the 3scale provider user access token operations
+type AccessTokenClient interface {
+	ListProviderUsers() (*client.UserList, error)
+	CreateAccessToken(userID int64, name, permission string, scopes []string) (*AccessToken, error)
+	// ShowAccessToken and DeleteAccessToken operate on the access tokens of the user
+	// owning the client access token, identified by ID or value
+	ShowAccessToken(idOrValue string) (*AccessToken, error)
+	DeleteAccessToken(idOrValue string) error
+}
+
+// AccessToken is a provider user access token.
+// Value is only returned when the access token is created
+type AccessToken struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Permission string   `json:"permission"`
+	Scopes     []string `json:"scopes"`
+	Value      string   `json:"value"`
+}
+
+// AccessTokenElem wraps the access token in admin API responses
+type AccessTokenElem struct {
+	AccessToken AccessToken `json:"access_token"`
+}
+
+// ClientFactory builds a Client for the admin portal at the given url,
+// authenticated with the given access token and verifying the admin portal
+// certificate with the given tls config
+type ClientFactory func(adminURLStr, accessToken string, tlsConfig *tls.Config) (Client, error)
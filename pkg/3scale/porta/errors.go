@@ -0,0 +1,45 @@
+package porta
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/3scale/3scale-porta-go-client/client"
+)
+
+// notFound is implemented by errors that mean the requested object
+// does not exist in 3scale
+type notFound interface {
+	NotFound() bool
+}
+
+// IsNotFound returns true if the error returned by a Client means
+// the requested object does not exist in 3scale
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if client.IsNotFound(err) {
+		return true
+	}
+
+	e, ok := err.(notFound)
+	return ok && e.NotFound()
+}
+
+// APIError is returned by ThreeScaleClient when the admin portal
+// answers with an unexpected status code
+type APIError struct {
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error calling 3scale system - reason: %s - code: %d", e.Message, e.Code)
+}
+
+// NotFound returns true when the requested object does not exist
+func (e *APIError) NotFound() bool {
+	return e.Code == http.StatusNotFound
+}
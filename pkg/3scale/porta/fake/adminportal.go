@@ -0,0 +1,1038 @@
+package fake
+
+import (
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
+	"github.com/3scale/3scale-porta-go-client/client"
+)
+
+// NotFoundError is returned by the AdminPortal when the requested object
+// does not exist. porta.IsNotFound returns true for it
+type NotFoundError struct {
+	Kind string
+	ID   string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("NotFound: %s %s", e.Kind, e.ID)
+}
+
+// NotFound marks the error as a not found error
+func (e NotFoundError) NotFound() bool {
+	return true
+}
+
+type service struct {
+	service      client.Service
+	proxy        client.Proxy
+	proxyConfigs map[string]int
+	metrics      []client.Metric
+	mappingRules []client.MappingRule
+	plans        []client.Plan
+}
+
+type tenant struct {
+	tenant      client.Tenant
+	users       []client.User
+	roles       map[int64]string
+	providerKey string
+}
+
+// AdminPortal is an in-memory 3scale admin portal implementing porta.Client.
+// It keeps services, proxies, metrics, application plans, limits,
+// mapping rules, tenants and users, and behaves like a real admin portal
+// for the operations used by the operator, so reconciliation logic can be
+// exercised without a running 3scale.
+// It is safe for concurrent use
+type AdminPortal struct {
+	mu            sync.Mutex
+	lastID        int64
+	services      []*service
+	limits        map[string][]client.Limit
+	tenants       map[int64]*tenant
+	providerUsers []client.User
+	accessTokens  map[int64][]porta.AccessToken
+}
+
+// blank assignment to verify that AdminPortal implements porta.Client
+var _ porta.Client = &AdminPortal{}
+
+// NewAdminPortal returns an empty in-memory admin portal
+func NewAdminPortal() *AdminPortal {
+	return &AdminPortal{
+		limits:       map[string][]client.Limit{},
+		tenants:      map[int64]*tenant{},
+		accessTokens: map[int64][]porta.AccessToken{},
+	}
+}
+
+// ClientFactory returns a function building porta clients that always
+// talk to this admin portal, regardless of the admin url, access token and tls config
+func (a *AdminPortal) ClientFactory() porta.ClientFactory {
+	return func(string, string, *tls.Config) (porta.Client, error) {
+		return a, nil
+	}
+}
+
+func (a *AdminPortal) nextID() int64 {
+	a.lastID++
+	return a.lastID
+}
+
+func (a *AdminPortal) nextStringID() string {
+	return strconv.FormatInt(a.nextID(), 10)
+}
+
+func (a *AdminPortal) findService(id string) (*service, error) {
+	for _, s := range a.services {
+		if s.service.ID == id {
+			return s, nil
+		}
+	}
+	return nil, NotFoundError{Kind: "service", ID: id}
+}
+
+func (a *AdminPortal) findPlan(appPlanID string) (*service, int, error) {
+	for _, s := range a.services {
+		for idx := range s.plans {
+			if s.plans[idx].ID == appPlanID {
+				return s, idx, nil
+			}
+		}
+	}
+	return nil, 0, NotFoundError{Kind: "application plan", ID: appPlanID}
+}
+
+func (a *AdminPortal) findTenant(tenantID int64) (*tenant, error) {
+	t, ok := a.tenants[tenantID]
+	if !ok {
+		return nil, NotFoundError{Kind: "tenant", ID: strconv.FormatInt(tenantID, 10)}
+	}
+	return t, nil
+}
+
+// setXMLFields sets the string fields of obj whose xml tag matches a param key
+func setXMLFields(obj interface{}, params client.Params) {
+	v := reflect.ValueOf(obj).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("xml"), ",")[0]
+		value, ok := params[tag]
+		if ok && v.Field(i).Kind() == reflect.String {
+			v.Field(i).SetString(value)
+		}
+	}
+}
+
+func systemName(name string) string {
+	return strings.Replace(strings.ToLower(name), " ", "_", -1)
+}
+
+// CreateService creates a service with the default "hits" metric and
+// the default "GET /" mapping rule, like 3scale does
+func (a *AdminPortal) CreateService(name string) (client.Service, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, s := range a.services {
+		if s.service.SystemName == name {
+			return client.Service{}, fmt.Errorf("system_name has already been taken: %s", name)
+		}
+	}
+
+	s := &service{
+		service: client.Service{
+			ID:               a.nextStringID(),
+			AccountID:        "1",
+			Name:             name,
+			SystemName:       name,
+			State:            "incomplete",
+			DeploymentOption: "hosted",
+			BackendVersion:   "1",
+		},
+		proxyConfigs: map[string]int{},
+	}
+	s.proxy = client.Proxy{
+		XMLName:             xml.Name{Local: "proxy"},
+		ServiceID:           s.service.ID,
+		CredentialsLocation: "query",
+		AuthUserKey:         "user_key",
+		AuthAppID:           "app_id",
+		AuthAppKey:          "app_key",
+	}
+	hits := client.Metric{
+		XMLName:      xml.Name{Local: "metric"},
+		ID:           a.nextStringID(),
+		MetricName:   "hits",
+		SystemName:   "hits",
+		FriendlyName: "Hits",
+		ServiceID:    s.service.ID,
+		Description:  "Number of API hits",
+		Unit:         "hit",
+	}
+	s.metrics = append(s.metrics, hits)
+	s.mappingRules = append(s.mappingRules, client.MappingRule{
+		XMLName:    xml.Name{Local: "mapping_rule"},
+		ID:         a.nextStringID(),
+		MetricID:   hits.ID,
+		Pattern:    "/",
+		HTTPMethod: "GET",
+		Delta:      "1",
+	})
+	a.services = append(a.services, s)
+
+	return s.service, nil
+}
+
+// UpdateService updates the service attributes given in params
+func (a *AdminPortal) UpdateService(id string, params client.Params) (client.Service, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(id)
+	if err != nil {
+		return client.Service{}, err
+	}
+	setXMLFields(&s.service, params)
+	return s.service, nil
+}
+
+// DeleteService deletes the service with all its plans and limits
+func (a *AdminPortal) DeleteService(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for idx, s := range a.services {
+		if s.service.ID == id {
+			for _, plan := range s.plans {
+				delete(a.limits, plan.ID)
+			}
+			a.services = append(a.services[:idx], a.services[idx+1:]...)
+			return nil
+		}
+	}
+	return NotFoundError{Kind: "service", ID: id}
+}
+
+// ListServices lists all the services, including their metrics
+func (a *AdminPortal) ListServices() (client.ServiceList, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	list := client.ServiceList{}
+	for _, s := range a.services {
+		svc := s.service
+		svc.Metrics = client.MetricList{Metrics: append([]client.Metric{}, s.metrics...)}
+		list.Services = append(list.Services, svc)
+	}
+	return list, nil
+}
+
+// ReadProxy returns the service proxy
+func (a *AdminPortal) ReadProxy(svcID string) (client.Proxy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.Proxy{}, err
+	}
+	return s.proxy, nil
+}
+
+// UpdateProxy updates the proxy attributes given in params and deploys a
+// new sandbox proxy config version
+func (a *AdminPortal) UpdateProxy(svcID string, params client.Params) (client.Proxy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.Proxy{}, err
+	}
+	setXMLFields(&s.proxy, params)
+	s.proxyConfigs["sandbox"]++
+	return s.proxy, nil
+}
+
+// GetLatestProxyConfig returns the latest proxy config version of the environment
+func (a *AdminPortal) GetLatestProxyConfig(svcID string, env string) (client.ProxyConfigElement, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.ProxyConfigElement{}, err
+	}
+	version, ok := s.proxyConfigs[env]
+	if !ok {
+		return client.ProxyConfigElement{}, NotFoundError{Kind: "proxy config", ID: env}
+	}
+	return proxyConfigElement(env, version), nil
+}
+
+// PromoteProxyConfig promotes the proxy config version of env to toEnv
+func (a *AdminPortal) PromoteProxyConfig(svcID string, env string, version string, toEnv string) (client.ProxyConfigElement, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.ProxyConfigElement{}, err
+	}
+	v, err := strconv.Atoi(version)
+	if err != nil || s.proxyConfigs[env] < v || v < 1 {
+		return client.ProxyConfigElement{}, NotFoundError{Kind: "proxy config", ID: version}
+	}
+	s.proxyConfigs[toEnv] = v
+	return proxyConfigElement(toEnv, v), nil
+}
+
+func proxyConfigElement(env string, version int) client.ProxyConfigElement {
+	return client.ProxyConfigElement{
+		ProxyConfig: client.ProxyConfig{
+			Version:     version,
+			Environment: env,
+		},
+	}
+}
+
+// CreateMetric creates a metric in the service
+func (a *AdminPortal) CreateMetric(svcID string, name string, description string, unit string) (client.Metric, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.Metric{}, err
+	}
+	for _, m := range s.metrics {
+		if m.SystemName == systemName(name) {
+			return client.Metric{}, fmt.Errorf("system_name has already been taken: %s", name)
+		}
+	}
+	m := client.Metric{
+		XMLName:      xml.Name{Local: "metric"},
+		ID:           a.nextStringID(),
+		MetricName:   systemName(name),
+		SystemName:   systemName(name),
+		FriendlyName: name,
+		ServiceID:    svcID,
+		Description:  description,
+		Unit:         unit,
+	}
+	s.metrics = append(s.metrics, m)
+	return m, nil
+}
+
+// UpdateMetric updates the metric attributes given in params
+func (a *AdminPortal) UpdateMetric(svcID string, id string, params client.Params) (client.Metric, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.Metric{}, err
+	}
+	for idx := range s.metrics {
+		if s.metrics[idx].ID == id {
+			setXMLFields(&s.metrics[idx], params)
+			return s.metrics[idx], nil
+		}
+	}
+	return client.Metric{}, NotFoundError{Kind: "metric", ID: id}
+}
+
+// DeleteMetric deletes the metric with its mapping rules and limits
+func (a *AdminPortal) DeleteMetric(svcID string, id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return err
+	}
+	for idx := range s.metrics {
+		if s.metrics[idx].ID == id {
+			s.metrics = append(s.metrics[:idx], s.metrics[idx+1:]...)
+
+			mappingRules := []client.MappingRule{}
+			for _, mappingRule := range s.mappingRules {
+				if mappingRule.MetricID != id {
+					mappingRules = append(mappingRules, mappingRule)
+				}
+			}
+			s.mappingRules = mappingRules
+
+			for _, plan := range s.plans {
+				limits := []client.Limit{}
+				for _, limit := range a.limits[plan.ID] {
+					if limit.MetricID != id {
+						limits = append(limits, limit)
+					}
+				}
+				a.limits[plan.ID] = limits
+			}
+			return nil
+		}
+	}
+	return NotFoundError{Kind: "metric", ID: id}
+}
+
+// ListMetrics lists the metrics of the service
+func (a *AdminPortal) ListMetrics(svcID string) (client.MetricList, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.MetricList{}, err
+	}
+	return client.MetricList{Metrics: append([]client.Metric{}, s.metrics...)}, nil
+}
+
+// CreateAppPlan creates an application plan in the service
+func (a *AdminPortal) CreateAppPlan(svcID string, name string, stateEvent string) (client.Plan, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.Plan{}, err
+	}
+	plan := client.Plan{
+		XMLNameName:      xml.Name{Local: "plan"},
+		Custom:           "false",
+		ID:               a.nextStringID(),
+		PlanName:         name,
+		Type:             "application_plan",
+		State:            planState("hidden", stateEvent),
+		ServiceID:        svcID,
+		EndUserRequired:  "false",
+		ApprovalRequired: "false",
+		SetupFee:         "0.0",
+		CostPerMonth:     "0.0",
+		TrialPeriodDays:  "0",
+	}
+	s.plans = append(s.plans, plan)
+	return plan, nil
+}
+
+// UpdateAppPlan updates the application plan attributes given in params
+func (a *AdminPortal) UpdateAppPlan(svcID string, appPlanID string, name string, stateEvent string, params client.Params) (client.Plan, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, idx, err := a.findPlan(appPlanID)
+	if err != nil || s.service.ID != svcID {
+		return client.Plan{}, NotFoundError{Kind: "application plan", ID: appPlanID}
+	}
+	plan := &s.plans[idx]
+	setXMLFields(plan, params)
+	if name != "" {
+		plan.PlanName = name
+	}
+	plan.State = planState(plan.State, stateEvent)
+	return *plan, nil
+}
+
+func planState(current, stateEvent string) string {
+	switch stateEvent {
+	case "publish":
+		return "published"
+	case "hide":
+		return "hidden"
+	}
+	return current
+}
+
+// DeleteAppPlan deletes the application plan with its limits
+func (a *AdminPortal) DeleteAppPlan(svcID string, appPlanID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, idx, err := a.findPlan(appPlanID)
+	if err != nil || s.service.ID != svcID {
+		return NotFoundError{Kind: "application plan", ID: appPlanID}
+	}
+	s.plans = append(s.plans[:idx], s.plans[idx+1:]...)
+	delete(a.limits, appPlanID)
+	return nil
+}
+
+// ListAppPlanByServiceId lists the application plans of the service
+func (a *AdminPortal) ListAppPlanByServiceId(svcID string) (client.ApplicationPlansList, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.ApplicationPlansList{}, err
+	}
+	return client.ApplicationPlansList{Plans: append([]client.Plan{}, s.plans...)}, nil
+}
+
+// SetDefaultPlan makes the application plan the default one of the service
+func (a *AdminPortal) SetDefaultPlan(svcID string, id string) (client.Plan, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, idx, err := a.findPlan(id)
+	if err != nil || s.service.ID != svcID {
+		return client.Plan{}, NotFoundError{Kind: "application plan", ID: id}
+	}
+	for i := range s.plans {
+		s.plans[i].Default = i == idx
+	}
+	return s.plans[idx], nil
+}
+
+// CreateLimitAppPlan creates a limit for the metric in the application plan
+func (a *AdminPortal) CreateLimitAppPlan(appPlanID string, metricID string, period string, value int) (client.Limit, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, _, err := a.findPlan(appPlanID); err != nil {
+		return client.Limit{}, err
+	}
+	limit := client.Limit{
+		XMLName:  xml.Name{Local: "limit"},
+		ID:       a.nextStringID(),
+		MetricID: metricID,
+		PlanID:   appPlanID,
+		Period:   period,
+		Value:    strconv.Itoa(value),
+	}
+	a.limits[appPlanID] = append(a.limits[appPlanID], limit)
+	return limit, nil
+}
+
+// UpdateLimitPerAppPlan updates the limit attributes given in params
+func (a *AdminPortal) UpdateLimitPerAppPlan(appPlanID string, metricID string, limitID string, p client.Params) (client.Limit, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	limits := a.limits[appPlanID]
+	for idx := range limits {
+		if limits[idx].ID == limitID && limits[idx].MetricID == metricID {
+			setXMLFields(&limits[idx], p)
+			return limits[idx], nil
+		}
+	}
+	return client.Limit{}, NotFoundError{Kind: "limit", ID: limitID}
+}
+
+// DeleteLimitPerAppPlan deletes the limit from the application plan
+func (a *AdminPortal) DeleteLimitPerAppPlan(appPlanID string, metricID string, limitID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	limits := a.limits[appPlanID]
+	for idx := range limits {
+		if limits[idx].ID == limitID && limits[idx].MetricID == metricID {
+			a.limits[appPlanID] = append(limits[:idx], limits[idx+1:]...)
+			return nil
+		}
+	}
+	return NotFoundError{Kind: "limit", ID: limitID}
+}
+
+// ListLimitsPerAppPlan lists the limits of the application plan
+func (a *AdminPortal) ListLimitsPerAppPlan(appPlanID string) (client.LimitList, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, _, err := a.findPlan(appPlanID); err != nil {
+		return client.LimitList{}, err
+	}
+	return client.LimitList{Limits: append([]client.Limit{}, a.limits[appPlanID]...)}, nil
+}
+
+// CreateMappingRule creates a mapping rule in the service proxy
+func (a *AdminPortal) CreateMappingRule(svcID string, method string, pattern string, delta int, metricID string) (client.MappingRule, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.MappingRule{}, err
+	}
+	mappingRule := client.MappingRule{
+		XMLName:    xml.Name{Local: "mapping_rule"},
+		ID:         a.nextStringID(),
+		MetricID:   metricID,
+		Pattern:    pattern,
+		HTTPMethod: method,
+		Delta:      strconv.Itoa(delta),
+	}
+	s.mappingRules = append(s.mappingRules, mappingRule)
+	return mappingRule, nil
+}
+
+// UpdateMappingRule updates the mapping rule attributes given in params
+func (a *AdminPortal) UpdateMappingRule(svcID string, id string, params client.Params) (client.MappingRule, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.MappingRule{}, err
+	}
+	for idx := range s.mappingRules {
+		if s.mappingRules[idx].ID == id {
+			setXMLFields(&s.mappingRules[idx], params)
+			return s.mappingRules[idx], nil
+		}
+	}
+	return client.MappingRule{}, NotFoundError{Kind: "mapping rule", ID: id}
+}
+
+// DeleteMappingRule deletes the mapping rule from the service proxy
+func (a *AdminPortal) DeleteMappingRule(svcID string, id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return err
+	}
+	for idx := range s.mappingRules {
+		if s.mappingRules[idx].ID == id {
+			s.mappingRules = append(s.mappingRules[:idx], s.mappingRules[idx+1:]...)
+			return nil
+		}
+	}
+	return NotFoundError{Kind: "mapping rule", ID: id}
+}
+
+// ListMappingRule lists the mapping rules of the service proxy
+func (a *AdminPortal) ListMappingRule(svcID string) (client.MappingRuleList, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, err := a.findService(svcID)
+	if err != nil {
+		return client.MappingRuleList{}, err
+	}
+	return client.MappingRuleList{MappingRules: append([]client.MappingRule{}, s.mappingRules...)}, nil
+}
+
+// CreateTenant creates a tenant with a pending admin user and
+// its provider application
+func (a *AdminPortal) CreateTenant(orgName, username, email, password string) (*client.Tenant, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, t := range a.tenants {
+		if t.tenant.Signup.Account.OrgName == orgName {
+			return nil, fmt.Errorf("org_name has already been taken: %s", orgName)
+		}
+	}
+
+	accountID := a.nextID()
+	domain := fmt.Sprintf("%s.example.com", systemName(orgName))
+	t := &tenant{
+		tenant: client.Tenant{
+			Signup: client.Signup{
+				Account: client.Account{
+					ID:           accountID,
+					State:        "approved",
+					OrgName:      orgName,
+					SupportEmail: email,
+					AdminDomain:  fmt.Sprintf("%s-admin.example.com", systemName(orgName)),
+					Domain:       domain,
+				},
+				AccessToken: client.AccessToken{
+					ID:         a.nextID(),
+					Name:       "Administration",
+					Scopes:     []string{"account_management"},
+					Permission: "rw",
+					Value:      fmt.Sprintf("access-token-%d", accountID),
+				},
+			},
+		},
+		providerKey: fmt.Sprintf("provider-key-%d", accountID),
+		roles:       map[int64]string{},
+	}
+	adminID := a.nextID()
+	t.users = append(t.users, client.User{
+		ID:        adminID,
+		State:     "pending",
+		UserName:  username,
+		Email:     email,
+		AccountID: accountID,
+	})
+	t.roles[adminID] = "admin"
+	a.tenants[accountID] = t
+
+	tenantCopy := t.tenant
+	return &tenantCopy, nil
+}
+
+// ShowTenant returns the tenant
+func (a *AdminPortal) ShowTenant(tenantID int64) (*client.Tenant, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t, err := a.findTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	tenantCopy := t.tenant
+	return &tenantCopy, nil
+}
+
+// UpdateTenant updates the tenant account attributes given in params
+func (a *AdminPortal) UpdateTenant(tenantID int64, params client.Params) (*client.Tenant, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t, err := a.findTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if orgName, ok := params["org_name"]; ok {
+		t.tenant.Signup.Account.OrgName = orgName
+	}
+	if supportEmail, ok := params["support_email"]; ok {
+		t.tenant.Signup.Account.SupportEmail = supportEmail
+	}
+	tenantCopy := t.tenant
+	return &tenantCopy, nil
+}
+
+// DeleteTenant deletes the tenant with all its users
+func (a *AdminPortal) DeleteTenant(tenantID int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := a.findTenant(tenantID); err != nil {
+		return err
+	}
+	delete(a.tenants, tenantID)
+	return nil
+}
+
+// SuspendTenant suspends the tenant provider account
+func (a *AdminPortal) SuspendTenant(tenantID int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t, err := a.findTenant(tenantID)
+	if err != nil {
+		return err
+	}
+	t.tenant.Signup.Account.State = "suspended"
+	return nil
+}
+
+// ActivateUser activates a pending user
+func (a *AdminPortal) ActivateUser(accountID, userID int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	user, err := a.findUser(accountID, userID)
+	if err != nil {
+		return err
+	}
+	user.State = "active"
+	return nil
+}
+
+// ReadUser returns the account user
+func (a *AdminPortal) ReadUser(accountID, userID int64) (*client.User, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	user, err := a.findUser(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	userCopy := *user
+	return &userCopy, nil
+}
+
+// ListUsers lists the account users matching the "state" and "role" filters.
+// Users created with the tenant are admins, users created later are members
+func (a *AdminPortal) ListUsers(accountID int64, filterParams client.Params) (*client.UserList, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t, err := a.findTenant(accountID)
+	if err != nil {
+		return nil, err
+	}
+	list := &client.UserList{}
+	for _, user := range t.users {
+		if state, ok := filterParams["state"]; ok && state != user.State {
+			continue
+		}
+		if role, ok := filterParams["role"]; ok && role != t.roles[user.ID] {
+			continue
+		}
+		list.Users = append(list.Users, client.UserElem{User: user})
+	}
+	return list, nil
+}
+
+// UpdateUser updates the user attributes given in params
+func (a *AdminPortal) UpdateUser(accountID int64, userID int64, userParams client.Params) (*client.User, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	user, err := a.findUser(accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if username, ok := userParams["username"]; ok {
+		user.UserName = username
+	}
+	if email, ok := userParams["email"]; ok {
+		user.Email = email
+	}
+	userCopy := *user
+	return &userCopy, nil
+}
+
+// CreateUser creates a pending member user in the account
+func (a *AdminPortal) CreateUser(accountID int64, username, email, password string) (*client.User, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t, err := a.findTenant(accountID)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range t.users {
+		if user.UserName == username {
+			return nil, fmt.Errorf("username has already been taken: %s", username)
+		}
+	}
+
+	user := client.User{
+		ID:        a.nextID(),
+		State:     "pending",
+		UserName:  username,
+		Email:     email,
+		AccountID: accountID,
+	}
+	t.users = append(t.users, user)
+	t.roles[user.ID] = "member"
+	return &user, nil
+}
+
+// DeleteUser deletes the account user
+func (a *AdminPortal) DeleteUser(accountID, userID int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t, err := a.findTenant(accountID)
+	if err != nil {
+		return err
+	}
+	for idx := range t.users {
+		if t.users[idx].ID == userID {
+			t.users = append(t.users[:idx], t.users[idx+1:]...)
+			delete(t.roles, userID)
+			return nil
+		}
+	}
+	return NotFoundError{Kind: "user", ID: strconv.FormatInt(userID, 10)}
+}
+
+// SuspendUser suspends an active account user
+func (a *AdminPortal) SuspendUser(accountID, userID int64) error {
+	return a.transitionUser(accountID, userID, "active", "suspended")
+}
+
+// UnsuspendUser reactivates a suspended account user
+func (a *AdminPortal) UnsuspendUser(accountID, userID int64) error {
+	return a.transitionUser(accountID, userID, "suspended", "active")
+}
+
+func (a *AdminPortal) transitionUser(accountID, userID int64, from, to string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	user, err := a.findUser(accountID, userID)
+	if err != nil {
+		return err
+	}
+	if user.State != from {
+		return fmt.Errorf("user %d cannot transition from %s to %s", userID, user.State, to)
+	}
+	user.State = to
+	return nil
+}
+
+// ChangeUserRole changes the role of the account user
+func (a *AdminPortal) ChangeUserRole(accountID, userID int64, role string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if role != "admin" && role != "member" {
+		return fmt.Errorf("unknown user role: %s", role)
+	}
+	t, err := a.findTenant(accountID)
+	if err != nil {
+		return err
+	}
+	if _, err := a.findUser(accountID, userID); err != nil {
+		return err
+	}
+	t.roles[userID] = role
+	return nil
+}
+
+func (a *AdminPortal) findUser(accountID, userID int64) (*client.User, error) {
+	t, err := a.findTenant(accountID)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range t.users {
+		if t.users[idx].ID == userID {
+			return &t.users[idx], nil
+		}
+	}
+	return nil, NotFoundError{Kind: "user", ID: strconv.FormatInt(userID, 10)}
+}
+
+// ListApplications lists the account applications. Tenants have
+// a single provider application holding the provider key
+func (a *AdminPortal) ListApplications(accountID int64) (*client.ApplicationList, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t, err := a.findTenant(accountID)
+	if err != nil {
+		return nil, err
+	}
+	return &client.ApplicationList{
+		Applications: []client.ApplicationElem{
+			{
+				Application: client.Application{
+					ID:      accountID,
+					State:   "live",
+					UserKey: t.providerKey,
+				},
+			},
+		},
+	}, nil
+}
+
+// AddProviderUser adds an active user to the account owning the admin portal
+func (a *AdminPortal) AddProviderUser(username, email string) client.User {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	user := client.User{
+		ID:       a.nextID(),
+		State:    "active",
+		UserName: username,
+		Email:    email,
+	}
+	a.providerUsers = append(a.providerUsers, user)
+	return user
+}
+
+// ListProviderUsers lists the users of the account owning the admin portal
+func (a *AdminPortal) ListProviderUsers() (*client.UserList, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	list := &client.UserList{}
+	for _, user := range a.providerUsers {
+		list.Users = append(list.Users, client.UserElem{User: user})
+	}
+	return list, nil
+}
+
+// CreateAccessToken creates an access token with a unique value for the provider user
+func (a *AdminPortal) CreateAccessToken(userID int64, name, permission string, scopes []string) (*porta.AccessToken, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	found := false
+	for _, user := range a.providerUsers {
+		found = found || user.ID == userID
+	}
+	if !found {
+		return nil, NotFoundError{Kind: "user", ID: strconv.FormatInt(userID, 10)}
+	}
+
+	token := porta.AccessToken{
+		ID:         a.nextID(),
+		Name:       name,
+		Permission: permission,
+		Scopes:     scopes,
+		Value:      fmt.Sprintf("token-%d", a.lastID),
+	}
+	a.accessTokens[userID] = append(a.accessTokens[userID], token)
+	return &token, nil
+}
+
+func (a *AdminPortal) findAccessToken(idOrValue string) (int64, int, error) {
+	for userID, tokens := range a.accessTokens {
+		for idx, token := range tokens {
+			if strconv.FormatInt(token.ID, 10) == idOrValue || token.Value == idOrValue {
+				return userID, idx, nil
+			}
+		}
+	}
+	return 0, 0, NotFoundError{Kind: "access token", ID: idOrValue}
+}
+
+// AddAccessToken adds an access token with the given value to the provider user,
+// like the access tokens created when 3scale is deployed
+func (a *AdminPortal) AddAccessToken(userID int64, value string) porta.AccessToken {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	token := porta.AccessToken{ID: a.nextID(), Name: "seed", Permission: "rw", Value: value}
+	a.accessTokens[userID] = append(a.accessTokens[userID], token)
+	return token
+}
+
+// ListAccessTokens lists the access tokens of the provider user
+func (a *AdminPortal) ListAccessTokens(userID int64) []porta.AccessToken {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]porta.AccessToken{}, a.accessTokens[userID]...)
+}
+
+// ShowAccessToken reads an access token of any provider user, identified by ID or value.
+// The token value is not returned
+func (a *AdminPortal) ShowAccessToken(idOrValue string) (*porta.AccessToken, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	userID, idx, err := a.findAccessToken(idOrValue)
+	if err != nil {
+		return nil, err
+	}
+	token := a.accessTokens[userID][idx]
+	token.Value = ""
+	return &token, nil
+}
+
+// DeleteAccessToken revokes an access token of any provider user, identified by ID or value
+func (a *AdminPortal) DeleteAccessToken(idOrValue string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	userID, idx, err := a.findAccessToken(idOrValue)
+	if err != nil {
+		return err
+	}
+	tokens := a.accessTokens[userID]
+	a.accessTokens[userID] = append(tokens[:idx:idx], tokens[idx+1:]...)
+	return nil
+}
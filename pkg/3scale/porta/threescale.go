@@ -0,0 +1,185 @@
+package porta
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/3scale/3scale-porta-go-client/client"
+)
+
+const (
+	accountSuspend = "/admin/api/accounts/%d/suspend.json"
+	userCreate     = "/admin/api/accounts/%d/users.json"
+	userDelete     = "/admin/api/accounts/%d/users/%d.json"
+	userSuspend    = "/admin/api/accounts/%d/users/%d/suspend.json"
+	userUnsuspend  = "/admin/api/accounts/%d/users/%d/unsuspend.json"
+	userRole       = "/admin/api/accounts/%d/users/%d/%s.json"
+
+	providerUserList  = "/admin/api/users.json"
+	accessTokenCreate = "/admin/api/users/%d/access_tokens.json"
+	personalToken     = "/admin/api/personal/access_tokens/%s.json"
+)
+
+// ThreeScaleClient extends client.ThreeScaleClient with the admin portal
+// endpoints the porta client library does not provide
+type ThreeScaleClient struct {
+	*client.ThreeScaleClient
+	adminURL    *url.URL
+	accessToken string
+	httpClient  *http.Client
+}
+
+// blank assignment to verify that ThreeScaleClient implements Client
+var _ Client = &ThreeScaleClient{}
+
+// NewThreeScaleClient builds a ThreeScaleClient for the admin portal at the given scheme, host and port.
+// If httpClient is nil, the default http client will be used
+func NewThreeScaleClient(scheme, host string, port int, accessToken string, httpClient *http.Client) (*ThreeScaleClient, error) {
+	adminPortal, err := client.NewAdminPortal(scheme, host, port)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &ThreeScaleClient{
+		ThreeScaleClient: client.NewThreeScale(adminPortal, accessToken, httpClient),
+		adminURL:         &url.URL{Scheme: scheme, Host: fmt.Sprintf("%s:%d", host, port)},
+		accessToken:      accessToken,
+		httpClient:       httpClient,
+	}, nil
+}
+
+// SuspendTenant suspends the tenant provider account.
+// Suspended tenants keep their data but cannot use the admin portal nor the APIs
+func (c *ThreeScaleClient) SuspendTenant(tenantID int64) error {
+	return c.do(http.MethodPut, fmt.Sprintf(accountSuspend, tenantID), nil, http.StatusOK, nil)
+}
+
+// CreateUser creates a pending user in the account
+func (c *ThreeScaleClient) CreateUser(accountID int64, username, email, password string) (*client.User, error) {
+	params := client.Params{
+		"username": username,
+		"email":    email,
+		"password": password,
+	}
+	user := &client.UserElem{}
+	err := c.do(http.MethodPost, fmt.Sprintf(userCreate, accountID), params, http.StatusCreated, user)
+	if err != nil {
+		return nil, err
+	}
+	return &user.User, nil
+}
+
+// DeleteUser deletes the account user
+func (c *ThreeScaleClient) DeleteUser(accountID, userID int64) error {
+	return c.do(http.MethodDelete, fmt.Sprintf(userDelete, accountID, userID), nil, http.StatusOK, nil)
+}
+
+// SuspendUser suspends an active account user
+func (c *ThreeScaleClient) SuspendUser(accountID, userID int64) error {
+	return c.do(http.MethodPut, fmt.Sprintf(userSuspend, accountID, userID), nil, http.StatusOK, nil)
+}
+
+// UnsuspendUser reactivates a suspended account user
+func (c *ThreeScaleClient) UnsuspendUser(accountID, userID int64) error {
+	return c.do(http.MethodPut, fmt.Sprintf(userUnsuspend, accountID, userID), nil, http.StatusOK, nil)
+}
+
+// ChangeUserRole changes the role of the account user. Role is one of admin or member
+func (c *ThreeScaleClient) ChangeUserRole(accountID, userID int64, role string) error {
+	return c.do(http.MethodPut, fmt.Sprintf(userRole, accountID, userID, role), nil, http.StatusOK, nil)
+}
+
+// ListProviderUsers lists the users of the account owning the admin portal
+func (c *ThreeScaleClient) ListProviderUsers() (*client.UserList, error) {
+	list := &client.UserList{}
+	err := c.do(http.MethodGet, providerUserList, nil, http.StatusOK, list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// CreateAccessToken creates an access token for the provider user.
+// Permission is one of ro or rw
+func (c *ThreeScaleClient) CreateAccessToken(userID int64, name, permission string, scopes []string) (*AccessToken, error) {
+	values := url.Values{}
+	values.Set("name", name)
+	values.Set("permission", permission)
+	for _, scope := range scopes {
+		values.Add("scopes[]", scope)
+	}
+	token := &AccessTokenElem{}
+	err := c.doValues(http.MethodPost, fmt.Sprintf(accessTokenCreate, userID), values, http.StatusCreated, token)
+	if err != nil {
+		return nil, err
+	}
+	return &token.AccessToken, nil
+}
+
+// ShowAccessToken reads an access token of the user owning the client access token.
+// The token value is not returned
+func (c *ThreeScaleClient) ShowAccessToken(idOrValue string) (*AccessToken, error) {
+	token := &AccessTokenElem{}
+	err := c.do(http.MethodGet, fmt.Sprintf(personalToken, url.PathEscape(idOrValue)), nil, http.StatusOK, token)
+	if err != nil {
+		return nil, err
+	}
+	return &token.AccessToken, nil
+}
+
+// DeleteAccessToken revokes an access token of the user owning the client access token
+func (c *ThreeScaleClient) DeleteAccessToken(idOrValue string) error {
+	return c.do(http.MethodDelete, fmt.Sprintf(personalToken, url.PathEscape(idOrValue)), nil, http.StatusOK, nil)
+}
+
+// do sends a request to the given admin API endpoint, authenticated with the access token,
+// and decodes the json response into decodeInto, when not nil.
+// An APIError is returned when the response status code is not expectCode
+func (c *ThreeScaleClient) do(method, endpoint string, params client.Params, expectCode int, decodeInto interface{}) error {
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	return c.doValues(method, endpoint, values, expectCode, decodeInto)
+}
+
+// doValues is like do, for parameters with multiple values
+func (c *ThreeScaleClient) doValues(method, endpoint string, values url.Values, expectCode int, decodeInto interface{}) error {
+	var body io.Reader
+	if len(values) > 0 {
+		body = strings.NewReader(values.Encode())
+	}
+
+	req, err := http.NewRequest(method, c.adminURL.ResolveReference(&url.URL{Path: endpoint}).String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("", c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectCode {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return &APIError{Code: resp.StatusCode, Message: string(respBody)}
+	}
+
+	if decodeInto == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(decodeInto)
+}
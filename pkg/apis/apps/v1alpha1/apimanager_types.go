@@ -7,6 +7,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -29,12 +30,40 @@ type APIManagerSpec struct {
 	WildcardRouter *WildcardRouterSpec `json:"wildcardRouter,omitempty"`
 	// +optional
 	HighAvailability *HighAvailabilitySpec `json:"highAvailability,omitempty"`
+	// +optional
+	Redis *RedisSpec `json:"redis,omitempty"`
+	// +optional
+	Storage *StorageSpec `json:"storage,omitempty"`
+	// +optional
+	Routes *RoutesSpec `json:"routes,omitempty"`
+	// +optional
+	Registry *RegistrySpec `json:"registry,omitempty"`
+	// +optional
+	CredentialPolicies *CredentialPoliciesSpec `json:"credentialPolicies,omitempty"`
+	// PortalTLS configures how the operator verifies the certificates of the
+	// master and admin portals when calling their APIs, e.g. a CA bundle for
+	// routes served with a self-signed certificate
+	// +optional
+	PortalTLS *capabilitiesv1alpha1.AdminPortalTLSSpec `json:"portalTLS,omitempty"`
 }
 
 // APIManagerStatus defines the observed state of APIManager
 // +k8s:openapi-gen=true
 type APIManagerStatus struct {
 	Conditions []APIManagerCondition `json:"conditions,omitempty" protobuf:"bytes,4,rep,name=conditions"`
+	// Phase is the step of the APIManager rollout being waited for
+	// +optional
+	Phase APIManagerPhase `json:"phase,omitempty"`
+	// ProductVersion is the release the APIManager objects were last
+	// reconciled to. Changing spec.productVersion is only allowed to the
+	// releases that can be upgraded from it
+	// +optional
+	ProductVersion product.Version `json:"productVersion,omitempty"`
+	// +optional
+	CredentialRotations []CredentialRotationStatus `json:"credentialRotations,omitempty"`
+	// WeakCredentials are the "secret/key" credentials not meeting their policy minimum entropy
+	// +optional
+	WeakCredentials []string `json:"weakCredentials,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -60,6 +89,25 @@ const (
 	APIManagerProgressing APIManagerConditionType = "Progressing"
 )
 
+// APIManagerPhase is a step of the APIManager rollout. The DeploymentConfigs
+// of a phase are created once every DeploymentConfig of the previous phases
+// is available
+type APIManagerPhase string
+
+const (
+	// Databases is the phase of the system, backend and zync databases, Redis and Memcached
+	APIManagerPhaseDatabases APIManagerPhase = "Databases"
+	// Backend is the phase of the backend listener, worker and cron
+	APIManagerPhaseBackend APIManagerPhase = "Backend"
+	// System is the phase of system. It includes waiting for the system-app
+	// pre hook, which runs the database migrations
+	APIManagerPhaseSystem APIManagerPhase = "System"
+	// ZyncAndApicast is the phase of zync and the apicast gateways
+	APIManagerPhaseZyncAndApicast APIManagerPhase = "ZyncAndApicast"
+	// Completed means every DeploymentConfig of the APIManager is available
+	APIManagerPhaseCompleted APIManagerPhase = "Completed"
+)
+
 type APIManagerCondition struct {
 	Type   APIManagerConditionType `json:"type" description:"type of APIManager condition"`
 	Status v1.ConditionStatus      `json:"status" description:"status of the condition, one of True, False, Unknown"` //TODO should be a custom ConditionStatus or the core v1 one?
@@ -169,6 +217,22 @@ type SystemS3Spec struct {
 	AWSRegion         string                  `json:"awsRegion"`
 	AWSCredentials    v1.LocalObjectReference `json:"awsCredentialsSecret"`
 	FileUploadStorage string                  `json:"fileUploadStorage"`
+	// Endpoint is the URL of an S3-compatible service, like MinIO or Ceph
+	// RGW. Amazon S3 is used when not set
+	// +optional
+	Endpoint *string `json:"endpoint,omitempty"`
+	// ForcePathStyle addresses the bucket in the path of the requests
+	// instead of in the hostname
+	// +optional
+	ForcePathStyle *bool `json:"forcePathStyle,omitempty"`
+	// CACertificateSecret references a secret whose 'ca.crt' key contains
+	// the CA certificates trusted when connecting to the endpoint
+	// +optional
+	CACertificateSecret *v1.LocalObjectReference `json:"caCertificateSecret,omitempty"`
+	// ServerSideEncryption is the server-side encryption algorithm of the
+	// stored objects. Either 'AES256' or 'aws:kms'
+	// +optional
+	ServerSideEncryption *string `json:"serverSideEncryption,omitempty"`
 }
 
 type SystemDatabaseSpec struct {
@@ -216,8 +280,12 @@ func (apimanager *APIManager) SetDefaults() (bool, error) {
 	changed = apimanager.setAPIManagerCommonSpecDefaults()
 	changed = apimanager.setApicastSpecDefaults()
 	changed, err = apimanager.setSystemSpecDefaults()
+	if err != nil {
+		return changed, err
+	}
 
-	return changed, err
+	redisChanged, err := apimanager.setRedisSpecDefaults()
+	return changed || redisChanged, err
 }
 
 func (apimanager *APIManager) setApicastSpecDefaults() bool {
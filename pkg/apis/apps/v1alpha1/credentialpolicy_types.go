@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	oprand "github.com/3scale/3scale-operator/pkg/crypto/rand"
+)
+
+// CredentialType groups the operator generated credentials sharing a generation policy
+type CredentialType string
+
+const (
+	// PasswordCredentialType covers database, master and admin user and backend internal API passwords
+	PasswordCredentialType CredentialType = "password"
+	// AccessTokenCredentialType covers master, admin and apicast access tokens and the zync authentication token
+	AccessTokenCredentialType CredentialType = "accessToken"
+	// SecretKeyBaseCredentialType covers the system and zync secret key bases
+	SecretKeyBaseCredentialType CredentialType = "secretKeyBase"
+	// SharedSecretCredentialType covers the backend events hook shared secret
+	SharedSecretCredentialType CredentialType = "sharedSecret"
+)
+
+// defaultCredentialPolicies are used for the credential types, or policy fields, not set in the spec
+var defaultCredentialPolicies = map[CredentialType]oprand.Policy{
+	PasswordCredentialType:      {Length: 16, Charset: oprand.AlphanumericCharsetName, MinEntropyBits: 80},
+	AccessTokenCredentialType:   {Length: 16, Charset: oprand.AlphanumericCharsetName, MinEntropyBits: 80},
+	SecretKeyBaseCredentialType: {Length: 128, Charset: oprand.AlphanumericCharsetName, MinEntropyBits: 256},
+	SharedSecretCredentialType:  {Length: 16, Charset: oprand.AlphanumericCharsetName, MinEntropyBits: 80},
+}
+
+// CredentialPoliciesSpec configures how the operator generates credentials
+// +k8s:openapi-gen=true
+type CredentialPoliciesSpec struct {
+	// +optional
+	Password *CredentialPolicySpec `json:"password,omitempty"`
+	// +optional
+	AccessToken *CredentialPolicySpec `json:"accessToken,omitempty"`
+	// +optional
+	SecretKeyBase *CredentialPolicySpec `json:"secretKeyBase,omitempty"`
+	// +optional
+	SharedSecret *CredentialPolicySpec `json:"sharedSecret,omitempty"`
+	// RegenerateWeakCredentials rotates the existing rotatable credentials
+	// whose value does not meet the minimum entropy of their policy
+	// +optional
+	RegenerateWeakCredentials bool `json:"regenerateWeakCredentials,omitempty"`
+}
+
+// CredentialPolicySpec defines the generation policy of a credential type
+// +k8s:openapi-gen=true
+type CredentialPolicySpec struct {
+	// +optional
+	Length *int `json:"length,omitempty"`
+	// Charset is one of alphanumeric, lowercaseAlphanumeric or hexadecimal
+	// +optional
+	Charset *string `json:"charset,omitempty"`
+	// MinEntropyBits is the minimum estimated entropy of the credential values.
+	// Existing values below it are reported as weak
+	// +optional
+	MinEntropyBits *int `json:"minEntropyBits,omitempty"`
+}
+
+// CredentialPolicy returns the generation policy of the credential type,
+// the fields not set in the spec take the default value
+func (spec *APIManagerSpec) CredentialPolicy(credentialType CredentialType) oprand.Policy {
+	policy := defaultCredentialPolicies[credentialType]
+
+	var policySpec *CredentialPolicySpec
+	if spec.CredentialPolicies != nil {
+		switch credentialType {
+		case PasswordCredentialType:
+			policySpec = spec.CredentialPolicies.Password
+		case AccessTokenCredentialType:
+			policySpec = spec.CredentialPolicies.AccessToken
+		case SecretKeyBaseCredentialType:
+			policySpec = spec.CredentialPolicies.SecretKeyBase
+		case SharedSecretCredentialType:
+			policySpec = spec.CredentialPolicies.SharedSecret
+		}
+	}
+	if policySpec == nil {
+		return policy
+	}
+
+	if policySpec.Length != nil {
+		policy.Length = *policySpec.Length
+	}
+	if policySpec.Charset != nil {
+		policy.Charset = *policySpec.Charset
+	}
+	if policySpec.MinEntropyBits != nil {
+		policy.MinEntropyBits = *policySpec.MinEntropyBits
+	}
+	return policy
+}
@@ -0,0 +1,74 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RotateCredentialsAnnotation requests the rotation of operator generated credentials.
+// Its value is a comma separated list of CredentialName values. The operator removes
+// each credential from the annotation once it has been rotated
+const RotateCredentialsAnnotation = "apps.3scale.net/rotate-credentials"
+
+// CredentialName identifies an operator generated credential that can be rotated
+type CredentialName string
+
+const (
+	MasterAccessTokenCredential          CredentialName = "masterAccessToken"
+	AdminAccessTokenCredential           CredentialName = "adminAccessToken"
+	BackendSharedSecretCredential        CredentialName = "backendSharedSecret"
+	BackendInternalAPIPasswordCredential CredentialName = "backendInternalAPIPassword"
+	AppSecretKeyBaseCredential           CredentialName = "appSecretKeyBase"
+	ZyncAuthenticationTokenCredential    CredentialName = "zyncAuthenticationToken"
+)
+
+// CredentialRotationPhase is the progress of a credential rotation
+type CredentialRotationPhase string
+
+const (
+	// CredentialRotationRollingOut means the new credential is stored and its
+	// consumers are being rolled out
+	CredentialRotationRollingOut CredentialRotationPhase = "RollingOut"
+	// CredentialRotationCompleted means every consumer runs with the new credential
+	// and the previous access token, if any, is revoked
+	CredentialRotationCompleted CredentialRotationPhase = "Completed"
+)
+
+// CredentialRotationStatus records the last rotation of a credential
+// +k8s:openapi-gen=true
+type CredentialRotationStatus struct {
+	Credential CredentialName          `json:"credential"`
+	Phase      CredentialRotationPhase `json:"phase"`
+	// LastRotationTime is the time the new credential was stored
+	LastRotationTime metav1.Time `json:"lastRotationTime"`
+	// Consumers are the DeploymentConfigs rolled out with the new credential
+	// +optional
+	Consumers []string `json:"consumers,omitempty"`
+	// PendingConsumers are the consumers not rolled out yet, in rollout order
+	// +optional
+	PendingConsumers []string `json:"pendingConsumers,omitempty"`
+	// RevokedAccessTokenID is the ID of the previous access token, revoked
+	// once every consumer is rolled out
+	// +optional
+	RevokedAccessTokenID int64 `json:"revokedAccessTokenID,omitempty"`
+}
+
+// CredentialRotationInProgress returns the rotation whose consumers are being rolled out, if any
+func (s *APIManagerStatus) CredentialRotationInProgress() *CredentialRotationStatus {
+	for idx := range s.CredentialRotations {
+		if s.CredentialRotations[idx].Phase == CredentialRotationRollingOut {
+			return &s.CredentialRotations[idx]
+		}
+	}
+	return nil
+}
+
+// SetCredentialRotation adds or replaces the rotation status of the credential
+func (s *APIManagerStatus) SetCredentialRotation(rotation CredentialRotationStatus) {
+	for idx := range s.CredentialRotations {
+		if s.CredentialRotations[idx].Credential == rotation.Credential {
+			s.CredentialRotations[idx] = rotation
+			return
+		}
+	}
+	s.CredentialRotations = append(s.CredentialRotations, rotation)
+}
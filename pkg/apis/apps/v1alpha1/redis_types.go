@@ -0,0 +1,121 @@
+package v1alpha1
+
+import (
+	"fmt"
+)
+
+const (
+	// RedisSentinelMasterRole connects to the primary of the Sentinel group
+	RedisSentinelMasterRole = "master"
+	// RedisSentinelSlaveRole connects to a replica of the Sentinel group
+	RedisSentinelSlaveRole = "slave"
+
+	defaultRedisSentinelReplicas  int32 = 2
+	defaultRedisSentinelSentinels int32 = 3
+	defaultBackendRedisMasterName       = "backend-redis"
+	defaultSystemRedisMasterName        = "system-redis"
+)
+
+// RedisSpec configures the topology of the backend and system Redis.
+// When no topology is chosen, single standalone Redis instances are deployed
+// +k8s:openapi-gen=true
+type RedisSpec struct {
+	// Sentinel deploys the backend and system Redis as primary/replica sets
+	// monitored by Sentinels inside the cluster
+	// +optional
+	Sentinel *RedisSentinelSpec `json:"sentinel,omitempty"`
+	// ExternalSentinel points backend and system to Redis instances
+	// monitored by Sentinels outside the APIManager
+	// +optional
+	ExternalSentinel *ExternalRedisSentinelSpec `json:"externalSentinel,omitempty"`
+}
+
+// RedisSentinelSpec configures the Sentinel-managed Redis deployed by the operator
+// +k8s:openapi-gen=true
+type RedisSentinelSpec struct {
+	// Replicas is the number of replicas of each Redis primary
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Sentinels is the number of Sentinels monitoring the Redis primaries
+	// +optional
+	Sentinels *int32 `json:"sentinels,omitempty"`
+}
+
+// ExternalRedisSentinelSpec references Redis instances monitored by external Sentinels
+// +k8s:openapi-gen=true
+type ExternalRedisSentinelSpec struct {
+	// Hosts is the comma separated list of Sentinel URLs, like
+	// redis://sentinel-0:26379,redis://sentinel-1:26379
+	Hosts string `json:"hosts"`
+	// Role is the role of the Redis instances to connect to, master or slave
+	// +optional
+	Role *string `json:"role,omitempty"`
+	// BackendStorageMasterName is the Sentinel group name of the backend storage Redis
+	// +optional
+	BackendStorageMasterName *string `json:"backendStorageMasterName,omitempty"`
+	// BackendQueuesMasterName is the Sentinel group name of the backend queues Redis
+	// +optional
+	BackendQueuesMasterName *string `json:"backendQueuesMasterName,omitempty"`
+	// SystemMasterName is the Sentinel group name of the system Redis
+	// +optional
+	SystemMasterName *string `json:"systemMasterName,omitempty"`
+}
+
+func (apimanager *APIManager) setRedisSpecDefaults() (bool, error) {
+	changed := false
+	spec := &apimanager.Spec
+
+	if spec.Redis == nil {
+		return changed, nil
+	}
+
+	if spec.Redis.Sentinel != nil && spec.Redis.ExternalSentinel != nil {
+		return changed, fmt.Errorf("Only one Redis topology can be chosen at the same time")
+	}
+
+	if sentinel := spec.Redis.Sentinel; sentinel != nil {
+		if spec.HighAvailability != nil && spec.HighAvailability.Enabled {
+			return changed, fmt.Errorf("Redis Sentinels cannot be deployed when HighAvailability is enabled. Use an external Sentinel instead")
+		}
+		if sentinel.Replicas == nil {
+			replicas := defaultRedisSentinelReplicas
+			sentinel.Replicas = &replicas
+			changed = true
+		}
+		if sentinel.Sentinels == nil {
+			sentinels := defaultRedisSentinelSentinels
+			sentinel.Sentinels = &sentinels
+			changed = true
+		}
+	}
+
+	if external := spec.Redis.ExternalSentinel; external != nil {
+		if external.Hosts == "" {
+			return changed, fmt.Errorf("No external Redis Sentinel hosts have been provided")
+		}
+		if external.Role == nil {
+			role := RedisSentinelMasterRole
+			external.Role = &role
+			changed = true
+		} else if *external.Role != RedisSentinelMasterRole && *external.Role != RedisSentinelSlaveRole {
+			return changed, fmt.Errorf("Unknown external Redis Sentinel role '%s'. Use '%s' or '%s'", *external.Role, RedisSentinelMasterRole, RedisSentinelSlaveRole)
+		}
+		defaultMasterNames := []struct {
+			masterName **string
+			value      string
+		}{
+			{&external.BackendStorageMasterName, defaultBackendRedisMasterName},
+			{&external.BackendQueuesMasterName, defaultBackendRedisMasterName},
+			{&external.SystemMasterName, defaultSystemRedisMasterName},
+		}
+		for _, d := range defaultMasterNames {
+			if *d.masterName == nil {
+				value := d.value
+				*d.masterName = &value
+				changed = true
+			}
+		}
+	}
+
+	return changed, nil
+}
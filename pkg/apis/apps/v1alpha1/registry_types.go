@@ -0,0 +1,52 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// RegistrySpec configures where the images of the release are pulled from.
+// It only applies to the default images of the release. The images set
+// explicitly in the APIManager are used as they are
+// +k8s:openapi-gen=true
+type RegistrySpec struct {
+	// Mirror replaces the registry host of the release images. It can
+	// include a path, like 'mirror.example.com:5000/3scale', that is
+	// prepended to the repository of every image
+	// +optional
+	Mirror *string `json:"mirror,omitempty"`
+	// ImagePullSecrets are added to the 'amp' service account and to the
+	// pods of every DeploymentConfig
+	// +optional
+	ImagePullSecrets []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ImageDigests pins the release images to digests
+	// +optional
+	ImageDigests *ImageDigestsSpec `json:"imageDigests,omitempty"`
+}
+
+// ImageDigestsSpec defines the digest, like 'sha256:<hex>', each release
+// image is pinned to. The tag of a pinned image is dropped
+// +k8s:openapi-gen=true
+type ImageDigestsSpec struct {
+	// +optional
+	Apicast *string `json:"apicast,omitempty"`
+	// +optional
+	Backend *string `json:"backend,omitempty"`
+	// +optional
+	BackendRedis *string `json:"backendRedis,omitempty"`
+	// +optional
+	System *string `json:"system,omitempty"`
+	// +optional
+	SystemRedis *string `json:"systemRedis,omitempty"`
+	// +optional
+	SystemMySQL *string `json:"systemMySQL,omitempty"`
+	// +optional
+	SystemPostgreSQL *string `json:"systemPostgreSQL,omitempty"`
+	// +optional
+	SystemMemcached *string `json:"systemMemcached,omitempty"`
+	// +optional
+	WildcardRouter *string `json:"wildcardRouter,omitempty"`
+	// +optional
+	Zync *string `json:"zync,omitempty"`
+	// +optional
+	ZyncPostgreSQL *string `json:"zyncPostgreSQL,omitempty"`
+}
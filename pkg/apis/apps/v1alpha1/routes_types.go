@@ -0,0 +1,49 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// RoutesSpec configures the Routes exposing the 3scale portals and
+// gateways. Unset fields keep the hosts derived from the wildcard domain and
+// the router's default certificate
+// +k8s:openapi-gen=true
+type RoutesSpec struct {
+	// SystemMaster configures the system-master Route of the master portal
+	// +optional
+	SystemMaster *RouteSpec `json:"systemMaster,omitempty"`
+	// SystemProvider configures the system-provider-admin Route of the
+	// admin portal
+	// +optional
+	SystemProvider *RouteSpec `json:"systemProvider,omitempty"`
+	// SystemDeveloper configures the system-developer Route of the
+	// developer portal
+	// +optional
+	SystemDeveloper *RouteSpec `json:"systemDeveloper,omitempty"`
+	// ApicastStaging configures the api-apicast-staging Route
+	// +optional
+	ApicastStaging *RouteSpec `json:"apicastStaging,omitempty"`
+	// ApicastProduction configures the api-apicast-production Route
+	// +optional
+	ApicastProduction *RouteSpec `json:"apicastProduction,omitempty"`
+}
+
+// RouteSpec defines the host and the TLS settings of a Route
+// +k8s:openapi-gen=true
+type RouteSpec struct {
+	// +optional
+	Host *string `json:"host,omitempty"`
+	// +optional
+	TLS *RouteTLSSpec `json:"tls,omitempty"`
+}
+
+// RouteTLSSpec defines the TLS settings of a Route. TLS is terminated at the
+// router, the 3scale pods serve plain HTTP
+// +k8s:openapi-gen=true
+type RouteTLSSpec struct {
+	// CertificateSecret references a secret with the 'tls.crt' and 'tls.key'
+	// keys, and optionally a 'ca.crt' key with the certificate chain, served
+	// by the router. The router's default certificate is used when not set
+	// +optional
+	CertificateSecret *v1.LocalObjectReference `json:"certificateSecret,omitempty"`
+}
@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StorageSpec configures the PersistentVolumeClaims created by the operator.
+// Unset fields keep the defaults of each volume
+// +k8s:openapi-gen=true
+type StorageSpec struct {
+	// SystemStorage configures the system-storage PersistentVolumeClaim,
+	// used when the system FileStorage is a PVC
+	// +optional
+	SystemStorage *PersistentVolumeClaimSpec `json:"systemStorage,omitempty"`
+	// SystemDatabase configures the PersistentVolumeClaim of the system
+	// database: mysql-storage or postgresql-data
+	// +optional
+	SystemDatabase *PersistentVolumeClaimSpec `json:"systemDatabase,omitempty"`
+	// BackendRedis configures the backend-redis-storage PersistentVolumeClaim
+	// +optional
+	BackendRedis *PersistentVolumeClaimSpec `json:"backendRedis,omitempty"`
+	// SystemRedis configures the system-redis-storage PersistentVolumeClaim
+	// +optional
+	SystemRedis *PersistentVolumeClaimSpec `json:"systemRedis,omitempty"`
+}
+
+// PersistentVolumeClaimSpec defines a PersistentVolumeClaim created by the operator.
+// Only the size of existing claims is updated, to expand them
+// +k8s:openapi-gen=true
+type PersistentVolumeClaimSpec struct {
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// +optional
+	AccessModes []v1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
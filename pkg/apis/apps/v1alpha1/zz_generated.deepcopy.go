@@ -5,6 +5,9 @@
 package v1alpha1
 
 import (
+	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -160,6 +163,36 @@ func (in *APIManagerSpec) DeepCopyInto(out *APIManagerSpec) {
 		*out = new(HighAvailabilitySpec)
 		**out = **in
 	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = new(RoutesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(RegistrySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialPolicies != nil {
+		in, out := &in.CredentialPolicies, &out.CredentialPolicies
+		*out = new(CredentialPoliciesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PortalTLS != nil {
+		in, out := &in.PortalTLS, &out.PortalTLS
+		*out = new(capabilitiesv1alpha1.AdminPortalTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -181,6 +214,18 @@ func (in *APIManagerStatus) DeepCopyInto(out *APIManagerStatus) {
 		*out = make([]APIManagerCondition, len(*in))
 		copy(*out, *in)
 	}
+	if in.CredentialRotations != nil {
+		in, out := &in.CredentialRotations, &out.CredentialRotations
+		*out = make([]CredentialRotationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WeakCredentials != nil {
+		in, out := &in.WeakCredentials, &out.WeakCredentials
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -261,6 +306,136 @@ func (in *BackendSpec) DeepCopy() *BackendSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialPoliciesSpec) DeepCopyInto(out *CredentialPoliciesSpec) {
+	*out = *in
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(CredentialPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccessToken != nil {
+		in, out := &in.AccessToken, &out.AccessToken
+		*out = new(CredentialPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyBase != nil {
+		in, out := &in.SecretKeyBase, &out.SecretKeyBase
+		*out = new(CredentialPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SharedSecret != nil {
+		in, out := &in.SharedSecret, &out.SharedSecret
+		*out = new(CredentialPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialPoliciesSpec.
+func (in *CredentialPoliciesSpec) DeepCopy() *CredentialPoliciesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialPoliciesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialPolicySpec) DeepCopyInto(out *CredentialPolicySpec) {
+	*out = *in
+	if in.Length != nil {
+		in, out := &in.Length, &out.Length
+		*out = new(int)
+		**out = **in
+	}
+	if in.Charset != nil {
+		in, out := &in.Charset, &out.Charset
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinEntropyBits != nil {
+		in, out := &in.MinEntropyBits, &out.MinEntropyBits
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialPolicySpec.
+func (in *CredentialPolicySpec) DeepCopy() *CredentialPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialRotationStatus) DeepCopyInto(out *CredentialRotationStatus) {
+	*out = *in
+	in.LastRotationTime.DeepCopyInto(&out.LastRotationTime)
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingConsumers != nil {
+		in, out := &in.PendingConsumers, &out.PendingConsumers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotationStatus.
+func (in *CredentialRotationStatus) DeepCopy() *CredentialRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalRedisSentinelSpec) DeepCopyInto(out *ExternalRedisSentinelSpec) {
+	*out = *in
+	if in.Role != nil {
+		in, out := &in.Role, &out.Role
+		*out = new(string)
+		**out = **in
+	}
+	if in.BackendStorageMasterName != nil {
+		in, out := &in.BackendStorageMasterName, &out.BackendStorageMasterName
+		*out = new(string)
+		**out = **in
+	}
+	if in.BackendQueuesMasterName != nil {
+		in, out := &in.BackendQueuesMasterName, &out.BackendQueuesMasterName
+		*out = new(string)
+		**out = **in
+	}
+	if in.SystemMasterName != nil {
+		in, out := &in.SystemMasterName, &out.SystemMasterName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalRedisSentinelSpec.
+func (in *ExternalRedisSentinelSpec) DeepCopy() *ExternalRedisSentinelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalRedisSentinelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HighAvailabilitySpec) DeepCopyInto(out *HighAvailabilitySpec) {
 	*out = *in
@@ -277,6 +452,320 @@ func (in *HighAvailabilitySpec) DeepCopy() *HighAvailabilitySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageDigestsSpec) DeepCopyInto(out *ImageDigestsSpec) {
+	*out = *in
+	if in.Apicast != nil {
+		in, out := &in.Apicast, &out.Apicast
+		*out = new(string)
+		**out = **in
+	}
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(string)
+		**out = **in
+	}
+	if in.BackendRedis != nil {
+		in, out := &in.BackendRedis, &out.BackendRedis
+		*out = new(string)
+		**out = **in
+	}
+	if in.System != nil {
+		in, out := &in.System, &out.System
+		*out = new(string)
+		**out = **in
+	}
+	if in.SystemRedis != nil {
+		in, out := &in.SystemRedis, &out.SystemRedis
+		*out = new(string)
+		**out = **in
+	}
+	if in.SystemMySQL != nil {
+		in, out := &in.SystemMySQL, &out.SystemMySQL
+		*out = new(string)
+		**out = **in
+	}
+	if in.SystemPostgreSQL != nil {
+		in, out := &in.SystemPostgreSQL, &out.SystemPostgreSQL
+		*out = new(string)
+		**out = **in
+	}
+	if in.SystemMemcached != nil {
+		in, out := &in.SystemMemcached, &out.SystemMemcached
+		*out = new(string)
+		**out = **in
+	}
+	if in.WildcardRouter != nil {
+		in, out := &in.WildcardRouter, &out.WildcardRouter
+		*out = new(string)
+		**out = **in
+	}
+	if in.Zync != nil {
+		in, out := &in.Zync, &out.Zync
+		*out = new(string)
+		**out = **in
+	}
+	if in.ZyncPostgreSQL != nil {
+		in, out := &in.ZyncPostgreSQL, &out.ZyncPostgreSQL
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageDigestsSpec.
+func (in *ImageDigestsSpec) DeepCopy() *ImageDigestsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageDigestsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimSpec) DeepCopyInto(out *PersistentVolumeClaimSpec) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimSpec.
+func (in *PersistentVolumeClaimSpec) DeepCopy() *PersistentVolumeClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisSentinelSpec) DeepCopyInto(out *RedisSentinelSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Sentinels != nil {
+		in, out := &in.Sentinels, &out.Sentinels
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSentinelSpec.
+func (in *RedisSentinelSpec) DeepCopy() *RedisSentinelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisSentinelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisSpec) DeepCopyInto(out *RedisSpec) {
+	*out = *in
+	if in.Sentinel != nil {
+		in, out := &in.Sentinel, &out.Sentinel
+		*out = new(RedisSentinelSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalSentinel != nil {
+		in, out := &in.ExternalSentinel, &out.ExternalSentinel
+		*out = new(ExternalRedisSentinelSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSpec.
+func (in *RedisSpec) DeepCopy() *RedisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistrySpec) DeepCopyInto(out *RegistrySpec) {
+	*out = *in
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageDigests != nil {
+		in, out := &in.ImageDigests, &out.ImageDigests
+		*out = new(ImageDigestsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistrySpec.
+func (in *RegistrySpec) DeepCopy() *RegistrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
+	*out = *in
+	if in.Host != nil {
+		in, out := &in.Host, &out.Host
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(RouteTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpec.
+func (in *RouteSpec) DeepCopy() *RouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteTLSSpec) DeepCopyInto(out *RouteTLSSpec) {
+	*out = *in
+	if in.CertificateSecret != nil {
+		in, out := &in.CertificateSecret, &out.CertificateSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteTLSSpec.
+func (in *RouteTLSSpec) DeepCopy() *RouteTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoutesSpec) DeepCopyInto(out *RoutesSpec) {
+	*out = *in
+	if in.SystemMaster != nil {
+		in, out := &in.SystemMaster, &out.SystemMaster
+		*out = new(RouteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SystemProvider != nil {
+		in, out := &in.SystemProvider, &out.SystemProvider
+		*out = new(RouteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SystemDeveloper != nil {
+		in, out := &in.SystemDeveloper, &out.SystemDeveloper
+		*out = new(RouteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApicastStaging != nil {
+		in, out := &in.ApicastStaging, &out.ApicastStaging
+		*out = new(RouteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApicastProduction != nil {
+		in, out := &in.ApicastProduction, &out.ApicastProduction
+		*out = new(RouteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutesSpec.
+func (in *RoutesSpec) DeepCopy() *RoutesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RoutesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	if in.SystemStorage != nil {
+		in, out := &in.SystemStorage, &out.SystemStorage
+		*out = new(PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SystemDatabase != nil {
+		in, out := &in.SystemDatabase, &out.SystemDatabase
+		*out = new(PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackendRedis != nil {
+		in, out := &in.BackendRedis, &out.BackendRedis
+		*out = new(PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SystemRedis != nil {
+		in, out := &in.SystemRedis, &out.SystemRedis
+		*out = new(PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemDatabaseSpec) DeepCopyInto(out *SystemDatabaseSpec) {
 	*out = *in
@@ -314,7 +803,7 @@ func (in *SystemFileStorageSpec) DeepCopyInto(out *SystemFileStorageSpec) {
 	if in.S3 != nil {
 		in, out := &in.S3, &out.S3
 		*out = new(SystemS3Spec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -396,6 +885,26 @@ func (in *SystemPostgreSQLSpec) DeepCopy() *SystemPostgreSQLSpec {
 func (in *SystemS3Spec) DeepCopyInto(out *SystemS3Spec) {
 	*out = *in
 	out.AWSCredentials = in.AWSCredentials
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.ForcePathStyle != nil {
+		in, out := &in.ForcePathStyle, &out.ForcePathStyle
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CACertificateSecret != nil {
+		in, out := &in.CACertificateSecret, &out.CACertificateSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ServerSideEncryption != nil {
+		in, out := &in.ServerSideEncryption, &out.ServerSideEncryption
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
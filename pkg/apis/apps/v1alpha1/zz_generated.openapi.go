@@ -13,9 +13,22 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManager":       schema_pkg_apis_apps_v1alpha1_APIManager(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerSpec":   schema_pkg_apis_apps_v1alpha1_APIManagerSpec(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerStatus": schema_pkg_apis_apps_v1alpha1_APIManagerStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManager":                schema_pkg_apis_apps_v1alpha1_APIManager(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerSpec":            schema_pkg_apis_apps_v1alpha1_APIManagerSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerStatus":          schema_pkg_apis_apps_v1alpha1_APIManagerStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialPoliciesSpec":    schema_pkg_apis_apps_v1alpha1_CredentialPoliciesSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialPolicySpec":      schema_pkg_apis_apps_v1alpha1_CredentialPolicySpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialRotationStatus":  schema_pkg_apis_apps_v1alpha1_CredentialRotationStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ExternalRedisSentinelSpec": schema_pkg_apis_apps_v1alpha1_ExternalRedisSentinelSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ImageDigestsSpec":          schema_pkg_apis_apps_v1alpha1_ImageDigestsSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.PersistentVolumeClaimSpec": schema_pkg_apis_apps_v1alpha1_PersistentVolumeClaimSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RedisSentinelSpec":         schema_pkg_apis_apps_v1alpha1_RedisSentinelSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RedisSpec":                 schema_pkg_apis_apps_v1alpha1_RedisSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RegistrySpec":              schema_pkg_apis_apps_v1alpha1_RegistrySpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteSpec":                 schema_pkg_apis_apps_v1alpha1_RouteSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteTLSSpec":              schema_pkg_apis_apps_v1alpha1_RouteTLSSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RoutesSpec":                schema_pkg_apis_apps_v1alpha1_RoutesSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.StorageSpec":               schema_pkg_apis_apps_v1alpha1_StorageSpec(ref),
 	}
 }
 
@@ -140,12 +153,43 @@ func schema_pkg_apis_apps_v1alpha1_APIManagerSpec(ref common.ReferenceCallback)
 							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.HighAvailabilitySpec"),
 						},
 					},
+					"redis": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RedisSpec"),
+						},
+					},
+					"storage": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.StorageSpec"),
+						},
+					},
+					"routes": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RoutesSpec"),
+						},
+					},
+					"registry": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RegistrySpec"),
+						},
+					},
+					"credentialPolicies": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialPoliciesSpec"),
+						},
+					},
+					"portalTLS": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PortalTLS configures how the operator verifies the certificates of the master and admin portals when calling their APIs, e.g. a CA bundle for routes served with a self-signed certificate",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.AdminPortalTLSSpec"),
+						},
+					},
 				},
 				Required: []string{"productVersion", "wildcardDomain"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ApicastSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.BackendSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.HighAvailabilitySpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.SystemSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.WildcardRouterSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ZyncSpec"},
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ApicastSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.BackendSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialPoliciesSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.HighAvailabilitySpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RedisSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RegistrySpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RoutesSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.StorageSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.SystemSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.WildcardRouterSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ZyncSpec", "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.AdminPortalTLSSpec"},
 	}
 }
 
@@ -167,10 +211,583 @@ func schema_pkg_apis_apps_v1alpha1_APIManagerStatus(ref common.ReferenceCallback
 							},
 						},
 					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase is the step of the APIManager rollout being waited for",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"productVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProductVersion is the release the APIManager objects were last reconciled to. Changing spec.productVersion is only allowed to the releases that can be upgraded from it",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialRotations": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialRotationStatus"),
+									},
+								},
+							},
+						},
+					},
+					"weakCredentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WeakCredentials are the \"secret/key\" credentials not meeting their policy minimum entropy",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerCondition", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialRotationStatus"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_CredentialPoliciesSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CredentialPoliciesSpec configures how the operator generates credentials",
+				Properties: map[string]spec.Schema{
+					"password": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialPolicySpec"),
+						},
+					},
+					"accessToken": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialPolicySpec"),
+						},
+					},
+					"secretKeyBase": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialPolicySpec"),
+						},
+					},
+					"sharedSecret": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialPolicySpec"),
+						},
+					},
+					"regenerateWeakCredentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RegenerateWeakCredentials rotates the existing rotatable credentials whose value does not meet the minimum entropy of their policy",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialPolicySpec"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_CredentialPolicySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CredentialPolicySpec defines the generation policy of a credential type",
+				Properties: map[string]spec.Schema{
+					"length": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"charset": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Charset is one of alphanumeric, lowercaseAlphanumeric or hexadecimal",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"minEntropyBits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinEntropyBits is the minimum estimated entropy of the credential values. Existing values below it are reported as weak",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_CredentialRotationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CredentialRotationStatus records the last rotation of a credential",
+				Properties: map[string]spec.Schema{
+					"credential": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lastRotationTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastRotationTime is the time the new credential was stored",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"consumers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Consumers are the DeploymentConfigs rolled out with the new credential",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"pendingConsumers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PendingConsumers are the consumers not rolled out yet, in rollout order",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"revokedAccessTokenID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RevokedAccessTokenID is the ID of the previous access token, revoked once every consumer is rolled out",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"credential", "phase", "lastRotationTime"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_ExternalRedisSentinelSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ExternalRedisSentinelSpec references Redis instances monitored by external Sentinels",
+				Properties: map[string]spec.Schema{
+					"hosts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hosts is the comma separated list of Sentinel URLs, like redis://sentinel-0:26379,redis://sentinel-1:26379",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"role": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Role is the role of the Redis instances to connect to, master or slave",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"backendStorageMasterName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BackendStorageMasterName is the Sentinel group name of the backend storage Redis",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"backendQueuesMasterName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BackendQueuesMasterName is the Sentinel group name of the backend queues Redis",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"systemMasterName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SystemMasterName is the Sentinel group name of the system Redis",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"hosts"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_ImageDigestsSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageDigestsSpec defines the digest, like 'sha256:<hex>', each release image is pinned to. The tag of a pinned image is dropped",
+				Properties: map[string]spec.Schema{
+					"apicast": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"backend": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"backendRedis": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"system": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"systemRedis": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"systemMySQL": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"systemPostgreSQL": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"systemMemcached": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"wildcardRouter": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"zync": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"zyncPostgreSQL": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_PersistentVolumeClaimSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PersistentVolumeClaimSpec defines a PersistentVolumeClaim created by the operator. Only the size of existing claims is updated, to expand them",
+				Properties: map[string]spec.Schema{
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+					"storageClassName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"accessModes": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_RedisSentinelSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RedisSentinelSpec configures the Sentinel-managed Redis deployed by the operator",
+				Properties: map[string]spec.Schema{
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Replicas is the number of replicas of each Redis primary",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"sentinels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sentinels is the number of Sentinels monitoring the Redis primaries",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_RedisSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RedisSpec configures the topology of the backend and system Redis. When no topology is chosen, single standalone Redis instances are deployed",
+				Properties: map[string]spec.Schema{
+					"sentinel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sentinel deploys the backend and system Redis as primary/replica sets monitored by Sentinels inside the cluster",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RedisSentinelSpec"),
+						},
+					},
+					"externalSentinel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExternalSentinel points backend and system to Redis instances monitored by Sentinels outside the APIManager",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ExternalRedisSentinelSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ExternalRedisSentinelSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RedisSentinelSpec"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_RegistrySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RegistrySpec configures where the images of the release are pulled from. It only applies to the default images of the release. The images set explicitly in the APIManager are used as they are",
+				Properties: map[string]spec.Schema{
+					"mirror": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mirror replaces the registry host of the release images. It can include a path, like 'mirror.example.com:5000/3scale', that is prepended to the repository of every image",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imagePullSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImagePullSecrets are added to the 'amp' service account and to the pods of every DeploymentConfig",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.LocalObjectReference"),
+									},
+								},
+							},
+						},
+					},
+					"imageDigests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageDigests pins the release images to digests",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ImageDigestsSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ImageDigestsSpec", "k8s.io/api/core/v1.LocalObjectReference"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_RouteSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RouteSpec defines the host and the TLS settings of a Route",
+				Properties: map[string]spec.Schema{
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"tls": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteTLSSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteTLSSpec"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_RouteTLSSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RouteTLSSpec defines the TLS settings of a Route. TLS is terminated at the router, the 3scale pods serve plain HTTP",
+				Properties: map[string]spec.Schema{
+					"certificateSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertificateSecret references a secret with the 'tls.crt' and 'tls.key' keys, and optionally a 'ca.crt' key with the certificate chain, served by the router. The router's default certificate is used when not set",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.LocalObjectReference"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_RoutesSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RoutesSpec configures the Routes exposing the 3scale portals and gateways. Unset fields keep the hosts derived from the wildcard domain and the router's default certificate",
+				Properties: map[string]spec.Schema{
+					"systemMaster": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SystemMaster configures the system-master Route of the master portal",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteSpec"),
+						},
+					},
+					"systemProvider": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SystemProvider configures the system-provider-admin Route of the admin portal",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteSpec"),
+						},
+					},
+					"systemDeveloper": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SystemDeveloper configures the system-developer Route of the developer portal",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteSpec"),
+						},
+					},
+					"apicastStaging": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApicastStaging configures the api-apicast-staging Route",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteSpec"),
+						},
+					},
+					"apicastProduction": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApicastProduction configures the api-apicast-production Route",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RouteSpec"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_StorageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StorageSpec configures the PersistentVolumeClaims created by the operator. Unset fields keep the defaults of each volume",
+				Properties: map[string]spec.Schema{
+					"systemStorage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SystemStorage configures the system-storage PersistentVolumeClaim, used when the system FileStorage is a PVC",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.PersistentVolumeClaimSpec"),
+						},
+					},
+					"systemDatabase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SystemDatabase configures the PersistentVolumeClaim of the system database: mysql-storage or postgresql-data",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.PersistentVolumeClaimSpec"),
+						},
+					},
+					"backendRedis": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BackendRedis configures the backend-redis-storage PersistentVolumeClaim",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.PersistentVolumeClaimSpec"),
+						},
+					},
+					"systemRedis": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SystemRedis configures the system-redis-storage PersistentVolumeClaim",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.PersistentVolumeClaimSpec"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerCondition"},
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.PersistentVolumeClaimSpec"},
 	}
 }
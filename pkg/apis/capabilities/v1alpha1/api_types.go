@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
 	"github.com/3scale/3scale-operator/pkg/helper"
 	portaClient "github.com/3scale/3scale-porta-go-client/client"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -90,7 +91,7 @@ func (api *API) getIntegrationMethodType() string {
 	}
 	return ""
 }
-func (api API) getInternalAPIfrom3scale(c *portaClient.ThreeScaleClient) (*InternalAPI, error) {
+func (api API) getInternalAPIfrom3scale(c porta.Client) (*InternalAPI, error) {
 
 	service, err := getServiceFromInternalAPI(c, api.Name)
 	if err != nil {
@@ -581,7 +582,7 @@ func (api InternalAPI) getIntegration() Integration {
 }
 
 // createIn3scale Creates the InternalAPI in 3scale
-func (api InternalAPI) createIn3scale(c *portaClient.ThreeScaleClient) error {
+func (api InternalAPI) createIn3scale(c porta.Client) error {
 
 	// Get the proper 3scale deployment Option based on the integrationMethod
 	deploymentOption := IntegrationMethodToDeploymentType[api.getIntegrationName()]
@@ -699,7 +700,7 @@ func (api InternalAPI) createIn3scale(c *portaClient.ThreeScaleClient) error {
 }
 
 // DeleteFrom3scale Removes an InternalAPI from 3scale
-func (api InternalAPI) DeleteFrom3scale(c *portaClient.ThreeScaleClient) error {
+func (api InternalAPI) DeleteFrom3scale(c porta.Client) error {
 
 	services, err := c.ListServices()
 	if err != nil {
@@ -750,13 +751,7 @@ type APIPair struct {
 }
 
 // reconcileWith3scale creates/modifies/deletes APIs based on the information of the APIsDiff object.
-func (d *APIsDiff) ReconcileWith3scale(creds InternalCredentials) error {
-
-	c, err := helper.PortaClientFromURLString(creds.AdminURL, creds.AuthToken)
-
-	if err != nil {
-		return err
-	}
+func (d *APIsDiff) ReconcileWith3scale(c porta.Client) error {
 
 	for _, api := range d.MissingFromB {
 
@@ -1118,7 +1113,7 @@ func get3scaleProxyFromInternalAPI(api InternalAPI) (portaClient.Proxy, error) {
 
 	return proxy, nil
 }
-func getServiceFromInternalAPI(c *portaClient.ThreeScaleClient, serviceName string) (portaClient.Service, error) {
+func getServiceFromInternalAPI(c porta.Client, serviceName string) (portaClient.Service, error) {
 	services, err := c.ListServices()
 
 	if err != nil {
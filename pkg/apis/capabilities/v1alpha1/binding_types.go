@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,9 +25,17 @@ const BINDING_FINALIZER = "binding.capabilities.3scale.net"
 // BindingSpec defines the desired state of Binding
 // +k8s:openapi-gen=true
 type BindingSpec struct {
-	CredentialsRef v1.SecretReference `json:"credentialsRef"`
+	// CredentialsRef references the Secret with the tenant credentials.
+	// Either credentialsRef or tenantRef must be set
+	//+optional
+	CredentialsRef v1.SecretReference `json:"credentialsRef,omitempty"`
+	// TenantRef references a Tenant object, in the same namespace, whose credentials Secret is used
+	//+optional
+	TenantRef *v1.LocalObjectReference `json:"tenantRef,omitempty"`
 	//+optional
 	APISelector metav1.LabelSelector `json:"apiSelector,omitempty"`
+	//+optional
+	TLS *AdminPortalTLSSpec `json:"tls,omitempty"`
 }
 
 // BindingStatus defines the observed state of Binding
@@ -41,6 +49,62 @@ type BindingStatus struct {
 	DesiredState *string `json:"desiredState,omitempty"`
 	//+optional
 	PreviousState *string `json:"previousState,omitempty"`
+	//+optional
+	Conditions []BindingCondition `json:"conditions,omitempty"`
+}
+
+type BindingConditionType string
+
+const (
+	// BindingWaiting means the binding is waiting for the referenced Tenant to be Ready
+	// before syncing the APIs
+	BindingWaiting BindingConditionType = "Waiting"
+)
+
+// BindingCondition describes the state of the Binding at a certain point
+// +k8s:openapi-gen=true
+type BindingCondition struct {
+	Type   BindingConditionType `json:"type" description:"type of Binding condition"`
+	Status v1.ConditionStatus   `json:"status" description:"status of the condition, one of True, False, Unknown"`
+	// +optional
+	Reason string `json:"reason,omitempty" description:"one-word CamelCase reason for the condition's last transition"`
+	// +optional
+	Message string `json:"message,omitempty" description:"human-readable message indicating details about last transition"`
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" description:"last time the condition transit from one status to another"`
+}
+
+// GetCondition returns the condition of the given type, or nil if it is not set
+func (s *BindingStatus) GetCondition(condType BindingConditionType) *BindingCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == condType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates the condition of the same type.
+// The last transition time is only changed when the condition status changes
+func (s *BindingStatus) SetCondition(condType BindingConditionType, status v1.ConditionStatus, reason, message string) {
+	current := s.GetCondition(condType)
+	if current == nil {
+		s.Conditions = append(s.Conditions, BindingCondition{
+			Type:               condType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: metav1.Now(),
+		})
+		return
+	}
+
+	if current.Status != status {
+		current.LastTransitionTime = metav1.Now()
+	}
+	current.Status = status
+	current.Reason = reason
+	current.Message = message
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -134,19 +198,29 @@ func (b *Binding) SetLastSuccessfulSync() {
 	b.Status.LastSync = timestamp
 }
 
+// PortaClient builds a porta client for the given credentials, verifying the
+// admin portal certificate as described by the binding TLS spec
+func (b *Binding) PortaClient(c client.Client, creds InternalCredentials, newPortaClient porta.ClientFactory) (porta.Client, error) {
+	tlsConfig, err := b.Spec.TLS.TLSConfig(c, b.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return newPortaClient(creds.AdminURL, creds.AuthToken, tlsConfig)
+}
+
 // IsTerminating checks if the objects has been marked for deletion
 func (b *Binding) IsTerminating() bool {
 	return b.HasFinalizer() && b.DeletionTimestamp != nil
 }
 
 // CleanUp remove all the objects referenced by the binding object current state.
-func (b *Binding) CleanUp(c client.Client) error {
+func (b *Binding) CleanUp(c client.Client, newPortaClient porta.ClientFactory) error {
 
 	state, err := b.GetCurrentState()
 	if state != nil {
-		portaClient, err := helper.PortaClientFromURLString(state.Credentials.AdminURL, state.Credentials.AuthToken)
+		portaClient, err := b.PortaClient(c, state.Credentials, newPortaClient)
 
-		if err != nil {
+		if err == nil {
 			for _, api := range state.APIs {
 				_ = api.DeleteFrom3scale(portaClient)
 			}
@@ -295,7 +369,7 @@ func (b Binding) NewDesiredState(c client.Client) (*State, error) {
 }
 
 // NewDesiredState creates a new state from the 3scale system
-func (b Binding) NewCurrentState(c client.Client) (*State, error) {
+func (b Binding) NewCurrentState(c client.Client, newPortaClient porta.ClientFactory) (*State, error) {
 
 	internalCredentials, err := b.newInternalCredentials(c)
 	if err != nil {
@@ -317,7 +391,7 @@ func (b Binding) NewCurrentState(c client.Client) (*State, error) {
 		return nil, err
 	}
 
-	portaClient, err := helper.PortaClientFromURLString(state.Credentials.AdminURL, state.Credentials.AuthToken)
+	portaClient, err := b.PortaClient(c, state.Credentials, newPortaClient)
 	if err != nil {
 		return nil, err
 	}
@@ -392,10 +466,14 @@ func (b Binding) getAPIs(c client.Client) (*APIList, error) {
 }
 func (b Binding) newInternalCredentials(c client.Client) (*InternalCredentials, error) {
 
+	secretNN, err := b.credentialsSecretNamespacedName(c)
+	if err != nil {
+		return nil, err
+	}
+
 	// GET SECRET
 	secret := &v1.Secret{}
-	// TODO: fix namespace default
-	err := c.Get(context.TODO(), types.NamespacedName{Name: b.Spec.CredentialsRef.Name, Namespace: b.Namespace}, secret)
+	err = c.Get(context.TODO(), secretNN, secret)
 
 	if err != nil && errors.IsNotFound(err) {
 		return nil, fmt.Errorf("credentialsNotFound")
@@ -408,3 +486,44 @@ func (b Binding) newInternalCredentials(c client.Client) (*InternalCredentials,
 		AdminURL:  string(secret.Data["adminURL"]),
 	}, nil
 }
+
+// credentialsSecretNamespacedName returns the location of the Secret with the tenant credentials,
+// either referenced directly or through the referenced Tenant object
+func (b Binding) credentialsSecretNamespacedName(c client.Client) (types.NamespacedName, error) {
+	if b.Spec.TenantRef == nil {
+		if b.Spec.CredentialsRef.Name == "" {
+			return types.NamespacedName{}, fmt.Errorf("credentialsRef or tenantRef must be set")
+		}
+		// TODO: fix namespace default
+		return types.NamespacedName{Name: b.Spec.CredentialsRef.Name, Namespace: b.Namespace}, nil
+	}
+
+	tenant := &Tenant{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: b.Spec.TenantRef.Name, Namespace: b.Namespace}, tenant)
+	if err != nil && errors.IsNotFound(err) {
+		return types.NamespacedName{}, fmt.Errorf("tenantNotFound")
+	} else if err != nil {
+		return types.NamespacedName{}, fmt.Errorf("errorGettingTenant")
+	}
+
+	return tenant.TenantSecretNamespacedName(), nil
+}
+
+// TenantReady checks if the Tenant referenced by the binding is Ready.
+// Bindings not referencing a Tenant are always ready
+func (b Binding) TenantReady(c client.Client) (bool, error) {
+	if b.Spec.TenantRef == nil {
+		return true, nil
+	}
+
+	tenant := &Tenant{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: b.Spec.TenantRef.Name, Namespace: b.Namespace}, tenant)
+	if err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	readyCondition := tenant.Status.GetCondition(TenantReady)
+	return readyCondition != nil && readyCondition.Status == v1.ConditionTrue, nil
+}
@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
 	portaClient "github.com/3scale/3scale-porta-go-client/client"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -110,7 +111,7 @@ type LimitPair struct {
 	B InternalLimit
 }
 
-func (d *LimitsDiff) reconcileWith3scale(c *portaClient.ThreeScaleClient, serviceId string, planID string) error {
+func (d *LimitsDiff) reconcileWith3scale(c porta.Client, serviceId string, planID string) error {
 
 	for _, limit := range d.MissingFromA {
 		metric, err := metricNametoMetric(c, serviceId, limit.Metric)
@@ -181,7 +182,7 @@ func diffLimits(aLimits []InternalLimit, bLimits []InternalLimit) LimitsDiff {
 	return limitDiff
 
 }
-func get3scaleLimitFromInternalLimit(c *portaClient.ThreeScaleClient, serviceID string, planID string, limit InternalLimit) (portaClient.Limit, error) {
+func get3scaleLimitFromInternalLimit(c porta.Client, serviceID string, planID string, limit InternalLimit) (portaClient.Limit, error) {
 
 	limits3scale, err := c.ListLimitsPerAppPlan(planID)
 	if err != nil {
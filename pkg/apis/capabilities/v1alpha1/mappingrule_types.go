@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
 	portaClient "github.com/3scale/3scale-porta-go-client/client"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -64,7 +65,7 @@ type MappingRuleList struct {
 func init() {
 	SchemeBuilder.Register(&MappingRule{}, &MappingRuleList{})
 }
-func get3scaleMappingRulefromInternalMappingRule(c *portaClient.ThreeScaleClient, serviceID string, internalMappingRule InternalMappingRule) (portaClient.MappingRule, error) {
+func get3scaleMappingRulefromInternalMappingRule(c porta.Client, serviceID string, internalMappingRule InternalMappingRule) (portaClient.MappingRule, error) {
 	mappingRules, err := c.ListMappingRule(serviceID)
 	metric, err := metricNametoMetric(c, serviceID, internalMappingRule.Metric)
 	internalIncrement := strconv.FormatInt(internalMappingRule.Increment, 10)
@@ -91,7 +92,7 @@ func getMappingRules(namespace string, matchLabels map[string]string, c client.C
 	err := c.List(context.TODO(), &opts, mappingRules)
 	return mappingRules, err
 }
-func getServiceMappingRulesFrom3scale(c *portaClient.ThreeScaleClient, service portaClient.Service) (*[]InternalMappingRule, error) {
+func getServiceMappingRulesFrom3scale(c porta.Client, service portaClient.Service) (*[]InternalMappingRule, error) {
 
 	var mappingRules []InternalMappingRule
 	mappingRulesFrom3scale, _ := c.ListMappingRule(service.ID)
@@ -221,7 +222,7 @@ func diffMappingRules(mappingRules1, mappingRules2 []InternalMappingRule) Mappin
 	return mappingRuleDiff
 }
 
-func (m MappingRuleDiff) reconcileWith3scale(c *portaClient.ThreeScaleClient, serviceId string, api InternalAPI) error {
+func (m MappingRuleDiff) reconcileWith3scale(c porta.Client, serviceId string, api InternalAPI) error {
 	for _, mappingRule := range m.MissingFromB {
 		metric, err := metricNametoMetric(c, serviceId, mappingRule.Metric)
 		if err != nil {
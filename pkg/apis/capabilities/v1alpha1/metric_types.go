@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
 	portaClient "github.com/3scale/3scale-porta-go-client/client"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -110,7 +111,7 @@ func diffMetrics(metrics1, metrics2 []InternalMetric) MetricsDiff {
 	}
 	return metricsDiff
 }
-func (d *MetricsDiff) ReconcileWith3scale(c *portaClient.ThreeScaleClient, serviceId string, api InternalAPI) error {
+func (d *MetricsDiff) ReconcileWith3scale(c porta.Client, serviceId string, api InternalAPI) error {
 
 	for _, metric := range d.MissingFromB {
 		err := createInternalMetricIn3scale(c, api, metric)
@@ -159,7 +160,7 @@ func getMetrics(namespace string, matchLabels map[string]string, c client.Client
 	return metrics, err
 }
 
-func metricNametoMetric(c *portaClient.ThreeScaleClient, serviceID string, metricName string) (portaClient.Metric, error) {
+func metricNametoMetric(c porta.Client, serviceID string, metricName string) (portaClient.Metric, error) {
 	m := portaClient.Metric{}
 	metrics, err := c.ListMetrics(serviceID)
 	if err != nil {
@@ -175,7 +176,7 @@ func metricNametoMetric(c *portaClient.ThreeScaleClient, serviceID string, metri
 	return m, fmt.Errorf("metric not found")
 }
 
-func metricIDtoMetric(c *portaClient.ThreeScaleClient, serviceID string, metricID string) (portaClient.Metric, error) {
+func metricIDtoMetric(c porta.Client, serviceID string, metricID string) (portaClient.Metric, error) {
 	m := portaClient.Metric{}
 
 	metrics, err := c.ListMetrics(serviceID)
@@ -193,7 +194,7 @@ func metricIDtoMetric(c *portaClient.ThreeScaleClient, serviceID string, metricI
 	return m, nil
 
 }
-func createInternalMetricIn3scale(c *portaClient.ThreeScaleClient, api InternalAPI, metric InternalMetric) error {
+func createInternalMetricIn3scale(c porta.Client, api InternalAPI, metric InternalMetric) error {
 
 	service, err := getServiceFromInternalAPI(c, api.Name)
 	if err != nil {
@@ -212,7 +213,7 @@ func newInternalMetricFromMetric(metric Metric) *InternalMetric {
 	return &internalMetric
 }
 
-func deleteInternalMetricFrom3scale(c *portaClient.ThreeScaleClient, api InternalAPI, metric InternalMetric) error {
+func deleteInternalMetricFrom3scale(c porta.Client, api InternalAPI, metric InternalMetric) error {
 
 	service, err := getServiceFromInternalAPI(c, api.Name)
 	if err != nil {
@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
 	portaClient "github.com/3scale/3scale-porta-go-client/client"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -102,7 +103,7 @@ type planPair struct {
 	B InternalPlan
 }
 
-func (d *plansDiff) reconcileWith3scale(c *portaClient.ThreeScaleClient, serviceId string, api InternalAPI) error {
+func (d *plansDiff) reconcileWith3scale(c porta.Client, serviceId string, api InternalAPI) error {
 
 	for _, plan := range d.MissingFromA {
 		plan3scale, err := get3scalePlanFromInternalPlan(c, serviceId, plan)
@@ -232,7 +233,7 @@ func comparePlans(a, b InternalPlan) bool {
 
 	return true
 }
-func get3scalePlanFromInternalPlan(c *portaClient.ThreeScaleClient, serviceID string, plan InternalPlan) (portaClient.Plan, error) {
+func get3scalePlanFromInternalPlan(c porta.Client, serviceID string, plan InternalPlan) (portaClient.Plan, error) {
 	plans3scale, err := c.ListAppPlanByServiceId(serviceID)
 	if err != nil {
 		return portaClient.Plan{}, err
@@ -6,6 +6,29 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TENANT_FINALIZER is set on Tenant objects so the 3scale tenant is cleaned up on deletion
+const TENANT_FINALIZER = "tenant.capabilities.3scale.net"
+
+// TenantDeletionPolicy defines what happens to the 3scale tenant when the Tenant object is deleted
+type TenantDeletionPolicy string
+
+const (
+	// TenantDeletionPolicyDelete schedules the 3scale tenant for deletion
+	TenantDeletionPolicyDelete TenantDeletionPolicy = "Delete"
+	// TenantDeletionPolicySuspend suspends the 3scale tenant, keeping its data
+	TenantDeletionPolicySuspend TenantDeletionPolicy = "Suspend"
+)
+
+// TenantDeletionPhase describes the progress of the 3scale tenant cleanup
+type TenantDeletionPhase string
+
+const (
+	TenantDeletionPhaseInProgress TenantDeletionPhase = "InProgress"
+	TenantDeletionPhaseFailed     TenantDeletionPhase = "Failed"
+	TenantDeletionPhaseCompleted  TenantDeletionPhase = "Completed"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -14,13 +37,41 @@ import (
 // TenantSpec defines the desired state of Tenant
 // +k8s:openapi-gen=true
 type TenantSpec struct {
-	Username               string             `json:"username"`
-	Email                  string             `json:"email"`
-	OrganizationName       string             `json:"organizationName"`
-	SystemMasterUrl        string             `json:"systemMasterUrl"`
+	Username         string `json:"username"`
+	Email            string `json:"email"`
+	OrganizationName string `json:"organizationName"`
+	// SystemMasterUrl is the master portal URL. Required unless APIManagerRef is set
+	//+optional
+	SystemMasterUrl        string             `json:"systemMasterUrl,omitempty"`
 	TenantSecretRef        v1.SecretReference `json:"tenantSecretRef"`
 	PasswordCredentialsRef v1.SecretReference `json:"passwordCredentialsRef"`
-	MasterCredentialsRef   v1.SecretReference `json:"masterCredentialsRef"`
+	// MasterCredentialsRef is the secret with the master access token. Required unless APIManagerRef is set
+	//+optional
+	MasterCredentialsRef v1.SecretReference `json:"masterCredentialsRef,omitempty"`
+	// APIManagerRef references an APIManager deployed in the same cluster.
+	// The master portal URL and credentials are read from it instead of
+	// SystemMasterUrl and MasterCredentialsRef
+	//+optional
+	APIManagerRef *APIManagerReference `json:"apiManagerRef,omitempty"`
+	//+optional
+	TLS *AdminPortalTLSSpec `json:"tls,omitempty"`
+	// DeletionPolicy is applied to the 3scale tenant when the Tenant object is deleted.
+	// One of Delete or Suspend. Defaults to Delete
+	//+optional
+	DeletionPolicy TenantDeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ResyncPeriod is the interval between reconciliations correcting any drift
+	// of the 3scale tenant from the spec. Defaults to 10m, 0 disables the periodic resync
+	//+optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+// APIManagerReference locates an APIManager object
+// +k8s:openapi-gen=true
+type APIManagerReference struct {
+	Name string `json:"name"`
+	// Namespace of the APIManager. Defaults to the Tenant namespace
+	//+optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // TenantStatus defines the observed state of Tenant
@@ -28,6 +79,89 @@ type TenantSpec struct {
 type TenantStatus struct {
 	TenantId int64 `json:"tenantId"`
 	AdminId  int64 `json:"adminId"`
+	//+optional
+	AdminURL string `json:"adminURL,omitempty"`
+	//+optional
+	ProviderKeySecretRef *v1.SecretReference `json:"providerKeySecretRef,omitempty"`
+	//+optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	//+optional
+	Conditions []TenantCondition `json:"conditions,omitempty"`
+	//+optional
+	Deletion *TenantDeletionStatus `json:"deletion,omitempty"`
+}
+
+type TenantConditionType string
+
+const (
+	// TenantReady means the 3scale tenant exists, its admin user is active
+	// and the provider key secret is available
+	TenantReady TenantConditionType = "Ready"
+	// TenantSynced means the 3scale tenant matches the spec as of the last reconciliation
+	TenantSynced TenantConditionType = "Synced"
+)
+
+// TenantCondition describes the state of the Tenant at a certain point
+// +k8s:openapi-gen=true
+type TenantCondition struct {
+	Type   TenantConditionType `json:"type" description:"type of Tenant condition"`
+	Status v1.ConditionStatus  `json:"status" description:"status of the condition, one of True, False, Unknown"`
+	// +optional
+	Reason string `json:"reason,omitempty" description:"one-word CamelCase reason for the condition's last transition"`
+	// +optional
+	Message string `json:"message,omitempty" description:"human-readable message indicating details about last transition"`
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" description:"last time the condition transit from one status to another"`
+}
+
+// GetCondition returns the condition of the given type, or nil if it is not set
+func (s *TenantStatus) GetCondition(condType TenantConditionType) *TenantCondition {
+	return getTenantCondition(s.Conditions, condType)
+}
+
+// SetCondition adds or updates the condition of the same type.
+// The last transition time is only changed when the condition status changes
+func (s *TenantStatus) SetCondition(condType TenantConditionType, status v1.ConditionStatus, reason, message string) {
+	s.Conditions = setTenantCondition(s.Conditions, condType, status, reason, message)
+}
+
+func getTenantCondition(conditions []TenantCondition, condType TenantConditionType) *TenantCondition {
+	for i := range conditions {
+		if conditions[i].Type == condType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func setTenantCondition(conditions []TenantCondition, condType TenantConditionType, status v1.ConditionStatus, reason, message string) []TenantCondition {
+	current := getTenantCondition(conditions, condType)
+	if current == nil {
+		return append(conditions, TenantCondition{
+			Type:               condType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: metav1.Now(),
+		})
+	}
+
+	if current.Status != status {
+		current.LastTransitionTime = metav1.Now()
+	}
+	current.Status = status
+	current.Reason = reason
+	current.Message = message
+	return conditions
+}
+
+// TenantDeletionStatus defines the observed state of the 3scale tenant cleanup
+// +k8s:openapi-gen=true
+type TenantDeletionStatus struct {
+	Policy TenantDeletionPolicy `json:"policy"`
+	Phase  TenantDeletionPhase  `json:"phase"`
+	//+optional
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -55,9 +189,57 @@ func (t *Tenant) SetDefaults() bool {
 		ts.TenantSecretRef.Namespace = t.Namespace
 		changed = true
 	}
+	if ts.DeletionPolicy == "" {
+		ts.DeletionPolicy = TenantDeletionPolicyDelete
+		changed = true
+	}
 	return changed
 }
 
+// TenantSecretNamespacedName returns the location of the Secret with the tenant
+// credentials. It defaults to the tenant namespace, so it does not depend on the
+// defaults being stored
+func (t *Tenant) TenantSecretNamespacedName() types.NamespacedName {
+	nn := types.NamespacedName{Name: t.Spec.TenantSecretRef.Name, Namespace: t.Spec.TenantSecretRef.Namespace}
+	if nn.Namespace == "" {
+		nn.Namespace = t.Namespace
+	}
+	return nn
+}
+
+// IsTerminating checks if the tenant object has been marked for deletion
+func (t *Tenant) IsTerminating() bool {
+	return t.DeletionTimestamp != nil
+}
+
+// HasFinalizer checks if the tenant object has the tenant finalizer set
+func (t *Tenant) HasFinalizer() bool {
+	for _, finalizer := range t.GetFinalizers() {
+		if finalizer == TENANT_FINALIZER {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFinalizer adds the tenant finalizer to the meta of the tenant object
+func (t *Tenant) AddFinalizer() {
+	if !t.HasFinalizer() {
+		t.SetFinalizers(append(t.GetFinalizers(), TENANT_FINALIZER))
+	}
+}
+
+// RemoveFinalizer removes the tenant finalizer from the meta of the tenant object
+func (t *Tenant) RemoveFinalizer() {
+	var finalizers []string
+	for _, finalizer := range t.GetFinalizers() {
+		if finalizer != TENANT_FINALIZER {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	t.SetFinalizers(finalizers)
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // TenantList contains a list of Tenant
@@ -0,0 +1,147 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TENANTUSER_FINALIZER is set on TenantUser objects so the 3scale user is deleted on deletion
+const TENANTUSER_FINALIZER = "tenantuser.capabilities.3scale.net"
+
+// TenantUserRole defines the role of the user in the tenant
+type TenantUserRole string
+
+const (
+	TenantUserRoleAdmin  TenantUserRole = "admin"
+	TenantUserRoleMember TenantUserRole = "member"
+)
+
+// TenantUserState defines the desired state of the user in the tenant
+type TenantUserState string
+
+const (
+	TenantUserStateActive    TenantUserState = "active"
+	TenantUserStateSuspended TenantUserState = "suspended"
+)
+
+// TenantUserSpec defines the desired state of TenantUser
+// +k8s:openapi-gen=true
+type TenantUserSpec struct {
+	// TenantRef references the Tenant object, in the same namespace, the user belongs to
+	TenantRef              v1.LocalObjectReference `json:"tenantRef"`
+	Username               string                  `json:"username"`
+	Email                  string                  `json:"email"`
+	PasswordCredentialsRef v1.SecretReference      `json:"passwordCredentialsRef"`
+	// Role is one of admin or member. Defaults to member
+	//+optional
+	Role TenantUserRole `json:"role,omitempty"`
+	// State is one of active or suspended. Defaults to active
+	//+optional
+	State TenantUserState `json:"state,omitempty"`
+}
+
+// TenantUserStatus defines the observed state of TenantUser
+// +k8s:openapi-gen=true
+type TenantUserStatus struct {
+	//+optional
+	UserId int64 `json:"userId,omitempty"`
+	//+optional
+	TenantId int64 `json:"tenantId,omitempty"`
+	//+optional
+	State string `json:"state,omitempty"`
+	//+optional
+	Role TenantUserRole `json:"role,omitempty"`
+	//+optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	//+optional
+	Conditions []TenantCondition `json:"conditions,omitempty"`
+}
+
+// GetCondition returns the condition of the given type, or nil if it is not set
+func (s *TenantUserStatus) GetCondition(condType TenantConditionType) *TenantCondition {
+	return getTenantCondition(s.Conditions, condType)
+}
+
+// SetCondition adds or updates the condition of the same type.
+// The last transition time is only changed when the condition status changes
+func (s *TenantUserStatus) SetCondition(condType TenantConditionType, status v1.ConditionStatus, reason, message string) {
+	s.Conditions = setTenantCondition(s.Conditions, condType, status, reason, message)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantUser is the Schema for the tenantusers API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type TenantUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TenantUserSpec   `json:"spec,omitempty"`
+	Status TenantUserStatus `json:"status,omitempty"`
+}
+
+// SetDefaults sets the default values for the tenant user spec and returns true if the spec was changed
+func (u *TenantUser) SetDefaults() bool {
+	changed := false
+	us := &u.Spec
+	if us.Role == "" {
+		us.Role = TenantUserRoleMember
+		changed = true
+	}
+	if us.State == "" {
+		us.State = TenantUserStateActive
+		changed = true
+	}
+	if us.PasswordCredentialsRef.Namespace == "" {
+		us.PasswordCredentialsRef.Namespace = u.Namespace
+		changed = true
+	}
+	return changed
+}
+
+// IsTerminating checks if the tenant user object has been marked for deletion
+func (u *TenantUser) IsTerminating() bool {
+	return u.DeletionTimestamp != nil
+}
+
+// HasFinalizer checks if the tenant user object has the tenant user finalizer set
+func (u *TenantUser) HasFinalizer() bool {
+	for _, finalizer := range u.GetFinalizers() {
+		if finalizer == TENANTUSER_FINALIZER {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFinalizer adds the tenant user finalizer to the meta of the tenant user object
+func (u *TenantUser) AddFinalizer() {
+	if !u.HasFinalizer() {
+		u.SetFinalizers(append(u.GetFinalizers(), TENANTUSER_FINALIZER))
+	}
+}
+
+// RemoveFinalizer removes the tenant user finalizer from the meta of the tenant user object
+func (u *TenantUser) RemoveFinalizer() {
+	var finalizers []string
+	for _, finalizer := range u.GetFinalizers() {
+		if finalizer != TENANTUSER_FINALIZER {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	u.SetFinalizers(finalizers)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantUserList contains a list of TenantUser
+type TenantUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenantUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantUser{}, &TenantUserList{})
+}
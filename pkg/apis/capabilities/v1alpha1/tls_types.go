@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AdminPortalTLSSpec defines how the TLS connections to a 3scale admin portal are verified
+// +k8s:openapi-gen=true
+type AdminPortalTLSSpec struct {
+	// CABundleRef references the PEM encoded CA certificates used to verify
+	// the admin portal certificate, in addition to the system roots
+	//+optional
+	CABundleRef *CABundleReference `json:"caBundleRef,omitempty"`
+	// InsecureSkipVerify disables the admin portal certificate verification
+	//+optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// CABundleReference selects a key of a ConfigMap or a Secret holding a CA bundle.
+// Exactly one of the references must be set
+// +k8s:openapi-gen=true
+type CABundleReference struct {
+	//+optional
+	ConfigMapKeyRef *v1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	//+optional
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// TLSConfig builds the tls config described by the spec. The referenced CA bundle
+// is read from the given namespace. A nil spec verifies certificates against the system roots
+func (t *AdminPortalTLSSpec) TLSConfig(c client.Reader, namespace string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if t == nil {
+		return tlsConfig, nil
+	}
+
+	tlsConfig.InsecureSkipVerify = t.InsecureSkipVerify
+
+	if t.CABundleRef == nil {
+		return tlsConfig, nil
+	}
+
+	caBundle, err := t.CABundleRef.read(c, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("no valid PEM certificates found in CA bundle")
+	}
+	tlsConfig.RootCAs = rootCAs
+
+	return tlsConfig, nil
+}
+
+func (r *CABundleReference) read(c client.Reader, namespace string) ([]byte, error) {
+	switch {
+	case r.ConfigMapKeyRef != nil && r.SecretKeyRef != nil:
+		return nil, fmt.Errorf("CA bundle reference must set only one of configMapKeyRef and secretKeyRef")
+	case r.ConfigMapKeyRef != nil:
+		configMap := &v1.ConfigMap{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: r.ConfigMapKeyRef.Name, Namespace: namespace}, configMap)
+		if err != nil {
+			return nil, err
+		}
+		caBundle, ok := configMap.Data[r.ConfigMapKeyRef.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found in configmap %s", r.ConfigMapKeyRef.Key, r.ConfigMapKeyRef.Name)
+		}
+		return []byte(caBundle), nil
+	case r.SecretKeyRef != nil:
+		secret := &v1.Secret{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: r.SecretKeyRef.Name, Namespace: namespace}, secret)
+		if err != nil {
+			return nil, err
+		}
+		caBundle, ok := secret.Data[r.SecretKeyRef.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found in secret %s", r.SecretKeyRef.Key, r.SecretKeyRef.Name)
+		}
+		return caBundle, nil
+	default:
+		return nil, fmt.Errorf("CA bundle reference must set one of configMapKeyRef and secretKeyRef")
+	}
+}
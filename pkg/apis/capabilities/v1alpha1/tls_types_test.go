@@ -0,0 +1,148 @@
+package v1alpha1
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const tlsTestNamespace = "operator-test"
+
+// selfSignedCA returns a PEM encoded self signed CA certificate and its parsed form
+func selfSignedCA(t *testing.T) ([]byte, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "master-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), cert
+}
+
+func newTLSTestClient(objs ...runtime.Object) client.Client {
+	return fake.NewFakeClient(objs...)
+}
+
+func assertTrustsCA(t *testing.T, spec *AdminPortalTLSSpec, c client.Client, ca *x509.Certificate) {
+	tlsConfig, err := spec.TLSConfig(c, tlsTestNamespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tlsConfig.RootCAs == nil {
+		t.Fatal("expected root CAs to be set")
+	}
+	if _, err := ca.Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs}); err != nil {
+		t.Errorf("expected the CA bundle to be trusted: %v", err)
+	}
+}
+
+func TestTLSConfigNilSpec(t *testing.T) {
+	var spec *AdminPortalTLSSpec
+	tlsConfig, err := spec.TLSConfig(newTLSTestClient(), tlsTestNamespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tlsConfig.InsecureSkipVerify || tlsConfig.RootCAs != nil {
+		t.Errorf("expected a nil spec to verify against the system roots, got %+v", tlsConfig)
+	}
+}
+
+func TestTLSConfigInsecureSkipVerify(t *testing.T) {
+	spec := &AdminPortalTLSSpec{InsecureSkipVerify: true}
+	tlsConfig, err := spec.TLSConfig(newTLSTestClient(), tlsTestNamespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("expected certificate verification to be disabled")
+	}
+}
+
+func TestTLSConfigCABundleFromConfigMap(t *testing.T) {
+	caPEM, ca := selfSignedCA(t)
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "master-ca-bundle", Namespace: tlsTestNamespace},
+		Data:       map[string]string{"ca.crt": string(caPEM)},
+	}
+	spec := &AdminPortalTLSSpec{CABundleRef: &CABundleReference{
+		ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "master-ca-bundle"},
+			Key:                  "ca.crt",
+		},
+	}}
+	assertTrustsCA(t, spec, newTLSTestClient(configMap), ca)
+}
+
+func TestTLSConfigCABundleFromSecret(t *testing.T) {
+	caPEM, ca := selfSignedCA(t)
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "master-ca-bundle", Namespace: tlsTestNamespace},
+		Data:       map[string][]byte{"ca.crt": caPEM},
+	}
+	spec := &AdminPortalTLSSpec{CABundleRef: &CABundleReference{
+		SecretKeyRef: &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "master-ca-bundle"},
+			Key:                  "ca.crt",
+		},
+	}}
+	assertTrustsCA(t, spec, newTLSTestClient(secret), ca)
+}
+
+func TestTLSConfigInvalidCABundle(t *testing.T) {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "master-ca-bundle", Namespace: tlsTestNamespace},
+		Data:       map[string]string{"ca.crt": "not a certificate"},
+	}
+	configMapRef := &v1.ConfigMapKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "master-ca-bundle"},
+		Key:                  "ca.crt",
+	}
+	secretRef := &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "master-ca-bundle"},
+		Key:                  "ca.crt",
+	}
+
+	cases := []struct {
+		name string
+		ref  *CABundleReference
+	}{
+		{"invalid PEM", &CABundleReference{ConfigMapKeyRef: configMapRef}},
+		{"missing key", &CABundleReference{ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: configMapRef.LocalObjectReference, Key: "other.crt"}}},
+		{"missing secret", &CABundleReference{SecretKeyRef: secretRef}},
+		{"both references", &CABundleReference{ConfigMapKeyRef: configMapRef, SecretKeyRef: secretRef}},
+		{"no reference", &CABundleReference{}},
+	}
+	for _, tc := range cases {
+		spec := &AdminPortalTLSSpec{CABundleRef: tc.ref}
+		if _, err := spec.TLSConfig(newTLSTestClient(configMap), tlsTestNamespace); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -121,6 +122,22 @@ func (in *APIList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerReference) DeepCopyInto(out *APIManagerReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerReference.
+func (in *APIManagerReference) DeepCopy() *APIManagerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIPair) DeepCopyInto(out *APIPair) {
 	*out = *in
@@ -286,6 +303,27 @@ func (in *APIsDiff) DeepCopy() *APIsDiff {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminPortalTLSSpec) DeepCopyInto(out *AdminPortalTLSSpec) {
+	*out = *in
+	if in.CABundleRef != nil {
+		in, out := &in.CABundleRef, &out.CABundleRef
+		*out = new(CABundleReference)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminPortalTLSSpec.
+func (in *AdminPortalTLSSpec) DeepCopy() *AdminPortalTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminPortalTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApicastAuthenticationSettings) DeepCopyInto(out *ApicastAuthenticationSettings) {
 	*out = *in
@@ -400,6 +438,23 @@ func (in *Binding) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingCondition) DeepCopyInto(out *BindingCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingCondition.
+func (in *BindingCondition) DeepCopy() *BindingCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BindingList) DeepCopyInto(out *BindingList) {
 	*out = *in
@@ -437,7 +492,17 @@ func (in *BindingList) DeepCopyObject() runtime.Object {
 func (in *BindingSpec) DeepCopyInto(out *BindingSpec) {
 	*out = *in
 	out.CredentialsRef = in.CredentialsRef
+	if in.TenantRef != nil {
+		in, out := &in.TenantRef, &out.TenantRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	in.APISelector.DeepCopyInto(&out.APISelector)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(AdminPortalTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -474,6 +539,13 @@ func (in *BindingStatus) DeepCopyInto(out *BindingStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]BindingCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -487,6 +559,32 @@ func (in *BindingStatus) DeepCopy() *BindingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CABundleReference) DeepCopyInto(out *CABundleReference) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CABundleReference.
+func (in *CABundleReference) DeepCopy() *CABundleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CABundleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CodePlugin) DeepCopyInto(out *CodePlugin) {
 	*out = *in
@@ -1527,8 +1625,8 @@ func (in *Tenant) DeepCopyInto(out *Tenant) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1550,6 +1648,39 @@ func (in *Tenant) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantCondition) DeepCopyInto(out *TenantCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantCondition.
+func (in *TenantCondition) DeepCopy() *TenantCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantDeletionStatus) DeepCopyInto(out *TenantDeletionStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantDeletionStatus.
+func (in *TenantDeletionStatus) DeepCopy() *TenantDeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantDeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantList) DeepCopyInto(out *TenantList) {
 	*out = *in
@@ -1589,6 +1720,21 @@ func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 	out.TenantSecretRef = in.TenantSecretRef
 	out.PasswordCredentialsRef = in.PasswordCredentialsRef
 	out.MasterCredentialsRef = in.MasterCredentialsRef
+	if in.APIManagerRef != nil {
+		in, out := &in.APIManagerRef, &out.APIManagerRef
+		*out = new(APIManagerReference)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(AdminPortalTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -1605,6 +1751,23 @@ func (in *TenantSpec) DeepCopy() *TenantSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 	*out = *in
+	if in.ProviderKeySecretRef != nil {
+		in, out := &in.ProviderKeySecretRef, &out.ProviderKeySecretRef
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]TenantCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(TenantDeletionStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -1617,3 +1780,105 @@ func (in *TenantStatus) DeepCopy() *TenantStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUser) DeepCopyInto(out *TenantUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUser.
+func (in *TenantUser) DeepCopy() *TenantUser {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUserList) DeepCopyInto(out *TenantUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUserList.
+func (in *TenantUserList) DeepCopy() *TenantUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUserSpec) DeepCopyInto(out *TenantUserSpec) {
+	*out = *in
+	out.TenantRef = in.TenantRef
+	out.PasswordCredentialsRef = in.PasswordCredentialsRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUserSpec.
+func (in *TenantUserSpec) DeepCopy() *TenantUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUserStatus) DeepCopyInto(out *TenantUserStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]TenantCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUserStatus.
+func (in *TenantUserStatus) DeepCopy() *TenantUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
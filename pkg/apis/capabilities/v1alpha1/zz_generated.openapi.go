@@ -13,27 +13,36 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.API":               schema_pkg_apis_capabilities_v1alpha1_API(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.APISpec":           schema_pkg_apis_capabilities_v1alpha1_APISpec(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.APIStatus":         schema_pkg_apis_capabilities_v1alpha1_APIStatus(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Binding":           schema_pkg_apis_capabilities_v1alpha1_Binding(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.BindingSpec":       schema_pkg_apis_capabilities_v1alpha1_BindingSpec(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.BindingStatus":     schema_pkg_apis_capabilities_v1alpha1_BindingStatus(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Limit":             schema_pkg_apis_capabilities_v1alpha1_Limit(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.LimitSpec":         schema_pkg_apis_capabilities_v1alpha1_LimitSpec(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.LimitStatus":       schema_pkg_apis_capabilities_v1alpha1_LimitStatus(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MappingRule":       schema_pkg_apis_capabilities_v1alpha1_MappingRule(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MappingRuleSpec":   schema_pkg_apis_capabilities_v1alpha1_MappingRuleSpec(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MappingRuleStatus": schema_pkg_apis_capabilities_v1alpha1_MappingRuleStatus(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Metric":            schema_pkg_apis_capabilities_v1alpha1_Metric(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MetricSpec":        schema_pkg_apis_capabilities_v1alpha1_MetricSpec(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MetricStatus":      schema_pkg_apis_capabilities_v1alpha1_MetricStatus(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Plan":              schema_pkg_apis_capabilities_v1alpha1_Plan(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.PlanSpec":          schema_pkg_apis_capabilities_v1alpha1_PlanSpec(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.PlanStatus":        schema_pkg_apis_capabilities_v1alpha1_PlanStatus(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Tenant":            schema_pkg_apis_capabilities_v1alpha1_Tenant(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantSpec":        schema_pkg_apis_capabilities_v1alpha1_TenantSpec(ref),
-		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantStatus":      schema_pkg_apis_capabilities_v1alpha1_TenantStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.API":                  schema_pkg_apis_capabilities_v1alpha1_API(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.APIManagerReference":  schema_pkg_apis_capabilities_v1alpha1_APIManagerReference(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.APISpec":              schema_pkg_apis_capabilities_v1alpha1_APISpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.APIStatus":            schema_pkg_apis_capabilities_v1alpha1_APIStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.AdminPortalTLSSpec":   schema_pkg_apis_capabilities_v1alpha1_AdminPortalTLSSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Binding":              schema_pkg_apis_capabilities_v1alpha1_Binding(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.BindingCondition":     schema_pkg_apis_capabilities_v1alpha1_BindingCondition(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.BindingSpec":          schema_pkg_apis_capabilities_v1alpha1_BindingSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.BindingStatus":        schema_pkg_apis_capabilities_v1alpha1_BindingStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.CABundleReference":    schema_pkg_apis_capabilities_v1alpha1_CABundleReference(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Limit":                schema_pkg_apis_capabilities_v1alpha1_Limit(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.LimitSpec":            schema_pkg_apis_capabilities_v1alpha1_LimitSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.LimitStatus":          schema_pkg_apis_capabilities_v1alpha1_LimitStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MappingRule":          schema_pkg_apis_capabilities_v1alpha1_MappingRule(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MappingRuleSpec":      schema_pkg_apis_capabilities_v1alpha1_MappingRuleSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MappingRuleStatus":    schema_pkg_apis_capabilities_v1alpha1_MappingRuleStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Metric":               schema_pkg_apis_capabilities_v1alpha1_Metric(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MetricSpec":           schema_pkg_apis_capabilities_v1alpha1_MetricSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.MetricStatus":         schema_pkg_apis_capabilities_v1alpha1_MetricStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Plan":                 schema_pkg_apis_capabilities_v1alpha1_Plan(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.PlanSpec":             schema_pkg_apis_capabilities_v1alpha1_PlanSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.PlanStatus":           schema_pkg_apis_capabilities_v1alpha1_PlanStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.Tenant":               schema_pkg_apis_capabilities_v1alpha1_Tenant(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantCondition":      schema_pkg_apis_capabilities_v1alpha1_TenantCondition(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantDeletionStatus": schema_pkg_apis_capabilities_v1alpha1_TenantDeletionStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantSpec":           schema_pkg_apis_capabilities_v1alpha1_TenantSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantStatus":         schema_pkg_apis_capabilities_v1alpha1_TenantStatus(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantUser":           schema_pkg_apis_capabilities_v1alpha1_TenantUser(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantUserSpec":       schema_pkg_apis_capabilities_v1alpha1_TenantUserSpec(ref),
+		"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantUserStatus":     schema_pkg_apis_capabilities_v1alpha1_TenantUserStatus(ref),
 	}
 }
 
@@ -80,6 +89,33 @@ func schema_pkg_apis_capabilities_v1alpha1_API(ref common.ReferenceCallback) com
 	}
 }
 
+func schema_pkg_apis_capabilities_v1alpha1_APIManagerReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "APIManagerReference locates an APIManager object",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the APIManager. Defaults to the Tenant namespace",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
 func schema_pkg_apis_capabilities_v1alpha1_APISpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -128,6 +164,33 @@ func schema_pkg_apis_capabilities_v1alpha1_APIStatus(ref common.ReferenceCallbac
 	}
 }
 
+func schema_pkg_apis_capabilities_v1alpha1_AdminPortalTLSSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AdminPortalTLSSpec defines how the TLS connections to a 3scale admin portal are verified",
+				Properties: map[string]spec.Schema{
+					"caBundleRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CABundleRef references the PEM encoded CA certificates used to verify the admin portal certificate, in addition to the system roots",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.CABundleReference"),
+						},
+					},
+					"insecureSkipVerify": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InsecureSkipVerify disables the admin portal certificate verification",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.CABundleReference"},
+	}
+}
+
 func schema_pkg_apis_capabilities_v1alpha1_Binding(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -171,6 +234,50 @@ func schema_pkg_apis_capabilities_v1alpha1_Binding(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_capabilities_v1alpha1_BindingCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BindingCondition describes the state of the Binding at a certain point",
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_capabilities_v1alpha1_BindingSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -179,7 +286,14 @@ func schema_pkg_apis_capabilities_v1alpha1_BindingSpec(ref common.ReferenceCallb
 				Properties: map[string]spec.Schema{
 					"credentialsRef": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/api/core/v1.SecretReference"),
+							Description: "CredentialsRef references the Secret with the tenant credentials. Either credentialsRef or tenantRef must be set",
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"tenantRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TenantRef references a Tenant object, in the same namespace, whose credentials Secret is used",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
 						},
 					},
 					"apiSelector": {
@@ -187,12 +301,16 @@ func schema_pkg_apis_capabilities_v1alpha1_BindingSpec(ref common.ReferenceCallb
 							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
 						},
 					},
+					"tls": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.AdminPortalTLSSpec"),
+						},
+					},
 				},
-				Required: []string{"credentialsRef"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.SecretReference", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.AdminPortalTLSSpec", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.SecretReference", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -225,11 +343,47 @@ func schema_pkg_apis_capabilities_v1alpha1_BindingStatus(ref common.ReferenceCal
 							Format: "",
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.BindingCondition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.BindingCondition", "k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp"},
+	}
+}
+
+func schema_pkg_apis_capabilities_v1alpha1_CABundleReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CABundleReference selects a key of a ConfigMap or a Secret holding a CA bundle. Exactly one of the references must be set",
+				Properties: map[string]spec.Schema{
+					"configMapKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/api/core/v1.ConfigMapKeySelector"),
+						},
+					},
+					"secretKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/api/core/v1.SecretKeySelector"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp"},
+			"k8s.io/api/core/v1.ConfigMapKeySelector", "k8s.io/api/core/v1.SecretKeySelector"},
 	}
 }
 
@@ -641,6 +795,82 @@ func schema_pkg_apis_capabilities_v1alpha1_Tenant(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_capabilities_v1alpha1_TenantCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantCondition describes the state of the Tenant at a certain point",
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_capabilities_v1alpha1_TenantDeletionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantDeletionStatus defines the observed state of the 3scale tenant cleanup",
+				Properties: map[string]spec.Schema{
+					"policy": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"policy", "phase"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
 func schema_pkg_apis_capabilities_v1alpha1_TenantSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -667,8 +897,9 @@ func schema_pkg_apis_capabilities_v1alpha1_TenantSpec(ref common.ReferenceCallba
 					},
 					"systemMasterUrl": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "SystemMasterUrl is the master portal URL. Required unless APIManagerRef is set",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 					"tenantSecretRef": {
@@ -683,15 +914,40 @@ func schema_pkg_apis_capabilities_v1alpha1_TenantSpec(ref common.ReferenceCallba
 					},
 					"masterCredentialsRef": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/api/core/v1.SecretReference"),
+							Description: "MasterCredentialsRef is the secret with the master access token. Required unless APIManagerRef is set",
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"apiManagerRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIManagerRef references an APIManager deployed in the same cluster. The master portal URL and credentials are read from it instead of SystemMasterUrl and MasterCredentialsRef",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.APIManagerReference"),
+						},
+					},
+					"tls": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.AdminPortalTLSSpec"),
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPolicy is applied to the 3scale tenant when the Tenant object is deleted. One of Delete or Suspend. Defaults to Delete",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resyncPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResyncPeriod is the interval between reconciliations correcting any drift of the 3scale tenant from the spec. Defaults to 10m, 0 disables the periodic resync",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
 				},
-				Required: []string{"username", "email", "organizationName", "systemMasterUrl", "tenantSecretRef", "passwordCredentialsRef", "masterCredentialsRef"},
+				Required: []string{"username", "email", "organizationName", "tenantSecretRef", "passwordCredentialsRef"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.SecretReference"},
+			"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.APIManagerReference", "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.AdminPortalTLSSpec", "k8s.io/api/core/v1.SecretReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
@@ -713,10 +969,196 @@ func schema_pkg_apis_capabilities_v1alpha1_TenantStatus(ref common.ReferenceCall
 							Format: "int64",
 						},
 					},
+					"adminURL": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"providerKeySecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantCondition"),
+									},
+								},
+							},
+						},
+					},
+					"deletion": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantDeletionStatus"),
+						},
+					},
 				},
 				Required: []string{"tenantId", "adminId"},
 			},
 		},
-		Dependencies: []string{},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantCondition", "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantDeletionStatus", "k8s.io/api/core/v1.SecretReference"},
+	}
+}
+
+func schema_pkg_apis_capabilities_v1alpha1_TenantUser(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantUser is the Schema for the tenantusers API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantUserSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantUserStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantUserSpec", "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantUserStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_capabilities_v1alpha1_TenantUserSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantUserSpec defines the desired state of TenantUser",
+				Properties: map[string]spec.Schema{
+					"tenantRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TenantRef references the Tenant object, in the same namespace, the user belongs to",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+					"username": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"email": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"passwordCredentialsRef": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"role": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Role is one of admin or member. Defaults to member",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State is one of active or suspended. Defaults to active",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"tenantRef", "username", "email", "passwordCredentialsRef"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.SecretReference"},
+	}
+}
+
+func schema_pkg_apis_capabilities_v1alpha1_TenantUserStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TenantUserStatus defines the observed state of TenantUser",
+				Properties: map[string]spec.Schema{
+					"userId": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"tenantId": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"role": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantCondition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1.TenantCondition"},
 	}
 }
@@ -0,0 +1,419 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.Log.WithName("cache")
+
+// WatchNamespaces returns the namespaces of a comma separated WATCH_NAMESPACE
+// value. An empty list means all namespaces
+func WatchNamespaces(watchNamespace string) []string {
+	namespaces := []string{}
+	seen := map[string]bool{}
+	for _, namespace := range strings.Split(watchNamespace, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" && !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// MultiNamespacedCacheBuilder returns a manager cache builder watching only
+// the given namespaces, with a cache per namespace. Unlike a cache for all
+// namespaces, it only needs permissions in the watched namespaces
+func MultiNamespacedCacheBuilder(namespaces []string) manager.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		caches := map[string]cache.Cache{}
+		for _, namespace := range namespaces {
+			opts.Namespace = namespace
+			namespaceCache, err := cache.New(config, opts)
+			if err != nil {
+				return nil, err
+			}
+			caches[namespace] = namespaceCache
+		}
+		return newMultiNamespaceCache(caches), nil
+	}
+}
+
+// multiNamespaceCache serves reads from the cache of the namespace of the
+// object. Lists without a namespace are aggregated from all the caches
+type multiNamespaceCache struct {
+	namespaceToCache map[string]cache.Cache
+	// namespaces are the keys of namespaceToCache, sorted
+	namespaces []string
+}
+
+var _ cache.Cache = &multiNamespaceCache{}
+
+func newMultiNamespaceCache(caches map[string]cache.Cache) *multiNamespaceCache {
+	namespaces := []string{}
+	for namespace := range caches {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return &multiNamespaceCache{namespaceToCache: caches, namespaces: namespaces}
+}
+
+// NamespaceNotWatchedError is returned when reading an object from a
+// namespace that is not watched
+type NamespaceNotWatchedError struct {
+	Namespace string
+}
+
+func (e *NamespaceNotWatchedError) Error() string {
+	return fmt.Sprintf("namespace %q is not watched by the operator", e.Namespace)
+}
+
+// Get implements client.Reader
+func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	namespaceCache, ok := c.namespaceToCache[key.Namespace]
+	if !ok {
+		return &NamespaceNotWatchedError{Namespace: key.Namespace}
+	}
+	return namespaceCache.Get(ctx, key, obj)
+}
+
+// List implements client.Reader
+func (c *multiNamespaceCache) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	if opts != nil && opts.Namespace != "" {
+		namespaceCache, ok := c.namespaceToCache[opts.Namespace]
+		if !ok {
+			return &NamespaceNotWatchedError{Namespace: opts.Namespace}
+		}
+		return namespaceCache.List(ctx, opts, list)
+	}
+
+	items := []runtime.Object{}
+	for _, namespace := range c.namespaces {
+		namespaceList := list.DeepCopyObject()
+		err := c.namespaceToCache[namespace].List(ctx, opts, namespaceList)
+		if err != nil {
+			return err
+		}
+		namespaceItems, err := apimeta.ExtractList(namespaceList)
+		if err != nil {
+			return err
+		}
+		items = append(items, namespaceItems...)
+	}
+	return apimeta.SetList(list, items)
+}
+
+// GetInformer implements cache.Informers
+func (c *multiNamespaceCache) GetInformer(obj runtime.Object) (toolscache.SharedIndexInformer, error) {
+	informers := map[string]toolscache.SharedIndexInformer{}
+	for _, namespace := range c.namespaces {
+		informer, err := c.namespaceToCache[namespace].GetInformer(obj)
+		if err != nil {
+			return nil, err
+		}
+		informers[namespace] = informer
+	}
+	return newMultiNamespaceInformer(informers), nil
+}
+
+// GetInformerForKind implements cache.Informers
+func (c *multiNamespaceCache) GetInformerForKind(gvk schema.GroupVersionKind) (toolscache.SharedIndexInformer, error) {
+	informers := map[string]toolscache.SharedIndexInformer{}
+	for _, namespace := range c.namespaces {
+		informer, err := c.namespaceToCache[namespace].GetInformerForKind(gvk)
+		if err != nil {
+			return nil, err
+		}
+		informers[namespace] = informer
+	}
+	return newMultiNamespaceInformer(informers), nil
+}
+
+// Start implements cache.Informers. It blocks until stopCh is closed
+func (c *multiNamespaceCache) Start(stopCh <-chan struct{}) error {
+	for _, namespace := range c.namespaces {
+		go func(namespace string, namespaceCache cache.Cache) {
+			if err := namespaceCache.Start(stopCh); err != nil {
+				log.Error(err, "Cache failed to start", "Namespace", namespace)
+			}
+		}(namespace, c.namespaceToCache[namespace])
+	}
+	<-stopCh
+	return nil
+}
+
+// WaitForCacheSync implements cache.Informers
+func (c *multiNamespaceCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	synced := true
+	for _, namespace := range c.namespaces {
+		if !c.namespaceToCache[namespace].WaitForCacheSync(stop) {
+			synced = false
+		}
+	}
+	return synced
+}
+
+// IndexField implements cache.Informers
+func (c *multiNamespaceCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	for _, namespace := range c.namespaces {
+		err := c.namespaceToCache[namespace].IndexField(obj, field, extractValue)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// multiNamespaceInformer is the informer of an object type in several
+// namespaces, backed by an informer per namespace. Its store and its
+// indexer read from the informers of all the namespaces
+type multiNamespaceInformer struct {
+	namespaceToInformer map[string]toolscache.SharedIndexInformer
+	// namespaces are the keys of namespaceToInformer, sorted
+	namespaces []string
+}
+
+var _ toolscache.SharedIndexInformer = &multiNamespaceInformer{}
+
+func newMultiNamespaceInformer(informers map[string]toolscache.SharedIndexInformer) *multiNamespaceInformer {
+	namespaces := []string{}
+	for namespace := range informers {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return &multiNamespaceInformer{namespaceToInformer: informers, namespaces: namespaces}
+}
+
+func (i *multiNamespaceInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	for _, namespace := range i.namespaces {
+		i.namespaceToInformer[namespace].AddEventHandler(handler)
+	}
+}
+
+func (i *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	for _, namespace := range i.namespaces {
+		i.namespaceToInformer[namespace].AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+func (i *multiNamespaceInformer) AddIndexers(indexers toolscache.Indexers) error {
+	for _, namespace := range i.namespaces {
+		if err := i.namespaceToInformer[namespace].AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *multiNamespaceInformer) GetStore() toolscache.Store {
+	return i.GetIndexer()
+}
+
+func (i *multiNamespaceInformer) GetIndexer() toolscache.Indexer {
+	indexers := map[string]toolscache.Indexer{}
+	for _, namespace := range i.namespaces {
+		indexers[namespace] = i.namespaceToInformer[namespace].GetIndexer()
+	}
+	return &multiNamespaceIndexer{namespaceToIndexer: indexers, namespaces: i.namespaces}
+}
+
+// GetController returns the informer itself, which runs the informers of
+// all the namespaces
+func (i *multiNamespaceInformer) GetController() toolscache.Controller {
+	return i
+}
+
+// Run runs the informers of all the namespaces until stopCh is closed.
+// Informers returned by the cache must not be run, as the cache of each
+// namespace runs them
+func (i *multiNamespaceInformer) Run(stopCh <-chan struct{}) {
+	for _, namespace := range i.namespaces {
+		go i.namespaceToInformer[namespace].Run(stopCh)
+	}
+	<-stopCh
+}
+
+func (i *multiNamespaceInformer) HasSynced() bool {
+	for _, namespace := range i.namespaces {
+		if !i.namespaceToInformer[namespace].HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// LastSyncResourceVersion returns the resource versions of the namespaces
+// that have been synced, as a comma separated list of namespace=version.
+// Resource versions of different lists cannot be compared, so there is not
+// a single version for several namespaces
+func (i *multiNamespaceInformer) LastSyncResourceVersion() string {
+	versions := []string{}
+	for _, namespace := range i.namespaces {
+		if version := i.namespaceToInformer[namespace].LastSyncResourceVersion(); version != "" {
+			versions = append(versions, namespace+"="+version)
+		}
+	}
+	return strings.Join(versions, ",")
+}
+
+// ReadOnlyStoreError is returned when writing to the store of several
+// namespaces, which is written by the informer of each namespace
+type ReadOnlyStoreError struct{}
+
+func (e *ReadOnlyStoreError) Error() string {
+	return "the store of several namespaces is read only"
+}
+
+// multiNamespaceIndexer serves reads of objects from the indexer of their
+// namespace and aggregates lists and index lookups from all the indexers
+type multiNamespaceIndexer struct {
+	namespaceToIndexer map[string]toolscache.Indexer
+	// namespaces are the keys of namespaceToIndexer, sorted
+	namespaces []string
+}
+
+var _ toolscache.Indexer = &multiNamespaceIndexer{}
+
+func (i *multiNamespaceIndexer) Add(obj interface{}) error {
+	return &ReadOnlyStoreError{}
+}
+
+func (i *multiNamespaceIndexer) Update(obj interface{}) error {
+	return &ReadOnlyStoreError{}
+}
+
+func (i *multiNamespaceIndexer) Delete(obj interface{}) error {
+	return &ReadOnlyStoreError{}
+}
+
+func (i *multiNamespaceIndexer) Replace(list []interface{}, resourceVersion string) error {
+	return &ReadOnlyStoreError{}
+}
+
+func (i *multiNamespaceIndexer) Resync() error {
+	for _, namespace := range i.namespaces {
+		if err := i.namespaceToIndexer[namespace].Resync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *multiNamespaceIndexer) List() []interface{} {
+	items := []interface{}{}
+	for _, namespace := range i.namespaces {
+		items = append(items, i.namespaceToIndexer[namespace].List()...)
+	}
+	return items
+}
+
+func (i *multiNamespaceIndexer) ListKeys() []string {
+	keys := []string{}
+	for _, namespace := range i.namespaces {
+		keys = append(keys, i.namespaceToIndexer[namespace].ListKeys()...)
+	}
+	return keys
+}
+
+func (i *multiNamespaceIndexer) Get(obj interface{}) (interface{}, bool, error) {
+	key, err := toolscache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	return i.GetByKey(key)
+}
+
+func (i *multiNamespaceIndexer) GetByKey(key string) (interface{}, bool, error) {
+	namespace, _, err := toolscache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	indexer, ok := i.namespaceToIndexer[namespace]
+	if !ok {
+		return nil, false, &NamespaceNotWatchedError{Namespace: namespace}
+	}
+	return indexer.GetByKey(key)
+}
+
+func (i *multiNamespaceIndexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	items := []interface{}{}
+	for _, namespace := range i.namespaces {
+		namespaceItems, err := i.namespaceToIndexer[namespace].Index(indexName, obj)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, namespaceItems...)
+	}
+	return items, nil
+}
+
+func (i *multiNamespaceIndexer) IndexKeys(indexName, indexKey string) ([]string, error) {
+	keys := []string{}
+	for _, namespace := range i.namespaces {
+		namespaceKeys, err := i.namespaceToIndexer[namespace].IndexKeys(indexName, indexKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, namespaceKeys...)
+	}
+	return keys, nil
+}
+
+func (i *multiNamespaceIndexer) ListIndexFuncValues(indexName string) []string {
+	values := []string{}
+	seen := map[string]bool{}
+	for _, namespace := range i.namespaces {
+		for _, value := range i.namespaceToIndexer[namespace].ListIndexFuncValues(indexName) {
+			if !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func (i *multiNamespaceIndexer) ByIndex(indexName, indexKey string) ([]interface{}, error) {
+	items := []interface{}{}
+	for _, namespace := range i.namespaces {
+		namespaceItems, err := i.namespaceToIndexer[namespace].ByIndex(indexName, indexKey)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, namespaceItems...)
+	}
+	return items, nil
+}
+
+// GetIndexers returns the indexers of the namespaces, which are the same
+// in all of them as they are added to all the informers
+func (i *multiNamespaceIndexer) GetIndexers() toolscache.Indexers {
+	for _, namespace := range i.namespaces {
+		return i.namespaceToIndexer[namespace].GetIndexers()
+	}
+	return toolscache.Indexers{}
+}
+
+func (i *multiNamespaceIndexer) AddIndexers(indexers toolscache.Indexers) error {
+	for _, namespace := range i.namespaces {
+		if err := i.namespaceToIndexer[namespace].AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
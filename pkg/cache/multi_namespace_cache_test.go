@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeCache reads objects from a fake client
+type fakeCache struct {
+	*informertest.FakeInformers
+	reader client.Reader
+}
+
+func newFakeCache(objs ...runtime.Object) cache.Cache {
+	return &fakeCache{FakeInformers: &informertest.FakeInformers{}, reader: fake.NewFakeClient(objs...)}
+}
+
+func (c *fakeCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return c.reader.Get(ctx, key, obj)
+}
+
+func (c *fakeCache) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	return c.reader.List(ctx, opts, list)
+}
+
+func configMap(namespace, name string) *v1.ConfigMap {
+	return &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+func TestWatchNamespaces(t *testing.T) {
+	cases := []struct {
+		watchNamespace string
+		expected       []string
+	}{
+		{"", []string{}},
+		{"team-a", []string{"team-a"}},
+		{"team-b, team-a,,team-b", []string{"team-a", "team-b"}},
+	}
+	for _, tc := range cases {
+		namespaces := WatchNamespaces(tc.watchNamespace)
+		if !reflect.DeepEqual(namespaces, tc.expected) {
+			t.Errorf("WATCH_NAMESPACE %q: expected %v, got %v", tc.watchNamespace, tc.expected, namespaces)
+		}
+	}
+}
+
+func TestMultiNamespaceCacheReads(t *testing.T) {
+	c := newMultiNamespaceCache(map[string]cache.Cache{
+		"team-a": newFakeCache(configMap("team-a", "config")),
+		"team-b": newFakeCache(configMap("team-b", "config"), configMap("team-b", "other")),
+	})
+
+	found := &v1.ConfigMap{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: "team-b", Name: "other"}, found)
+	if err != nil {
+		t.Fatalf("unexpected error getting object: %v", err)
+	}
+
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: "team-c", Name: "config"}, found)
+	if _, ok := err.(*NamespaceNotWatchedError); !ok {
+		t.Errorf("expected a NamespaceNotWatchedError getting an object of a not watched namespace, got %v", err)
+	}
+
+	list := &v1.ConfigMapList{}
+	err = c.List(context.TODO(), &client.ListOptions{Namespace: "team-a"}, list)
+	if err != nil {
+		t.Fatalf("unexpected error listing namespace: %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Errorf("expected 1 object in namespace team-a, got %d", len(list.Items))
+	}
+
+	list = &v1.ConfigMapList{}
+	err = c.List(context.TODO(), &client.ListOptions{}, list)
+	if err != nil {
+		t.Fatalf("unexpected error listing all namespaces: %v", err)
+	}
+	keys := []string{}
+	for _, item := range list.Items {
+		keys = append(keys, item.Namespace+"/"+item.Name)
+	}
+	sort.Strings(keys)
+	expected := []string{"team-a/config", "team-b/config", "team-b/other"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v listing all namespaces, got %v", expected, keys)
+	}
+}
+
+// newStoreInformer returns an informer that is not run, whose store holds
+// the given objects
+func newStoreInformer(t *testing.T, objs ...runtime.Object) toolscache.SharedIndexInformer {
+	indexers := toolscache.Indexers{toolscache.NamespaceIndex: toolscache.MetaNamespaceIndexFunc}
+	informer := toolscache.NewSharedIndexInformer(&toolscache.ListWatch{}, &v1.ConfigMap{}, 0, indexers)
+	for _, obj := range objs {
+		if err := informer.GetStore().Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return informer
+}
+
+func TestMultiNamespaceInformerStore(t *testing.T) {
+	informer := newMultiNamespaceInformer(map[string]toolscache.SharedIndexInformer{
+		"team-a": newStoreInformer(t, configMap("team-a", "config")),
+		"team-b": newStoreInformer(t, configMap("team-b", "config"), configMap("team-b", "other")),
+	})
+
+	store := informer.GetStore()
+	keys := store.ListKeys()
+	sort.Strings(keys)
+	expected := []string{"team-a/config", "team-b/config", "team-b/other"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected keys %v, got %v", expected, keys)
+	}
+	if items := store.List(); len(items) != 3 {
+		t.Errorf("expected 3 objects, got %d", len(items))
+	}
+
+	item, exists, err := store.Get(configMap("team-b", "other"))
+	if err != nil || !exists {
+		t.Fatalf("expected object team-b/other, got exists %v, error %v", exists, err)
+	}
+	if item.(*v1.ConfigMap).Namespace != "team-b" {
+		t.Errorf("expected object of namespace team-b, got %v", item)
+	}
+	if _, exists, err = store.GetByKey("team-a/other"); err != nil || exists {
+		t.Errorf("expected missing object team-a/other, got exists %v, error %v", exists, err)
+	}
+	if _, _, err = store.GetByKey("team-c/config"); err == nil {
+		t.Error("expected error getting an object of a not watched namespace")
+	}
+	if err = store.Add(configMap("team-a", "new")); err == nil {
+		t.Error("expected error adding an object to the store of several namespaces")
+	}
+
+	indexer := informer.GetIndexer()
+	items, err := indexer.ByIndex(toolscache.NamespaceIndex, "team-b")
+	if err != nil {
+		t.Fatalf("unexpected error reading index: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected 2 objects in namespace team-b, got %d", len(items))
+	}
+	values := indexer.ListIndexFuncValues(toolscache.NamespaceIndex)
+	sort.Strings(values)
+	if expected := []string{"team-a", "team-b"}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected index values %v, got %v", expected, values)
+	}
+	if _, ok := indexer.GetIndexers()[toolscache.NamespaceIndex]; !ok {
+		t.Errorf("expected the namespace indexer, got %v", indexer.GetIndexers())
+	}
+	if informer.GetController() == nil || informer.HasSynced() {
+		t.Error("expected a controller of informers that have not synced")
+	}
+}
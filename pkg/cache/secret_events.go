@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// NewSecretEventSource returns a source of the events of the Secrets in the
+// given namespaces, or in all the namespaces when the list is empty. Unlike
+// an informer, it does not keep the Secrets in memory, it only sends their
+// events. The watches are run by the manager
+func NewSecretEventSource(mgr manager.Manager, namespaces []string) (source.Source, error) {
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	events := make(chan event.GenericEvent)
+	for _, namespace := range namespaces {
+		listWatch := toolscache.NewListWatchFromClient(clientset.CoreV1().RESTClient(), "secrets", namespace, fields.Everything())
+		err = mgr.Add(manager.RunnableFunc(func(stopCh <-chan struct{}) error {
+			store := &eventStore{events: events, stop: stopCh}
+			toolscache.NewReflector(listWatch, &v1.Secret{}, store, 0).Run(stopCh)
+			return nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &source.Channel{Source: events}, nil
+}
+
+// eventStore is a store sending an event for every object it receives,
+// instead of storing it
+type eventStore struct {
+	events chan<- event.GenericEvent
+	stop   <-chan struct{}
+}
+
+var _ toolscache.Store = &eventStore{}
+
+func (s *eventStore) send(obj interface{}) error {
+	if deleted, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = deleted.Obj
+	}
+	meta, err := apimeta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return fmt.Errorf("%T is not a runtime.Object", obj)
+	}
+
+	select {
+	case s.events <- event.GenericEvent{Meta: meta, Object: runtimeObj}:
+	case <-s.stop:
+	}
+	return nil
+}
+
+func (s *eventStore) Add(obj interface{}) error {
+	return s.send(obj)
+}
+
+func (s *eventStore) Update(obj interface{}) error {
+	return s.send(obj)
+}
+
+func (s *eventStore) Delete(obj interface{}) error {
+	return s.send(obj)
+}
+
+// Replace sends an event for every listed object, as the objects changed
+// while not watching are unknown
+func (s *eventStore) Replace(list []interface{}, resourceVersion string) error {
+	for _, obj := range list {
+		if err := s.send(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *eventStore) List() []interface{} {
+	return nil
+}
+
+func (s *eventStore) ListKeys() []string {
+	return nil
+}
+
+func (s *eventStore) Get(obj interface{}) (interface{}, bool, error) {
+	return nil, false, nil
+}
+
+func (s *eventStore) GetByKey(key string) (interface{}, bool, error) {
+	return nil, false, nil
+}
+
+func (s *eventStore) Resync() error {
+	return nil
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestEventStore(t *testing.T) {
+	events := make(chan event.GenericEvent, 10)
+	store := &eventStore{events: events, stop: make(chan struct{})}
+
+	secret := func(name string) *v1.Secret {
+		return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "operator-test"}}
+	}
+	if err := store.Replace([]interface{}{secret("listed"), secret("other")}, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(secret("added")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Update(secret("updated")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(toolscache.DeletedFinalStateUnknown{Key: "operator-test/deleted", Obj: secret("deleted")}); err != nil {
+		t.Fatal(err)
+	}
+	close(events)
+
+	names := []string{}
+	for evt := range events {
+		if evt.Meta.GetNamespace() != "operator-test" {
+			t.Errorf("unexpected namespace %s", evt.Meta.GetNamespace())
+		}
+		names = append(names, evt.Meta.GetName())
+	}
+	expected := []string{"listed", "other", "added", "updated", "deleted"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, names)
+	}
+	for idx := range expected {
+		if names[idx] != expected[idx] {
+			t.Errorf("expected events %v, got %v", expected, names)
+		}
+	}
+
+	// Nothing is stored
+	if len(store.List()) != 0 || len(store.ListKeys()) != 0 {
+		t.Error("expected an empty store")
+	}
+}
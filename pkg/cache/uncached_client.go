@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// UncachedClientBuilder returns a manager client builder that reads the
+// given object and list types from the API server instead of the cache.
+// The cache creates an informer for each type read through it, so the
+// cache never holds the uncached types, like the Secrets of all the
+// namespaces
+func UncachedClientBuilder(uncachedObjects ...runtime.Object) manager.NewClientFunc {
+	return func(c cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
+		apiClient, err := client.New(config, options)
+		if err != nil {
+			return nil, err
+		}
+		return &client.DelegatingClient{
+			Reader:       newUncachedReader(c, apiClient, uncachedObjects...),
+			Writer:       apiClient,
+			StatusClient: apiClient,
+		}, nil
+	}
+}
+
+// uncachedReader reads the uncached types from the API server and the
+// other types from the cache
+type uncachedReader struct {
+	cacheReader   client.Reader
+	apiReader     client.Reader
+	uncachedTypes map[reflect.Type]bool
+}
+
+var _ client.Reader = &uncachedReader{}
+
+func newUncachedReader(cacheReader, apiReader client.Reader, uncachedObjects ...runtime.Object) *uncachedReader {
+	uncachedTypes := map[reflect.Type]bool{}
+	for _, obj := range uncachedObjects {
+		uncachedTypes[reflect.TypeOf(obj)] = true
+	}
+	return &uncachedReader{cacheReader: cacheReader, apiReader: apiReader, uncachedTypes: uncachedTypes}
+}
+
+func (r *uncachedReader) reader(obj runtime.Object) client.Reader {
+	if r.uncachedTypes[reflect.TypeOf(obj)] {
+		return r.apiReader
+	}
+	return r.cacheReader
+}
+
+// Get implements client.Reader
+func (r *uncachedReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return r.reader(obj).Get(ctx, key, obj)
+}
+
+// List implements client.Reader
+func (r *uncachedReader) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	return r.reader(list).List(ctx, opts, list)
+}
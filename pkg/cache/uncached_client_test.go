@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUncachedReader(t *testing.T) {
+	secret := func(name string) *v1.Secret {
+		return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: name}}
+	}
+	cacheReader := fake.NewFakeClient(secret("cached"), configMap("team-a", "config"))
+	apiReader := fake.NewFakeClient(secret("uncached"))
+	reader := newUncachedReader(cacheReader, apiReader, &v1.Secret{}, &v1.SecretList{})
+
+	err := reader.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "uncached"}, &v1.Secret{})
+	if err != nil {
+		t.Errorf("expected secret read from the API, got %v", err)
+	}
+	err = reader.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "config"}, &v1.ConfigMap{})
+	if err != nil {
+		t.Errorf("expected config map read from the cache, got %v", err)
+	}
+
+	list := &v1.SecretList{}
+	err = reader.List(context.TODO(), &client.ListOptions{Namespace: "team-a"}, list)
+	if err != nil {
+		t.Fatalf("unexpected error listing secrets: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "uncached" {
+		t.Errorf("expected secrets listed from the API, got %v", list.Items)
+	}
+}
@@ -4,18 +4,28 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/go-logr/logr"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
 	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	operatorcache "github.com/3scale/3scale-operator/pkg/cache"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/metrics"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -28,6 +38,28 @@ import (
 
 var log = logf.Log.WithName("controller_apimanager")
 
+// ResyncPeriod is the interval between reconciliations of a ready
+// APIManager, so the changes missed by the watches are eventually applied
+const ResyncPeriod = 10 * time.Minute
+
+// Reasons of the events emitted on the APIManager resource
+const (
+	EventReasonCreated           = "Created"
+	EventReasonUpdated           = "Updated"
+	EventReasonCreateFailed      = "CreateFailed"
+	EventReasonUpdateFailed      = "UpdateFailed"
+	EventReasonInvalidSpec       = "InvalidSpec"
+	EventReasonObjectsFailed     = "ObjectsGenerationFailed"
+	EventReasonCredentialRotated = "CredentialRotated"
+	EventReasonRotationFailed    = "CredentialRotationFailed"
+	EventReasonWeakCredentials   = "WeakCredentials"
+	EventReasonExpansionSkipped  = "ExpansionSkipped"
+	EventReasonUpgradeRejected   = "UpgradeRejected"
+	// EventReasonInvalidReleaseCatalog is emitted when the release catalog
+	// ConfigMap cannot be reloaded
+	EventReasonInvalidReleaseCatalog = "InvalidReleaseCatalog"
+)
+
 /**
 * USER ACTION REQUIRED: This is a scaffold file intended for the user to modify with their own Controller
 * business logic.  Delete these comments after modifying this file.*
@@ -57,13 +89,31 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &ReconcileAPIManager{client: mgr.GetClient(), apiClientReader: apiClientReader, scheme: mgr.GetScheme(), reqLogger: log}, nil
+	watchNamespace, err := k8sutil.GetWatchNamespace()
+	if err != nil {
+		return nil, err
+	}
+	watchNamespaces := operatorcache.WatchNamespaces(watchNamespace)
+	releaseCatalog, err := ReleaseCatalogConfigMap(watchNamespaces)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileAPIManager{
+		client:             mgr.GetClient(),
+		apiClientReader:    apiClientReader,
+		scheme:             mgr.GetScheme(),
+		reqLogger:          log,
+		recorder:           mgr.GetRecorder("apimanager-controller"),
+		portaClientFactory: helper.PortaClientFromURLString,
+		watchNamespaces:    watchNamespaces,
+		releaseCatalog:     releaseCatalog,
+	}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("apimanager-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("apimanager-controller", mgr, controller.Options{Reconciler: metrics.NewInstrumentedReconciler("apimanager-controller", r)})
 	if err != nil {
 		return err
 	}
@@ -74,9 +124,99 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to the Secrets read by the APIManager, either
+	// provided by the user or generated by the operator. The Secret events
+	// are mapped to the APIManagers referencing them by name, with an index
+	// of the APIManager cache. The Secrets are not kept in memory, so the
+	// operator does not cache all the Secrets of the watched namespaces
+	err = mgr.GetFieldIndexer().IndexField(&appsv1alpha1.APIManager{}, apiManagerSecretNamesField, func(obj runtime.Object) []string {
+		return apiManagerSecretNames(obj.(*appsv1alpha1.APIManager))
+	})
+	if err != nil {
+		return err
+	}
+	watchNamespace, err := k8sutil.GetWatchNamespace()
+	if err != nil {
+		return err
+	}
+	secretSource, err := operatorcache.NewSecretEventSource(mgr, operatorcache.WatchNamespaces(watchNamespace))
+	if err != nil {
+		return err
+	}
+	err = c.Watch(secretSource, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			return apiManagerSecretRequests(mgr.GetClient(), obj.Meta.GetNamespace(), obj.Meta.GetName())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// apiManagerSecretNamesField indexes the APIManagers by the names of the Secrets they read
+const apiManagerSecretNamesField = "apiManagerSecretNames"
+
+// apiManagerSecretRequests returns the requests for the APIManagers reading the given secret
+func apiManagerSecretRequests(c client.Client, namespace, secretName string) []reconcile.Request {
+	apimanagerList := &appsv1alpha1.APIManagerList{}
+	err := c.List(context.TODO(), client.MatchingField(apiManagerSecretNamesField, secretName).InNamespace(namespace), apimanagerList)
+	if err != nil {
+		log.Error(err, "Error listing APIManagers", "Namespace", namespace)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for idx := range apimanagerList.Items {
+		apimanager := &apimanagerList.Items[idx]
+		// Readers without the index, like the fake client, ignore the
+		// field selector
+		for _, name := range apiManagerSecretNames(apimanager) {
+			if name == secretName {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: apimanager.Name, Namespace: apimanager.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// apiManagerSecretNames returns the names of the Secrets read by the options providers of the APIManager
+func apiManagerSecretNames(cr *appsv1alpha1.APIManager) []string {
+	names := []string{
+		component.BackendSecretBackendRedisSecretName,
+		component.BackendSecretInternalApiSecretName,
+		component.BackendSecretBackendListenerSecretName,
+		component.SystemSecretSystemDatabaseSecretName,
+		component.SystemSecretSystemMemcachedSecretName,
+		component.SystemSecretSystemRecaptchaSecretName,
+		component.SystemSecretSystemEventsHookSecretName,
+		component.SystemSecretSystemRedisSecretName,
+		component.SystemSecretSystemAppSecretName,
+		component.SystemSecretSystemSeedSecretName,
+		component.SystemSecretSystemMasterApicastSecretName,
+		component.ZyncSecretName,
+	}
+	if cr.Spec.System != nil && cr.Spec.System.FileStorageSpec != nil && cr.Spec.System.FileStorageSpec.S3 != nil {
+		names = append(names, cr.Spec.System.FileStorageSpec.S3.AWSCredentials.Name)
+		if cr.Spec.System.FileStorageSpec.S3.CACertificateSecret != nil {
+			names = append(names, cr.Spec.System.FileStorageSpec.S3.CACertificateSecret.Name)
+		}
+	}
+	for _, routeSpec := range operator.APIManagerRouteSpecs(&cr.Spec) {
+		if routeSpec.TLS == nil {
+			continue
+		}
+		if routeSpec.TLS.CertificateSecret != nil {
+			names = append(names, routeSpec.TLS.CertificateSecret.Name)
+		}
+	}
+	return names
+}
+
 // blank assignment to verify that ReconcileAPIManager implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcileAPIManager{}
 
@@ -88,6 +228,17 @@ type ReconcileAPIManager struct {
 	scheme          *runtime.Scheme
 	reqLogger       logr.Logger
 	apiClientReader client.Reader
+	recorder        record.EventRecorder
+	// portaClientFactory builds the admin portal clients issuing
+	// rotated access tokens
+	portaClientFactory porta.ClientFactory
+	// watchNamespaces are the namespaces watched by the operator,
+	// empty when watching all namespaces
+	watchNamespaces []string
+	// releaseCatalog is the release catalog ConfigMap, if any, and
+	// releaseCatalogData the catalog last read from it
+	releaseCatalog     *types.NamespacedName
+	releaseCatalogData string
 }
 
 // Reconcile reads that state of the cluster for a APIManager object and makes changes based on the state read
@@ -120,10 +271,13 @@ func (r *ReconcileAPIManager) Reconcile(request reconcile.Request) (reconcile.Re
 	}
 	r.reqLogger.Info("Successfully retreived APIManager resource")
 
+	r.reconcileReleaseCatalog(instance)
+
 	r.reqLogger.Info("Setting defaults for APIManager resource")
 	changed, err := instance.SetDefaults() // TODO check where to put this
 	if err != nil {
 		// Error setting defaults - Stop reconciliation
+		r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonInvalidSpec, "Error setting defaults: %v", err)
 		return reconcile.Result{}, nil
 	}
 	if changed {
@@ -131,75 +285,183 @@ func (r *ReconcileAPIManager) Reconcile(request reconcile.Request) (reconcile.Re
 		err = r.client.Update(context.TODO(), instance)
 		if err != nil {
 			r.reqLogger.Error(err, "APIManager Resource cannot be updated. Requeuing request...")
+			r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonUpdateFailed, "Error updating defaults: %v", err)
 			return reconcile.Result{}, err
 		}
 		r.reqLogger.Info("Successfully updated defaults for APIManager resource")
 		return reconcile.Result{}, nil
 	}
 
+	deployedVersion, err := r.deployedProductVersion(instance)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to get the deployed product version. Requeuing request...")
+		return reconcile.Result{}, err
+	}
+	if deployedVersion != "" && !product.CanUpgrade(deployedVersion, instance.Spec.ProductVersion) {
+		// Stop reconciliation until the product version is changed
+		r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonUpgradeRejected, "Product version '%s' cannot be upgraded to '%s'", deployedVersion, instance.Spec.ProductVersion)
+		return reconcile.Result{}, nil
+	}
+
 	objs, err := r.apiManagerObjects(instance)
 	if err != nil {
 		r.reqLogger.Error(err, "Error creating APIManager objects. Requeuing request...")
+		r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonObjectsFailed, "Error generating APIManager objects: %v", err)
 		return reconcile.Result{}, err
 	}
 
 	// Set APIManager instance as the owner and controller
+	err = r.setAPIManagerOwnership(instance, objs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Create APIManager Objects. DeploymentConfigs are reconciled last, in
+	// rollout phases, so their config hash includes the Secrets and ConfigMaps
+	// reconciled before
+	dcPhases := rolloutPhases(objs)
 	for idx := range objs {
-		obj := objs[idx].Object
-		objectMeta := obj.(metav1.Object)
-		objectMeta.SetNamespace(instance.Namespace)
-		err = controllerutil.SetControllerReference(instance, objectMeta, r.scheme)
+		if _, ok := objs[idx].Object.(*appsv1.DeploymentConfig); ok {
+			continue
+		}
+		err = r.reconcileObject(instance, objs[idx].Object)
 		if err != nil {
-			r.reqLogger.Error(err, "Error setting OwnerReference on object. Requeuing request...",
-				"Kind", obj.GetObjectKind(),
-				"Namespace", objectMeta.GetNamespace(),
-				"Name", objectMeta.GetName(),
-			)
 			return reconcile.Result{}, err
 		}
 	}
 
-	// Create APIManager Objects
-	for idx := range objs {
-		obj := objs[idx].Object
-		objCopy := obj.DeepCopyObject() // We create a copy because the r.client.Create method removes TypeMeta for some reason
-		objectMeta := objCopy.(metav1.Object)
-		objectInfo := fmt.Sprintf("%s/%s", objCopy.GetObjectKind().GroupVersionKind().Kind, objectMeta.GetName())
+	phase, err := r.reconcileRollout(instance, dcPhases)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 
-		newobj := reflect.New(reflect.TypeOf(obj).Elem()).Interface()
-		found := newobj.(runtime.Object)
-		err = r.client.Get(context.TODO(), types.NamespacedName{Name: objectMeta.GetName(), Namespace: objectMeta.GetNamespace()}, found)
-		if err != nil {
-			if errors.IsNotFound(err) {
-				// TODO for some reason r.client.Create modifies the original object and removes the TypeMeta. Figure why is this???
-				createErr := r.client.Create(context.TODO(), obj)
-				if createErr != nil {
-					r.reqLogger.Error(createErr, fmt.Sprintf("Error creating object %s. Requeuing request...", objectInfo))
-					return reconcile.Result{}, createErr
+	ready, err := r.reconcileStatus(instance, objs, phase)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to update APIManager status. Requeuing request...")
+		return reconcile.Result{}, err
+	}
+	if !ready {
+		r.reqLogger.Info("Finished Current reconcile request successfully. APIManager not ready yet, requeuing request")
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	err = r.reconcileWeakCredentials(instance)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to check credentials strength. Requeuing request...")
+		return reconcile.Result{}, err
+	}
+
+	rotated, err := r.reconcileCredentialRotation(instance, objs)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to rotate credentials. Requeuing request...")
+		r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonRotationFailed, "Error rotating credentials: %v", err)
+		return reconcile.Result{}, err
+	}
+	if rotated {
+		r.reqLogger.Info("Finished Current reconcile request successfully. Credential rotation in progress, requeuing request")
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	r.reqLogger.Info("Finished Current reconcile request successfully. Requeuing request after the resync period")
+	return reconcile.Result{RequeueAfter: ResyncPeriod}, nil
+}
+
+// reconcileObject creates the object when it does not exist yet. Otherwise
+// it reconciles the data of Secrets and ConfigMaps, the tags of ImageStreams
+// and the config hash of DeploymentConfigs
+func (r *ReconcileAPIManager) reconcileObject(instance *appsv1alpha1.APIManager, obj runtime.Object) error {
+	objCopy := obj.DeepCopyObject() // We create a copy because the r.client.Create method removes TypeMeta for some reason
+	objectMeta := objCopy.(metav1.Object)
+	objectKind := objCopy.GetObjectKind().GroupVersionKind().Kind
+	objectInfo := fmt.Sprintf("%s/%s", objectKind, objectMeta.GetName())
+
+	newobj := reflect.New(reflect.TypeOf(obj).Elem()).Interface()
+	found := newobj.(runtime.Object)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: objectMeta.GetName(), Namespace: objectMeta.GetNamespace()}, found)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if dc, ok := obj.(*appsv1.DeploymentConfig); ok {
+				if _, hashErr := r.setConfigHash(dc); hashErr != nil {
+					r.reqLogger.Error(hashErr, fmt.Sprintf("Error computing config hash of %s. Requeuing request...", objectInfo))
+					return hashErr
 				}
-				r.reqLogger.Info(fmt.Sprintf("Created object %s", objectInfo))
-			} else {
-				r.reqLogger.Error(err, fmt.Sprintf("Failed to get %s.  Requeuing request...", objectInfo))
-				return reconcile.Result{}, err
 			}
-		} else {
-			r.reqLogger.Info(fmt.Sprintf("Object %s already exists", objectInfo))
-			if secret, ok := objCopy.(*v1.Secret); ok {
-				r.reqLogger.Info(fmt.Sprintf("Object %s is a secret. Reconciling it...", objectInfo))
-				// We get copy to avoid modifying possibly obtained object
-				// from the cache
-				foundSecret := found.(*v1.Secret)
-				r.reconcileSecret(secret, foundSecret, instance)
-				if err != nil {
-					r.reqLogger.Error(err, fmt.Sprintf("Failed to update secret secret/%s. Requeuing request...", secret.Name))
-					return reconcile.Result{}, err
-				}
+			// TODO for some reason r.client.Create modifies the original object and removes the TypeMeta. Figure why is this???
+			createErr := r.client.Create(context.TODO(), obj)
+			if createErr != nil {
+				r.reqLogger.Error(createErr, fmt.Sprintf("Error creating object %s. Requeuing request...", objectInfo))
+				r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonCreateFailed, "Error creating %s %s: %v", objectKind, objectMeta.GetName(), createErr)
+				return createErr
 			}
+			r.reqLogger.Info(fmt.Sprintf("Created object %s", objectInfo))
+			r.recorder.Eventf(instance, v1.EventTypeNormal, EventReasonCreated, "Created %s %s", objectKind, objectMeta.GetName())
+			return nil
 		}
+		r.reqLogger.Error(err, fmt.Sprintf("Failed to get %s.  Requeuing request...", objectInfo))
+		return err
 	}
 
-	r.reqLogger.Info("Finished Current reconcile request successfully. Skipping requeue of the request")
-	return reconcile.Result{}, nil
+	r.reqLogger.Info(fmt.Sprintf("Object %s already exists", objectInfo))
+	if secret, ok := objCopy.(*v1.Secret); ok {
+		r.reqLogger.Info(fmt.Sprintf("Object %s is a secret. Reconciling it...", objectInfo))
+		// We get copy to avoid modifying possibly obtained object
+		// from the cache
+		foundSecret := found.(*v1.Secret)
+		err = r.reconcileSecret(secret, foundSecret, instance)
+		if err != nil {
+			r.reqLogger.Error(err, fmt.Sprintf("Failed to update secret secret/%s. Requeuing request...", secret.Name))
+			r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonUpdateFailed, "Error updating Secret %s: %v", secret.Name, err)
+			return err
+		}
+	}
+	if imageStream, ok := objCopy.(*imagev1.ImageStream); ok {
+		err = r.reconcileImageStream(instance, imageStream, found.(*imagev1.ImageStream))
+		if err != nil {
+			r.reqLogger.Error(err, fmt.Sprintf("Failed to update %s. Requeuing request...", objectInfo))
+			r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonUpdateFailed, "Error updating ImageStream %s: %v", imageStream.Name, err)
+			return err
+		}
+	}
+	if configMap, ok := objCopy.(*v1.ConfigMap); ok {
+		err = r.reconcileConfigMap(instance, configMap, found.(*v1.ConfigMap))
+		if err != nil {
+			r.reqLogger.Error(err, fmt.Sprintf("Failed to update %s. Requeuing request...", objectInfo))
+			r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonUpdateFailed, "Error updating ConfigMap %s: %v", configMap.Name, err)
+			return err
+		}
+	}
+	if pvc, ok := objCopy.(*v1.PersistentVolumeClaim); ok {
+		err = r.reconcilePersistentVolumeClaim(instance, pvc, found.(*v1.PersistentVolumeClaim))
+		if err != nil {
+			r.reqLogger.Error(err, fmt.Sprintf("Failed to expand %s. Requeuing request...", objectInfo))
+			r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonUpdateFailed, "Error expanding PersistentVolumeClaim %s: %v", pvc.Name, err)
+			return err
+		}
+	}
+	if route, ok := objCopy.(*routev1.Route); ok {
+		err = r.reconcileRoute(instance, route, found.(*routev1.Route))
+		if err != nil {
+			r.reqLogger.Error(err, fmt.Sprintf("Failed to update %s. Requeuing request...", objectInfo))
+			r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonUpdateFailed, "Error updating Route %s: %v", route.Name, err)
+			return err
+		}
+	}
+	if _, ok := objCopy.(*appsv1.DeploymentConfig); ok {
+		if credentialRotationDefersRollout(instance, objectMeta.GetName()) {
+			r.reqLogger.Info(fmt.Sprintf("Deferring the rollout of %s until the previous consumers of the rotated credential are rolled out", objectInfo))
+			return nil
+		}
+		rolledOut, err := r.reconcileConfigHash(objectMeta.GetNamespace(), objectMeta.GetName())
+		if err != nil {
+			r.reqLogger.Error(err, fmt.Sprintf("Failed to update config hash of %s. Requeuing request...", objectInfo))
+			r.recorder.Eventf(instance, v1.EventTypeWarning, EventReasonUpdateFailed, "Error updating config hash of DeploymentConfig %s: %v", objectMeta.GetName(), err)
+			return err
+		}
+		if rolledOut {
+			r.recorder.Eventf(instance, v1.EventTypeNormal, EventReasonUpdated, "Rolled out DeploymentConfig %s after configuration change", objectMeta.GetName())
+		}
+	}
+	return nil
 }
 
 func (r *ReconcileAPIManager) reconcileSecret(desired, current *v1.Secret, cr *appsv1alpha1.APIManager) error {
@@ -212,6 +474,7 @@ func (r *ReconcileAPIManager) reconcileSecret(desired, current *v1.Secret, cr *a
 	// obtained from the Kubernetes API and we need to compare the secret
 	// data
 	desiredCopy.Data = secretStringDataToData(desiredCopy.StringData)
+	keepRotationRecord(currentCopy, desiredCopy)
 	if secretsEqual(currentCopy, desiredCopy) {
 		r.reqLogger.Info(fmt.Sprintf("Secret %s is already reconciled. Update skipped", currentCopy.Name))
 		return nil
@@ -229,6 +492,27 @@ func (r *ReconcileAPIManager) reconcileSecret(desired, current *v1.Secret, cr *a
 	if err = r.client.Update(context.TODO(), currentCopy); err != nil {
 		return err
 	}
+	r.recorder.Eventf(cr, v1.EventTypeNormal, EventReasonUpdated, "Updated Secret %s", currentCopy.Name)
+	return nil
+}
+
+// setAPIManagerOwnership sets the APIManager namespace on the objects and
+// the APIManager as their owner and controller
+func (r *ReconcileAPIManager) setAPIManagerOwnership(cr *appsv1alpha1.APIManager, objs []runtime.RawExtension) error {
+	for idx := range objs {
+		obj := objs[idx].Object
+		objectMeta := obj.(metav1.Object)
+		objectMeta.SetNamespace(cr.Namespace)
+		err := controllerutil.SetControllerReference(cr, objectMeta, r.scheme)
+		if err != nil {
+			r.reqLogger.Error(err, "Error setting OwnerReference on object. Requeuing request...",
+				"Kind", obj.GetObjectKind(),
+				"Namespace", objectMeta.GetNamespace(),
+				"Name", objectMeta.GetName(),
+			)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -350,6 +634,44 @@ func (r *ReconcileAPIManager) postProcessAPIManagerObjectsGroup(cr *appsv1alpha1
 		objects = h.PostProcessObjects(objects)
 	}
 
+	if cr.Spec.Redis != nil && (cr.Spec.Redis.Sentinel != nil || cr.Spec.Redis.ExternalSentinel != nil) {
+		optsProvider := operator.OperatorRedisSentinelOptionsProvider{APIManagerSpec: &cr.Spec}
+		opts, err := optsProvider.GetRedisSentinelOptions()
+		if err != nil {
+			return nil, err
+		}
+		rs := component.RedisSentinel{Options: opts}
+		objects = rs.PostProcessObjects(objects)
+	}
+
+	if cr.Spec.Routes != nil {
+		optsProvider := operator.OperatorRoutesOptionsProvider{APIManagerSpec: &cr.Spec, Namespace: cr.Namespace, Client: r.client}
+		opts, err := optsProvider.GetRoutesOptions()
+		if err != nil {
+			return nil, err
+		}
+		routes := component.Routes{Options: opts}
+		objects = routes.PostProcessObjects(objects)
+	}
+
+	if cr.Spec.Registry != nil && len(cr.Spec.Registry.ImagePullSecrets) > 0 {
+		optsProvider := operator.OperatorImagePullSecretsOptionsProvider{APIManagerSpec: &cr.Spec}
+		opts, err := optsProvider.GetImagePullSecretsOptions()
+		if err != nil {
+			return nil, err
+		}
+		i := component.ImagePullSecrets{Options: opts}
+		objects = i.PostProcessObjects(objects)
+	}
+
+	storageOptsProvider := operator.OperatorStorageOptionsProvider{APIManagerSpec: &cr.Spec}
+	storageOpts, err := storageOptsProvider.GetStorageOptions()
+	if err != nil {
+		return nil, err
+	}
+	storage := component.Storage{Options: storageOpts}
+	objects = storage.PostProcessObjects(objects)
+
 	return objects, nil
 }
 
@@ -0,0 +1,68 @@
+package apimanager
+
+import (
+	"sort"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAPIManagerSecretRequests(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := appsv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "operator-test"
+	s3APIManager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3", Namespace: namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			System: &appsv1alpha1.SystemSpec{
+				FileStorageSpec: &appsv1alpha1.SystemFileStorageSpec{
+					S3: &appsv1alpha1.SystemS3Spec{
+						AWSCredentials: v1.LocalObjectReference{Name: "aws-auth"},
+					},
+				},
+			},
+		},
+	}
+	pvcAPIManager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc", Namespace: namespace},
+	}
+	otherAPIManager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other-namespace"},
+	}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, s3APIManager, pvcAPIManager, otherAPIManager)
+
+	cases := []struct {
+		secretName string
+		expected   []string
+	}{
+		{"system-seed", []string{"pvc", "s3"}},
+		{"aws-auth", []string{"s3"}},
+		{"unrelated", nil},
+	}
+	for _, tc := range cases {
+		requests := apiManagerSecretRequests(k8sClient, namespace, tc.secretName)
+		var names []string
+		for _, request := range requests {
+			if request.Namespace != namespace {
+				t.Errorf("secret %s: unexpected request namespace %s", tc.secretName, request.Namespace)
+			}
+			names = append(names, request.Name)
+		}
+		sort.Strings(names)
+		if len(names) != len(tc.expected) {
+			t.Fatalf("secret %s: expected requests %v, got %v", tc.secretName, tc.expected, names)
+		}
+		for idx := range names {
+			if names[idx] != tc.expected[idx] {
+				t.Errorf("secret %s: expected requests %v, got %v", tc.secretName, tc.expected, names)
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package apimanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ConfigHashAnnotation is set on the pod template of the DeploymentConfigs with the hash
+// of the Secrets and ConfigMaps they reference. Changing any of them changes the hash,
+// so the config change trigger of the DeploymentConfig rolls out new pods
+const ConfigHashAnnotation = "apps.3scale.net/config-hash"
+
+// podSpecConfigReferences returns the names of the Secrets and ConfigMaps referenced
+// by the env vars and volumes of the pod
+func podSpecConfigReferences(podSpec *v1.PodSpec) (secrets, configMaps []string) {
+	secretSet := map[string]bool{}
+	configMapSet := map[string]bool{}
+
+	containers := append([]v1.Container{}, podSpec.InitContainers...)
+	containers = append(containers, podSpec.Containers...)
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				secretSet[envFrom.SecretRef.Name] = true
+			}
+			if envFrom.ConfigMapRef != nil {
+				configMapSet[envFrom.ConfigMapRef.Name] = true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secretSet[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMapSet[env.ValueFrom.ConfigMapKeyRef.Name] = true
+			}
+		}
+	}
+
+	for _, volume := range podSpec.Volumes {
+		if volume.Secret != nil {
+			secretSet[volume.Secret.SecretName] = true
+		}
+		if volume.ConfigMap != nil {
+			configMapSet[volume.ConfigMap.Name] = true
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil {
+					secretSet[source.Secret.Name] = true
+				}
+				if source.ConfigMap != nil {
+					configMapSet[source.ConfigMap.Name] = true
+				}
+			}
+		}
+	}
+
+	return sortedKeys(secretSet), sortedKeys(configMapSet)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := []string{}
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// configHash returns the hash of the contents of the Secrets and ConfigMaps referenced by the pod.
+// They are read from the API server, so objects created in the current reconciliation are included.
+// Missing objects are part of the hash too, so the pods are rolled out once they are created
+func (r *ReconcileAPIManager) configHash(namespace string, podSpec *v1.PodSpec) (string, error) {
+	secrets, configMaps := podSpecConfigReferences(podSpec)
+	hash := sha256.New()
+
+	for _, name := range secrets {
+		secret := &v1.Secret{}
+		err := r.apiClientReader.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		fmt.Fprintf(hash, "secret/%s\n", name)
+		if err == nil {
+			writeDataHash(hash, secret.Data)
+		}
+	}
+
+	for _, name := range configMaps {
+		configMap := &v1.ConfigMap{}
+		err := r.apiClientReader.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, configMap)
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		fmt.Fprintf(hash, "configmap/%s\n", name)
+		if err == nil {
+			data := map[string][]byte{}
+			for key, value := range configMap.Data {
+				data[key] = []byte(value)
+			}
+			for key, value := range configMap.BinaryData {
+				data[key] = value
+			}
+			writeDataHash(hash, data)
+		}
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func writeDataHash(w io.Writer, data map[string][]byte) {
+	keys := []string{}
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s=%d:", key, len(data[key]))
+		w.Write(data[key])
+		w.Write([]byte("\n"))
+	}
+}
+
+// setConfigHash stamps the config hash on the DeploymentConfig pod template.
+// It returns whether the hash changed
+func (r *ReconcileAPIManager) setConfigHash(dc *appsv1.DeploymentConfig) (bool, error) {
+	if dc.Spec.Template == nil {
+		return false, nil
+	}
+
+	hash, err := r.configHash(dc.Namespace, &dc.Spec.Template.Spec)
+	if err != nil {
+		return false, err
+	}
+
+	if dc.Spec.Template.Annotations[ConfigHashAnnotation] == hash {
+		return false, nil
+	}
+	if dc.Spec.Template.Annotations == nil {
+		dc.Spec.Template.Annotations = map[string]string{}
+	}
+	dc.Spec.Template.Annotations[ConfigHashAnnotation] = hash
+	return true, nil
+}
+
+// reconcileConfigHash updates the config hash of the existing DeploymentConfig,
+// rolling it out when its Secrets or ConfigMaps changed. It returns whether it was rolled out
+func (r *ReconcileAPIManager) reconcileConfigHash(namespace, name string) (bool, error) {
+	dc := &appsv1.DeploymentConfig{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, dc)
+	if err != nil {
+		return false, err
+	}
+
+	dc = dc.DeepCopy()
+	changed, err := r.setConfigHash(dc)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	r.reqLogger.Info(fmt.Sprintf("Configuration of DeploymentConfig %s changed. Rolling it out", name))
+	return true, r.client.Update(context.TODO(), dc)
+}
@@ -0,0 +1,67 @@
+package apimanager
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestReconcileConfigHash(t *testing.T) {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{v1.AddToScheme, appsv1.AddToScheme} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	namespace := "operator-test"
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-seed", Namespace: namespace},
+		Data:       map[string][]byte{"MASTER_USER": []byte("master")},
+	}
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-environment", Namespace: namespace},
+		Data:       map[string]string{"RAILS_ENV": "production"},
+	}
+	dc := newTestDeploymentConfig("system-app", namespace, secretEnvVar("MASTER_USER", "system-seed", "MASTER_USER"))
+	dc.Spec.Template.Spec.Containers[0].EnvFrom = []v1.EnvFromSource{
+		{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "system-environment"}}},
+	}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, secret, configMap, dc)
+
+	r := &ReconcileAPIManager{client: k8sClient, apiClientReader: k8sClient, scheme: s, reqLogger: logf.Log}
+
+	rolledOut, err := r.reconcileConfigHash(namespace, "system-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rolledOut {
+		t.Fatal("config hash not set")
+	}
+
+	rolledOut, err = r.reconcileConfigHash(namespace, "system-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rolledOut {
+		t.Error("rolled out without configuration changes")
+	}
+
+	configMap.Data["RAILS_ENV"] = "development"
+	if err := k8sClient.Update(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	rolledOut, err = r.reconcileConfigHash(namespace, "system-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rolledOut {
+		t.Error("not rolled out after ConfigMap change")
+	}
+}
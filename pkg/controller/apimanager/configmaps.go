@@ -0,0 +1,39 @@
+package apimanager
+
+import (
+	"context"
+	"fmt"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// reconcileConfigMap adds the data keys generated by the operator that are missing
+// in the existing ConfigMap, so the keys added by newer versions, like the S3
+// settings of system-environment, reach installs created before them.
+// Existing keys are left as they are, as users edit ConfigMaps like smtp
+func (r *ReconcileAPIManager) reconcileConfigMap(cr *appsv1alpha1.APIManager, desired, current *v1.ConfigMap) error {
+	currentCopy := current.DeepCopy()
+	if currentCopy.Data == nil {
+		currentCopy.Data = map[string]string{}
+	}
+
+	changed := false
+	for key, value := range desired.Data {
+		if _, ok := currentCopy.Data[key]; !ok {
+			currentCopy.Data[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	r.reqLogger.Info(fmt.Sprintf("ConfigMap %s is not equal to the expected ConfigMap. Updating ...", current.Name))
+	err := r.client.Update(context.TODO(), currentCopy)
+	if err != nil {
+		return err
+	}
+	r.recorder.Eventf(cr, v1.EventTypeNormal, EventReasonUpdated, "Updated ConfigMap %s", current.Name)
+	return nil
+}
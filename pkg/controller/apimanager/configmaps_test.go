@@ -0,0 +1,191 @@
+package apimanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// TestReconcileConfigMapExistingInstall checks that an install created before the
+// S3-compatible endpoint settings gets the keys the system DeploymentConfigs now read
+func TestReconcileConfigMapExistingInstall(t *testing.T) {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{v1.AddToScheme, appsv1.AddToScheme, appsv1alpha1.SchemeBuilder.AddToScheme} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	namespace := "operator-test"
+	endpoint := "https://minio.example.com:9000"
+	cr := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				ProductVersion: product.ProductUpstream,
+				WildcardDomain: "example.com",
+			},
+			System: &appsv1alpha1.SystemSpec{
+				FileStorageSpec: &appsv1alpha1.SystemFileStorageSpec{
+					S3: &appsv1alpha1.SystemS3Spec{
+						AWSBucket:         "bucket",
+						AWSRegion:         "us-east-1",
+						AWSCredentials:    v1.LocalObjectReference{Name: "aws-auth"},
+						FileUploadStorage: "s3",
+						Endpoint:          &endpoint,
+					},
+				},
+			},
+		},
+	}
+	if _, err := cr.SetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	awsAuth := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws-auth", Namespace: namespace},
+		Data: map[string][]byte{
+			component.S3SecretAWSAccessKeyIdFieldName:     []byte("access-key"),
+			component.S3SecretAWSSecretAccessKeyFieldName: []byte("secret-key"),
+		},
+	}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, cr, awsAuth)
+
+	objs, err := RenderAPIManagerObjects(k8sClient, s, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var desired *v1.ConfigMap
+	var systemApp *appsv1.DeploymentConfig
+	for idx := range objs {
+		switch obj := objs[idx].Object.(type) {
+		case *v1.ConfigMap:
+			if obj.Name == "system-environment" {
+				desired = obj
+			}
+		case *appsv1.DeploymentConfig:
+			if obj.Name == "system-app" {
+				systemApp = obj
+			}
+		}
+	}
+	if desired == nil || systemApp == nil {
+		t.Fatal("system-environment ConfigMap or system-app DeploymentConfig not rendered")
+	}
+
+	// The ConfigMap created by a previous version, without the endpoint settings,
+	// and with a key added by the user
+	existing := desired.DeepCopy()
+	for _, key := range []string{"AWS_HOSTNAME", "AWS_PROTOCOL", "AWS_PATH_STYLE", "AWS_SERVER_SIDE_ENCRYPTION"} {
+		delete(existing.Data, key)
+	}
+	existing.Data["CUSTOM"] = "value"
+	if err := k8sClient.Create(context.TODO(), existing); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ReconcileAPIManager{client: k8sClient, apiClientReader: k8sClient, scheme: s, reqLogger: logf.Log, recorder: record.NewFakeRecorder(10)}
+	if err := r.reconcileObject(cr, desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current := &v1.ConfigMap{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "system-environment", Namespace: namespace}, current); err != nil {
+		t.Fatal(err)
+	}
+	if current.Data["AWS_HOSTNAME"] != "minio.example.com:9000" || current.Data["AWS_PROTOCOL"] != "https" {
+		t.Errorf("endpoint settings not reconciled: %v", current.Data)
+	}
+	if current.Data["CUSTOM"] != "value" {
+		t.Errorf("user key removed: %v", current.Data)
+	}
+
+	// Every required key read by system-app exists, so its pods can start
+	for _, container := range systemApp.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			ref := env.ValueFrom
+			if ref == nil || ref.ConfigMapKeyRef == nil || ref.ConfigMapKeyRef.Name != current.Name {
+				continue
+			}
+			if ref.ConfigMapKeyRef.Optional != nil && *ref.ConfigMapKeyRef.Optional {
+				continue
+			}
+			if _, ok := current.Data[ref.ConfigMapKeyRef.Key]; !ok {
+				t.Errorf("%s: key %s missing in ConfigMap %s", container.Name, ref.ConfigMapKeyRef.Key, current.Name)
+			}
+		}
+	}
+}
+
+// TestReconcileConfigMapKeepsUserValues checks that the values set by the user on
+// a ConfigMap generated by the operator, like the smtp settings, survive a reconcile
+func TestReconcileConfigMapKeepsUserValues(t *testing.T) {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{v1.AddToScheme, appsv1.AddToScheme, appsv1alpha1.SchemeBuilder.AddToScheme} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	namespace := "operator-test"
+	cr := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				ProductVersion: product.ProductUpstream,
+				WildcardDomain: "example.com",
+			},
+		},
+	}
+	if _, err := cr.SetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, cr)
+
+	objs, err := RenderAPIManagerObjects(k8sClient, s, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var desired *v1.ConfigMap
+	for idx := range objs {
+		if obj, ok := objs[idx].Object.(*v1.ConfigMap); ok && obj.Name == "smtp" {
+			desired = obj
+		}
+	}
+	if desired == nil {
+		t.Fatal("smtp ConfigMap not rendered")
+	}
+
+	existing := desired.DeepCopy()
+	existing.Data["address"] = "smtp.example.com"
+	delete(existing.Data, "port")
+	if err := k8sClient.Create(context.TODO(), existing); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ReconcileAPIManager{client: k8sClient, apiClientReader: k8sClient, scheme: s, reqLogger: logf.Log, recorder: record.NewFakeRecorder(10)}
+	if err := r.reconcileObject(cr, desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current := &v1.ConfigMap{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "smtp", Namespace: namespace}, current); err != nil {
+		t.Fatal(err)
+	}
+	if current.Data["address"] != "smtp.example.com" {
+		t.Errorf("user smtp address overwritten: %v", current.Data)
+	}
+	if _, ok := current.Data["port"]; !ok {
+		t.Errorf("missing smtp port key not added: %v", current.Data)
+	}
+}
@@ -0,0 +1,639 @@
+package apimanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	appsv1 "github.com/openshift/api/apps/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The rotation record stored in the annotations of the credential secret
+// together with the new value, until the rotation is recorded in the
+// APIManager status
+const (
+	// rotationCredentialAnnotation is the credential whose new value is stored
+	rotationCredentialAnnotation = "apps.3scale.net/rotation-credential"
+	// rotationPreviousTokenIDAnnotation is the ID of the previous access token
+	rotationPreviousTokenIDAnnotation = "apps.3scale.net/rotation-previous-token-id"
+	// rotationPreviousValueAnnotation is the SHA-256 of the previous access token,
+	// replaced in the Tenant and Binding secrets
+	rotationPreviousValueAnnotation = "apps.3scale.net/rotation-previous-value-sha256"
+)
+
+var rotationAnnotations = []string{rotationCredentialAnnotation, rotationPreviousTokenIDAnnotation, rotationPreviousValueAnnotation}
+
+// credentialSource is the secret field holding a rotatable credential
+type credentialSource struct {
+	secretName     string
+	key            string
+	credentialType appsv1alpha1.CredentialType
+	// issued credentials are access tokens issued by the admin portal,
+	// instead of generated from the credential policy
+	issued bool
+}
+
+var credentialSources = map[appsv1alpha1.CredentialName]credentialSource{
+	appsv1alpha1.MasterAccessTokenCredential:          {component.SystemSecretSystemSeedSecretName, component.SystemSecretSystemSeedMasterAccessTokenFieldName, appsv1alpha1.AccessTokenCredentialType, true},
+	appsv1alpha1.AdminAccessTokenCredential:           {component.SystemSecretSystemSeedSecretName, component.SystemSecretSystemSeedAdminAccessTokenFieldName, appsv1alpha1.AccessTokenCredentialType, true},
+	appsv1alpha1.BackendSharedSecretCredential:        {component.SystemSecretSystemEventsHookSecretName, component.SystemSecretSystemEventsHookPasswordFieldName, appsv1alpha1.SharedSecretCredentialType, false},
+	appsv1alpha1.BackendInternalAPIPasswordCredential: {component.BackendSecretInternalApiSecretName, component.BackendSecretInternalApiPasswordFieldName, appsv1alpha1.PasswordCredentialType, false},
+	appsv1alpha1.AppSecretKeyBaseCredential:           {component.SystemSecretSystemAppSecretName, component.SystemSecretSystemAppSecretKeyBaseFieldName, appsv1alpha1.SecretKeyBaseCredentialType, false},
+	appsv1alpha1.ZyncAuthenticationTokenCredential:    {component.ZyncSecretName, component.ZyncSecretAuthenticationTokenFieldName, appsv1alpha1.AccessTokenCredentialType, false},
+}
+
+// credentialRotationOrder is the order in which requested credentials are rotated
+var credentialRotationOrder = []appsv1alpha1.CredentialName{
+	appsv1alpha1.MasterAccessTokenCredential,
+	appsv1alpha1.AdminAccessTokenCredential,
+	appsv1alpha1.BackendSharedSecretCredential,
+	appsv1alpha1.BackendInternalAPIPasswordCredential,
+	appsv1alpha1.AppSecretKeyBaseCredential,
+	appsv1alpha1.ZyncAuthenticationTokenCredential,
+}
+
+// consumerRolloutOrder is the order in which the consumers of a rotated
+// credential are rolled out, by DeploymentConfig name prefix
+var consumerRolloutOrder = []string{"system", "backend", "zync", "apicast"}
+
+// accessTokenScopes are the scopes of the access tokens issued on rotation
+var accessTokenScopes = []string{"account_management", "stats"}
+
+// requestedCredentialRotations parses the RotateCredentialsAnnotation of the APIManager,
+// returning the requested credentials in rotation order
+func requestedCredentialRotations(cr *appsv1alpha1.APIManager) ([]appsv1alpha1.CredentialName, error) {
+	value := strings.TrimSpace(cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation])
+	if value == "" {
+		return nil, nil
+	}
+
+	requested := map[appsv1alpha1.CredentialName]bool{}
+	for _, entry := range strings.Split(value, ",") {
+		credential := appsv1alpha1.CredentialName(strings.TrimSpace(entry))
+		if _, ok := credentialSources[credential]; !ok {
+			return nil, fmt.Errorf("unknown credential '%s' in annotation %s", credential, appsv1alpha1.RotateCredentialsAnnotation)
+		}
+		requested[credential] = true
+	}
+
+	result := []appsv1alpha1.CredentialName{}
+	for _, credential := range credentialRotationOrder {
+		if requested[credential] {
+			result = append(result, credential)
+		}
+	}
+	return result, nil
+}
+
+// reconcileCredentialRotation rotates the credentials requested in the RotateCredentialsAnnotation,
+// one at a time. A rotation stores the new credential and then rolls out its consumers group
+// by group, in consumerRolloutOrder, waiting for each group to complete before rolling out
+// the next one. The previous access token, if any, is revoked once every consumer runs with
+// the new one. It returns whether a rotation is in progress
+func (r *ReconcileAPIManager) reconcileCredentialRotation(cr *appsv1alpha1.APIManager, objs []runtime.RawExtension) (bool, error) {
+	if rotation := cr.Status.CredentialRotationInProgress(); rotation != nil {
+		return true, r.reconcileRotationRollout(cr, rotation)
+	}
+
+	requested, err := requestedCredentialRotations(cr)
+	if err != nil {
+		// Invalid annotation - Wait for the user to fix it
+		r.recorder.Eventf(cr, v1.EventTypeWarning, EventReasonInvalidSpec, "Invalid credential rotation request: %v", err)
+		return false, nil
+	}
+	if len(requested) == 0 {
+		return false, nil
+	}
+
+	credential := requested[0]
+	r.reqLogger.Info(fmt.Sprintf("Rotating credential %s", credential))
+	err = r.rotateCredential(cr, objs, credential)
+	if err != nil {
+		return false, fmt.Errorf("error rotating credential %s: %v", credential, err)
+	}
+	return true, r.removeRequestedRotation(cr, credential)
+}
+
+// removeRequestedRotation removes the credential from the RotateCredentialsAnnotation,
+// so it is not rotated again
+func (r *ReconcileAPIManager) removeRequestedRotation(cr *appsv1alpha1.APIManager, credential appsv1alpha1.CredentialName) error {
+	requested, err := requestedCredentialRotations(cr)
+	if err != nil {
+		return nil
+	}
+
+	pending := []string{}
+	found := false
+	for _, name := range requested {
+		if name == credential {
+			found = true
+			continue
+		}
+		pending = append(pending, string(name))
+	}
+	if !found {
+		return nil
+	}
+
+	if len(pending) == 0 {
+		delete(cr.Annotations, appsv1alpha1.RotateCredentialsAnnotation)
+	} else {
+		cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation] = strings.Join(pending, ",")
+	}
+	return r.client.Update(context.TODO(), cr)
+}
+
+// rotateCredential stores a new value of the credential in its secret, then replaces the
+// previous access token in the Tenant and Binding secrets holding it, and records the
+// rotation in the RollingOut phase with the consumers to roll out.
+// The new value is stored together with the rotation record, in the credential secret
+// annotations, right after being issued. Until the rotation is recorded in the status, a
+// retried rotation resumes from the stored value instead of issuing another access token
+func (r *ReconcileAPIManager) rotateCredential(cr *appsv1alpha1.APIManager, objs []runtime.RawExtension, credential appsv1alpha1.CredentialName) error {
+	source := credentialSources[credential]
+
+	secret := &v1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: source.secretName, Namespace: cr.Namespace}, secret)
+	if err != nil {
+		return err
+	}
+
+	if secret.Annotations[rotationCredentialAnnotation] != string(credential) {
+		secret, err = r.storeRotatedCredential(cr, secret, credential)
+		if err != nil {
+			return err
+		}
+	}
+
+	if previousHash := secret.Annotations[rotationPreviousValueAnnotation]; previousHash != "" {
+		err = r.reconcileDependentCredentials(cr, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, previousHash, string(secret.Data[source.key]))
+		if err != nil {
+			return err
+		}
+	}
+
+	var previousTokenID int64
+	if value, ok := secret.Annotations[rotationPreviousTokenIDAnnotation]; ok {
+		previousTokenID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid annotation %s of secret %s: %v", rotationPreviousTokenIDAnnotation, secret.Name, err)
+		}
+	}
+
+	// The config hash of the consumers includes the new value, so they are
+	// rolled out when their config hash is updated
+	consumers := credentialConsumers(objs, source.secretName, source.key)
+	cr.Status.SetCredentialRotation(appsv1alpha1.CredentialRotationStatus{
+		Credential:           credential,
+		Phase:                appsv1alpha1.CredentialRotationRollingOut,
+		LastRotationTime:     metav1.Now(),
+		Consumers:            consumers,
+		PendingConsumers:     append([]string{}, consumers...),
+		RevokedAccessTokenID: previousTokenID,
+	})
+	return r.client.Status().Update(context.TODO(), cr)
+}
+
+// storeRotatedCredential stores a new value of the credential in its secret, recording
+// the rotation in the secret annotations: the rotated credential and, for access tokens,
+// the ID and the hash of the previous token
+func (r *ReconcileAPIManager) storeRotatedCredential(cr *appsv1alpha1.APIManager, secret *v1.Secret, credential appsv1alpha1.CredentialName) (*v1.Secret, error) {
+	source := credentialSources[credential]
+	secret = secret.DeepCopy()
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	for _, key := range rotationAnnotations {
+		delete(secret.Annotations, key)
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	var value string
+	var err error
+	if source.issued {
+		previous := string(secret.Data[source.key])
+		var previousTokenID int64
+		value, previousTokenID, err = r.issueAccessToken(cr, secret, credential)
+		if err != nil {
+			return nil, err
+		}
+		if previousTokenID != 0 {
+			secret.Annotations[rotationPreviousTokenIDAnnotation] = strconv.FormatInt(previousTokenID, 10)
+		}
+		if previous != "" {
+			secret.Annotations[rotationPreviousValueAnnotation] = credentialHash(previous)
+		}
+	} else {
+		value, err = cr.Spec.CredentialPolicy(source.credentialType).Generate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	secret.Annotations[rotationCredentialAnnotation] = string(credential)
+	secret.Data[source.key] = []byte(value)
+	err = r.client.Update(context.TODO(), secret)
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// clearRotationRecord removes the rotation record from the credential secret, once the
+// rotation is recorded in the APIManager status
+func (r *ReconcileAPIManager) clearRotationRecord(cr *appsv1alpha1.APIManager, credential appsv1alpha1.CredentialName) error {
+	source := credentialSources[credential]
+	secret := &v1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: source.secretName, Namespace: cr.Namespace}, secret)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, key := range rotationAnnotations {
+		if _, ok := secret.Annotations[key]; ok {
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	secret = secret.DeepCopy()
+	for _, key := range rotationAnnotations {
+		delete(secret.Annotations, key)
+	}
+	return r.client.Update(context.TODO(), secret)
+}
+
+// keepRotationRecord copies the rotation record of the current secret to the desired one,
+// so reconciling the secret does not remove it
+func keepRotationRecord(current, desired *v1.Secret) {
+	for _, key := range rotationAnnotations {
+		value, ok := current.Annotations[key]
+		if !ok {
+			continue
+		}
+		if desired.Annotations == nil {
+			desired.Annotations = map[string]string{}
+		}
+		desired.Annotations[key] = value
+	}
+}
+
+// credentialHash returns the hex encoded SHA-256 of the credential value
+func credentialHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// reconcileRotationRollout rolls out the pending consumers of the rotation group by group.
+// Once every consumer is rolled out, the previous access token is revoked and the rotation
+// is completed
+func (r *ReconcileAPIManager) reconcileRotationRollout(cr *appsv1alpha1.APIManager, rotation *appsv1alpha1.CredentialRotationStatus) error {
+	// The annotation is not updated when the rotation is interrupted right
+	// after being recorded
+	err := r.removeRequestedRotation(cr, rotation.Credential)
+	if err != nil {
+		return err
+	}
+
+	err = r.clearRotationRecord(cr, rotation.Credential)
+	if err != nil {
+		return err
+	}
+
+	for len(rotation.PendingConsumers) > 0 {
+		group := pendingRolloutGroup(rotation.PendingConsumers)
+		groupRolledOut := true
+		for _, name := range group {
+			_, err = r.reconcileConfigHash(cr.Namespace, name)
+			if err != nil {
+				return err
+			}
+			rolledOut, err := r.deploymentConfigRolledOut(cr.Namespace, name)
+			if err != nil {
+				return err
+			}
+			groupRolledOut = groupRolledOut && rolledOut
+		}
+		if !groupRolledOut {
+			r.reqLogger.Info(fmt.Sprintf("Waiting for %s to roll out with the rotated credential %s", strings.Join(group, ", "), rotation.Credential))
+			return nil
+		}
+
+		rotation.PendingConsumers = rotation.PendingConsumers[len(group):]
+		err = r.client.Status().Update(context.TODO(), cr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if rotation.RevokedAccessTokenID != 0 {
+		err = r.revokeAccessToken(cr, rotation.Credential, rotation.RevokedAccessTokenID)
+		if err != nil {
+			return err
+		}
+	}
+
+	rotation.Phase = appsv1alpha1.CredentialRotationCompleted
+	rotation.PendingConsumers = nil
+	err = r.client.Status().Update(context.TODO(), cr)
+	if err != nil {
+		return err
+	}
+	r.recorder.Eventf(cr, v1.EventTypeNormal, EventReasonCredentialRotated, "Rotated credential %s, rolled out %s", rotation.Credential, strings.Join(rotation.Consumers, ", "))
+	return nil
+}
+
+// pendingRolloutGroup returns the leading pending consumers sharing the same rollout rank
+func pendingRolloutGroup(pending []string) []string {
+	rank := consumerRolloutRank(pending[0])
+	idx := 1
+	for idx < len(pending) && consumerRolloutRank(pending[idx]) == rank {
+		idx++
+	}
+	return pending[:idx]
+}
+
+// credentialRotationDefersRollout returns whether the DeploymentConfig is a consumer of the
+// rotation in progress not due to be rolled out yet
+func credentialRotationDefersRollout(cr *appsv1alpha1.APIManager, name string) bool {
+	rotation := cr.Status.CredentialRotationInProgress()
+	if rotation == nil || len(rotation.PendingConsumers) == 0 {
+		return false
+	}
+
+	current := pendingRolloutGroup(rotation.PendingConsumers)
+	for _, pending := range rotation.PendingConsumers[len(current):] {
+		if pending == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentConfigRolledOut returns whether the latest version of the DeploymentConfig
+// is observed and every replica is updated and available. It reads from the API server,
+// so a config hash update done in the same reconciliation is seen
+func (r *ReconcileAPIManager) deploymentConfigRolledOut(namespace, name string) (bool, error) {
+	dc := &appsv1.DeploymentConfig{}
+	err := r.apiClientReader.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, dc)
+	if err != nil {
+		return false, err
+	}
+
+	return dc.Status.ObservedGeneration >= dc.Generation &&
+		dc.Status.UpdatedReplicas >= dc.Spec.Replicas &&
+		dc.Status.AvailableReplicas >= dc.Spec.Replicas, nil
+}
+
+// credentialPortaClient returns a client of the admin portal the issued credential
+// belongs to, the master or the default tenant admin portal, authenticated with the
+// access token. The portal URL is read from its Route
+func (r *ReconcileAPIManager) credentialPortaClient(cr *appsv1alpha1.APIManager, credential appsv1alpha1.CredentialName, accessToken string) (porta.Client, string, error) {
+	routeName := component.SystemProviderRouteName
+	if credential == appsv1alpha1.MasterAccessTokenCredential {
+		routeName = component.SystemMasterRouteName
+	}
+
+	route := &routev1.Route{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: routeName, Namespace: cr.Namespace}, route)
+	if err != nil {
+		return nil, "", err
+	}
+	portalURL := helper.RouteURL(route)
+
+	tlsConfig, err := cr.Spec.PortalTLS.TLSConfig(r.apiClientReader, cr.Namespace)
+	if err != nil {
+		return nil, "", err
+	}
+
+	portaClient, err := r.portaClientFactory(portalURL, accessToken, tlsConfig)
+	return portaClient, portalURL, err
+}
+
+// issueAccessToken creates a new access token for the master or the default tenant admin user.
+// It returns the new access token value and the ID of the current one, 0 when unknown
+func (r *ReconcileAPIManager) issueAccessToken(cr *appsv1alpha1.APIManager, systemSeed *v1.Secret, credential appsv1alpha1.CredentialName) (string, int64, error) {
+	var accessToken, username string
+	switch credential {
+	case appsv1alpha1.MasterAccessTokenCredential:
+		accessToken = string(systemSeed.Data[component.SystemSecretSystemSeedMasterAccessTokenFieldName])
+		username = string(systemSeed.Data[component.SystemSecretSystemSeedMasterUserFieldName])
+	default:
+		accessToken = string(systemSeed.Data[component.SystemSecretSystemSeedAdminAccessTokenFieldName])
+		username = string(systemSeed.Data[component.SystemSecretSystemSeedAdminUserFieldName])
+	}
+
+	portaClient, portalURL, err := r.credentialPortaClient(cr, credential, accessToken)
+	if err != nil {
+		return "", 0, err
+	}
+
+	var currentTokenID int64
+	current, err := portaClient.ShowAccessToken(accessToken)
+	if err == nil {
+		currentTokenID = current.ID
+	} else if !porta.IsNotFound(err) {
+		return "", 0, err
+	}
+
+	users, err := portaClient.ListProviderUsers()
+	if err != nil {
+		return "", 0, err
+	}
+
+	for _, user := range users.Users {
+		if user.User.UserName != username {
+			continue
+		}
+		token, err := portaClient.CreateAccessToken(user.User.ID, fmt.Sprintf("3scale-operator %s", time.Now().UTC().Format(time.RFC3339)), "rw", accessTokenScopes)
+		if err != nil {
+			return "", 0, err
+		}
+		return token.Value, currentTokenID, nil
+	}
+	return "", 0, fmt.Errorf("user %s not found in %s", username, portalURL)
+}
+
+// revokeAccessToken revokes the previous access token of the credential, authenticated
+// with the new one
+func (r *ReconcileAPIManager) revokeAccessToken(cr *appsv1alpha1.APIManager, credential appsv1alpha1.CredentialName, tokenID int64) error {
+	source := credentialSources[credential]
+	secret := &v1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: source.secretName, Namespace: cr.Namespace}, secret)
+	if err != nil {
+		return err
+	}
+
+	portaClient, _, err := r.credentialPortaClient(cr, credential, string(secret.Data[source.key]))
+	if err != nil {
+		return err
+	}
+
+	err = portaClient.DeleteAccessToken(strconv.FormatInt(tokenID, 10))
+	if err != nil && !porta.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileDependentCredentials replaces the previous access token, identified by its hash,
+// with the new one in the master credentials secrets of the Tenants and the credentials
+// secrets of the Bindings not managed through the APIManager, in the watched namespaces
+func (r *ReconcileAPIManager) reconcileDependentCredentials(cr *appsv1alpha1.APIManager, rotated types.NamespacedName, previousHash, value string) error {
+	for _, namespace := range r.listNamespaces() {
+		tenantList := &capabilitiesv1alpha1.TenantList{}
+		err := r.client.List(context.TODO(), &client.ListOptions{Namespace: namespace}, tenantList)
+		if err != nil {
+			return err
+		}
+		for _, tenantR := range tenantList.Items {
+			if tenantR.Spec.APIManagerRef != nil || tenantR.Spec.MasterCredentialsRef.Name == "" {
+				continue
+			}
+			nn := types.NamespacedName{Name: tenantR.Spec.MasterCredentialsRef.Name, Namespace: tenantR.Spec.MasterCredentialsRef.Namespace}
+			if nn.Namespace == "" {
+				nn.Namespace = tenantR.Namespace
+			}
+			err = r.replaceSecretValue(cr, nn, rotated, component.SystemSecretSystemSeedMasterAccessTokenFieldName, previousHash, value)
+			if err != nil {
+				return err
+			}
+		}
+
+		bindingList := &capabilitiesv1alpha1.BindingList{}
+		err = r.client.List(context.TODO(), &client.ListOptions{Namespace: namespace}, bindingList)
+		if err != nil {
+			return err
+		}
+		for _, binding := range bindingList.Items {
+			if binding.Spec.TenantRef != nil || binding.Spec.CredentialsRef.Name == "" {
+				continue
+			}
+			nn := types.NamespacedName{Name: binding.Spec.CredentialsRef.Name, Namespace: binding.Namespace}
+			err = r.replaceSecretValue(cr, nn, rotated, "token", previousHash, value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// listNamespaces returns the namespaces to list objects from, the watched
+// namespaces or every namespace when the operator watches all of them
+func (r *ReconcileAPIManager) listNamespaces() []string {
+	if len(r.watchNamespaces) == 0 {
+		return []string{""}
+	}
+	return r.watchNamespaces
+}
+
+// namespaceWatched returns whether the operator watches the namespace
+func (r *ReconcileAPIManager) namespaceWatched(namespace string) bool {
+	if len(r.watchNamespaces) == 0 {
+		return true
+	}
+	for _, watched := range r.watchNamespaces {
+		if watched == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceSecretValue updates the secret key when it holds the previous value, identified by its hash.
+// Secrets outside the watched namespaces are not updated
+func (r *ReconcileAPIManager) replaceSecretValue(cr *appsv1alpha1.APIManager, nn, rotated types.NamespacedName, key, previousHash, value string) error {
+	if nn == rotated || !r.namespaceWatched(nn.Namespace) {
+		return nil
+	}
+
+	secret := &v1.Secret{}
+	err := r.apiClientReader.Get(context.TODO(), nn, secret)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if credentialHash(string(secret.Data[key])) != previousHash {
+		return nil
+	}
+
+	secret.Data[key] = []byte(value)
+	err = r.client.Update(context.TODO(), secret)
+	if err != nil {
+		return err
+	}
+	r.recorder.Eventf(cr, v1.EventTypeNormal, EventReasonUpdated, "Updated the rotated access token in Secret %s", nn)
+	return nil
+}
+
+// credentialConsumers returns the names of the DeploymentConfigs reading the secret field,
+// in rollout order
+func credentialConsumers(objs []runtime.RawExtension, secretName, key string) []string {
+	consumers := []string{}
+	for idx := range objs {
+		dc, ok := objs[idx].Object.(*appsv1.DeploymentConfig)
+		if !ok || dc.Spec.Template == nil {
+			continue
+		}
+		if podSpecReadsSecretKey(&dc.Spec.Template.Spec, secretName, key) {
+			consumers = append(consumers, dc.Name)
+		}
+	}
+
+	sort.SliceStable(consumers, func(i, j int) bool {
+		return consumerRolloutRank(consumers[i]) < consumerRolloutRank(consumers[j])
+	})
+	return consumers
+}
+
+func consumerRolloutRank(name string) int {
+	for idx, prefix := range consumerRolloutOrder {
+		if strings.HasPrefix(name, prefix) {
+			return idx
+		}
+	}
+	return len(consumerRolloutOrder)
+}
+
+func podSpecReadsSecretKey(podSpec *v1.PodSpec, secretName, key string) bool {
+	containers := append([]v1.Container{}, podSpec.InitContainers...)
+	containers = append(containers, podSpec.Containers...)
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil && envFrom.SecretRef.Name == secretName {
+				return true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil &&
+				env.ValueFrom.SecretKeyRef.Name == secretName && env.ValueFrom.SecretKeyRef.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
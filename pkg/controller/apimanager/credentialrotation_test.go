@@ -0,0 +1,515 @@
+package apimanager
+
+import (
+	"context"
+	"crypto/tls"
+	goerrors "errors"
+	"reflect"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta/fake"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	capabilitiesv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+const rotationTestNamespace = "operator-test"
+
+func newTestDeploymentConfig(name, namespace string, env ...v1.EnvVar) *appsv1.DeploymentConfig {
+	return &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.DeploymentConfigSpec{
+			Template: &v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: name, Env: env}},
+				},
+			},
+		},
+	}
+}
+
+func secretEnvVar(name, secretName, key string) v1.EnvVar {
+	return v1.EnvVar{
+		Name: name,
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: secretName}, Key: key},
+		},
+	}
+}
+
+func newRotationTestAPIManager(credentials string) *appsv1alpha1.APIManager {
+	tenantName := "3scale"
+	return &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "example-apimanager",
+			Namespace:   rotationTestNamespace,
+			Annotations: map[string]string{appsv1alpha1.RotateCredentialsAnnotation: credentials},
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{WildcardDomain: "example.com", TenantName: &tenantName},
+		},
+	}
+}
+
+// newRotationTestReconciler returns a reconciler with the DeploymentConfigs, both in the
+// cluster and as the desired objects, and the other k8s objects
+func newRotationTestReconciler(t *testing.T, factory porta.ClientFactory, dcs []*appsv1.DeploymentConfig, k8sObjs ...runtime.Object) (*ReconcileAPIManager, client.Client, []runtime.RawExtension) {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{v1.AddToScheme, appsv1.AddToScheme, routev1.AddToScheme, appsv1alpha1.SchemeBuilder.AddToScheme, capabilitiesv1alpha1.SchemeBuilder.AddToScheme} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objs := []runtime.RawExtension{}
+	for _, dc := range dcs {
+		objs = append(objs, runtime.RawExtension{Object: dc})
+		k8sObjs = append(k8sObjs, dc.DeepCopy())
+	}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, k8sObjs...)
+
+	r := &ReconcileAPIManager{
+		client:             k8sClient,
+		apiClientReader:    k8sClient,
+		scheme:             s,
+		reqLogger:          logf.Log,
+		recorder:           record.NewFakeRecorder(20),
+		portaClientFactory: factory,
+	}
+	return r, k8sClient, objs
+}
+
+// newReplicatedDeploymentConfig returns a DeploymentConfig with one replica, not rolled out yet
+func newReplicatedDeploymentConfig(name string, env ...v1.EnvVar) *appsv1.DeploymentConfig {
+	dc := newTestDeploymentConfig(name, rotationTestNamespace, env...)
+	dc.Spec.Replicas = 1
+	return dc
+}
+
+// markRolledOut reports every replica of the DeploymentConfig as updated and available
+func markRolledOut(t *testing.T, k8sClient client.Client, name string) {
+	dc := &appsv1.DeploymentConfig{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: rotationTestNamespace}, dc); err != nil {
+		t.Fatal(err)
+	}
+	dc.Status.ObservedGeneration = dc.Generation
+	dc.Status.UpdatedReplicas = dc.Spec.Replicas
+	dc.Status.AvailableReplicas = dc.Spec.Replicas
+	if err := k8sClient.Update(context.TODO(), dc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func rolledOut(t *testing.T, k8sClient client.Client, name string) bool {
+	dc := &appsv1.DeploymentConfig{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: rotationTestNamespace}, dc); err != nil {
+		t.Fatal(err)
+	}
+	_, ok := dc.Spec.Template.Annotations[ConfigHashAnnotation]
+	return ok
+}
+
+func secretValue(t *testing.T, k8sClient client.Client, nn types.NamespacedName, key string) string {
+	secret := &v1.Secret{}
+	if err := k8sClient.Get(context.TODO(), nn, secret); err != nil {
+		t.Fatal(err)
+	}
+	return string(secret.Data[key])
+}
+
+func reconcileRotation(t *testing.T, r *ReconcileAPIManager, cr *appsv1alpha1.APIManager, objs []runtime.RawExtension) {
+	inProgress, err := r.reconcileCredentialRotation(cr, objs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !inProgress {
+		t.Fatal("expected a credential rotation in progress")
+	}
+}
+
+func TestReconcileCredentialRotationRolloutOrder(t *testing.T) {
+	cr := newRotationTestAPIManager("zyncAuthenticationToken")
+	zyncSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.ZyncSecretName, Namespace: rotationTestNamespace},
+		Data:       map[string][]byte{component.ZyncSecretAuthenticationTokenFieldName: []byte("zync-token")},
+	}
+	zyncToken := secretEnvVar("ZYNC_AUTHENTICATION_TOKEN", component.ZyncSecretName, component.ZyncSecretAuthenticationTokenFieldName)
+	dcs := []*appsv1.DeploymentConfig{
+		newReplicatedDeploymentConfig("apicast-production", zyncToken),
+		newReplicatedDeploymentConfig("zync", zyncToken),
+		newReplicatedDeploymentConfig("backend-listener", zyncToken),
+		newReplicatedDeploymentConfig("system-app", zyncToken),
+		newReplicatedDeploymentConfig("system-sidekiq", zyncToken),
+		newReplicatedDeploymentConfig("backend-worker"),
+	}
+	r, k8sClient, objs := newRotationTestReconciler(t, fake.NewAdminPortal().ClientFactory(), dcs, cr, zyncSecret)
+
+	reconcileRotation(t, r, cr, objs)
+	if _, ok := cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation]; ok {
+		t.Errorf("rotation annotation not removed: %v", cr.Annotations)
+	}
+	zyncNN := types.NamespacedName{Name: component.ZyncSecretName, Namespace: rotationTestNamespace}
+	rotatedValue := secretValue(t, k8sClient, zyncNN, component.ZyncSecretAuthenticationTokenFieldName)
+	if rotatedValue == "zync-token" || len(rotatedValue) != 16 {
+		t.Errorf("zync authentication token not rotated: %s", rotatedValue)
+	}
+	rotation := cr.Status.CredentialRotationInProgress()
+	if rotation == nil {
+		t.Fatalf("expected a rotation rolling out, got %+v", cr.Status.CredentialRotations)
+	}
+	expected := []string{"system-app", "system-sidekiq", "backend-listener", "zync", "apicast-production"}
+	if !reflect.DeepEqual(rotation.Consumers, expected) || !reflect.DeepEqual(rotation.PendingConsumers, expected) {
+		t.Errorf("consumers = %v, pending = %v, expected %v", rotation.Consumers, rotation.PendingConsumers, expected)
+	}
+
+	steps := []struct {
+		rolledOut []string
+		waiting   []string
+	}{
+		{[]string{"system-app", "system-sidekiq"}, []string{"backend-listener", "zync", "apicast-production"}},
+		{[]string{"backend-listener"}, []string{"zync", "apicast-production"}},
+		{[]string{"zync"}, []string{"apicast-production"}},
+		{[]string{"apicast-production"}, nil},
+	}
+	for _, step := range steps {
+		reconcileRotation(t, r, cr, objs)
+		for _, name := range step.rolledOut {
+			if !rolledOut(t, k8sClient, name) {
+				t.Fatalf("%s not rolled out", name)
+			}
+		}
+		for _, name := range step.waiting {
+			if rolledOut(t, k8sClient, name) {
+				t.Fatalf("%s rolled out before %v completed", name, step.rolledOut)
+			}
+			// The regular reconciliation does not roll them out either
+			if err := r.reconcileObject(cr, newReplicatedDeploymentConfig(name, zyncToken)); err != nil {
+				t.Fatal(err)
+			}
+			if rolledOut(t, k8sClient, name) {
+				t.Fatalf("%s rolled out by the reconciliation before %v completed", name, step.rolledOut)
+			}
+		}
+		if rolledOut(t, k8sClient, "apicast-production") != (step.waiting == nil) {
+			t.Fatalf("apicast-production rolled out before %v completed", step.rolledOut)
+		}
+		for _, name := range step.rolledOut {
+			markRolledOut(t, k8sClient, name)
+		}
+	}
+
+	inProgress, err := r.reconcileCredentialRotation(cr, objs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !inProgress {
+		t.Fatal("expected the completed rotation to be reported")
+	}
+	if rotation := cr.Status.CredentialRotationInProgress(); rotation != nil {
+		t.Fatalf("rotation not completed: %+v", rotation)
+	}
+	if rolledOut(t, k8sClient, "backend-worker") {
+		t.Error("backend-worker does not read the rotated credential and must not be rolled out")
+	}
+	if value := secretValue(t, k8sClient, zyncNN, component.ZyncSecretAuthenticationTokenFieldName); value != rotatedValue {
+		t.Errorf("credential rotated twice: %s, expected %s", value, rotatedValue)
+	}
+
+	// Nothing else is requested
+	inProgress, err = r.reconcileCredentialRotation(cr, objs)
+	if err != nil || inProgress {
+		t.Errorf("expected no rotation in progress, got %t, %v", inProgress, err)
+	}
+}
+
+func TestReconcileCredentialRotationRevokesAccessToken(t *testing.T) {
+	cr := newRotationTestAPIManager("masterAccessToken")
+	cr.Spec.PortalTLS = &capabilitiesv1alpha1.AdminPortalTLSSpec{InsecureSkipVerify: true}
+	systemSeed := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemSecretSystemSeedSecretName, Namespace: rotationTestNamespace},
+		Data: map[string][]byte{
+			component.SystemSecretSystemSeedMasterUserFieldName:        []byte("master"),
+			component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("master-token"),
+		},
+	}
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemMasterRouteName, Namespace: rotationTestNamespace},
+		Spec:       routev1.RouteSpec{Host: "master.custom.example.com", TLS: &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}},
+	}
+
+	// A Tenant and a Binding in another namespace holding the master access token,
+	// and a Binding holding an unrelated token
+	tenantsNamespace := "tenants"
+	tenantR := &capabilitiesv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: tenantsNamespace},
+		Spec: capabilitiesv1alpha1.TenantSpec{
+			SystemMasterUrl:      "https://master.custom.example.com",
+			MasterCredentialsRef: v1.SecretReference{Name: "master-credentials"},
+		},
+	}
+	binding := &capabilitiesv1alpha1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: tenantsNamespace},
+		Spec:       capabilitiesv1alpha1.BindingSpec{CredentialsRef: v1.SecretReference{Name: "binding-credentials"}},
+	}
+	otherBinding := &capabilitiesv1alpha1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-binding", Namespace: tenantsNamespace},
+		Spec:       capabilitiesv1alpha1.BindingSpec{CredentialsRef: v1.SecretReference{Name: "other-credentials"}},
+	}
+	masterCredentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "master-credentials", Namespace: tenantsNamespace},
+		Data:       map[string][]byte{component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("master-token")},
+	}
+	bindingCredentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "binding-credentials", Namespace: tenantsNamespace},
+		Data:       map[string][]byte{"token": []byte("master-token"), "adminURL": []byte("https://master.custom.example.com")},
+	}
+	otherCredentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-credentials", Namespace: tenantsNamespace},
+		Data:       map[string][]byte{"token": []byte("tenant-token"), "adminURL": []byte("https://tenant-admin.example.com")},
+	}
+
+	portal := fake.NewAdminPortal()
+	user := portal.AddProviderUser("master", "master@example.com")
+	previousToken := portal.AddAccessToken(user.ID, "master-token")
+	portalURLs := map[string]bool{}
+	factory := func(adminURL, accessToken string, tlsConfig *tls.Config) (porta.Client, error) {
+		portalURLs[adminURL] = true
+		if tlsConfig == nil || !tlsConfig.InsecureSkipVerify {
+			t.Errorf("expected the portalTLS settings, got %+v", tlsConfig)
+		}
+		return portal, nil
+	}
+
+	masterToken := secretEnvVar("MASTER_ACCESS_TOKEN", component.SystemSecretSystemSeedSecretName, component.SystemSecretSystemSeedMasterAccessTokenFieldName)
+	dcs := []*appsv1.DeploymentConfig{newReplicatedDeploymentConfig("system-app", masterToken)}
+	r, k8sClient, objs := newRotationTestReconciler(t, factory, dcs, cr, systemSeed, route, tenantR, binding, otherBinding, masterCredentials, bindingCredentials, otherCredentials)
+
+	reconcileRotation(t, r, cr, objs)
+	if !reflect.DeepEqual(portalURLs, map[string]bool{"https://master.custom.example.com": true}) {
+		t.Errorf("unexpected portal URLs %v", portalURLs)
+	}
+	seedNN := types.NamespacedName{Name: component.SystemSecretSystemSeedSecretName, Namespace: rotationTestNamespace}
+	newToken := secretValue(t, k8sClient, seedNN, component.SystemSecretSystemSeedMasterAccessTokenFieldName)
+	if newToken == "master-token" || newToken == "" {
+		t.Fatalf("master access token not rotated: %s", newToken)
+	}
+	if value := secretValue(t, k8sClient, types.NamespacedName{Name: "master-credentials", Namespace: tenantsNamespace}, component.SystemSecretSystemSeedMasterAccessTokenFieldName); value != newToken {
+		t.Errorf("tenant master credentials = %s, expected %s", value, newToken)
+	}
+	if value := secretValue(t, k8sClient, types.NamespacedName{Name: "binding-credentials", Namespace: tenantsNamespace}, "token"); value != newToken {
+		t.Errorf("binding credentials = %s, expected %s", value, newToken)
+	}
+	if value := secretValue(t, k8sClient, types.NamespacedName{Name: "other-credentials", Namespace: tenantsNamespace}, "token"); value != "tenant-token" {
+		t.Errorf("unrelated binding credentials changed to %s", value)
+	}
+	if rotation := cr.Status.CredentialRotationInProgress(); rotation == nil || rotation.RevokedAccessTokenID != previousToken.ID {
+		t.Fatalf("expected the previous access token to be revoked after the rollout, got %+v", rotation)
+	}
+
+	// The previous token is kept until every consumer is rolled out
+	reconcileRotation(t, r, cr, objs)
+	if !rolledOut(t, k8sClient, "system-app") {
+		t.Fatal("system-app not rolled out")
+	}
+	if _, err := portal.ShowAccessToken(previousToken.Value); err != nil {
+		t.Fatalf("previous access token revoked before the rollout completed: %v", err)
+	}
+
+	markRolledOut(t, k8sClient, "system-app")
+	reconcileRotation(t, r, cr, objs)
+	if rotation := cr.Status.CredentialRotationInProgress(); rotation != nil {
+		t.Fatalf("rotation not completed: %+v", rotation)
+	}
+	if _, err := portal.ShowAccessToken(previousToken.Value); !porta.IsNotFound(err) {
+		t.Errorf("previous access token not revoked: %v", err)
+	}
+	if _, err := portal.ShowAccessToken(newToken); err != nil {
+		t.Errorf("new access token revoked: %v", err)
+	}
+}
+
+func TestReconcileCredentialRotationPartialFailure(t *testing.T) {
+	cr := newRotationTestAPIManager("masterAccessToken,zyncAuthenticationToken")
+	systemSeed := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemSecretSystemSeedSecretName, Namespace: rotationTestNamespace},
+		Data: map[string][]byte{
+			component.SystemSecretSystemSeedMasterUserFieldName:        []byte("master"),
+			component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("master-token"),
+		},
+	}
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemMasterRouteName, Namespace: rotationTestNamespace},
+		Spec:       routev1.RouteSpec{Host: "master.example.com"},
+	}
+	masterToken := secretEnvVar("MASTER_ACCESS_TOKEN", component.SystemSecretSystemSeedSecretName, component.SystemSecretSystemSeedMasterAccessTokenFieldName)
+	dcs := []*appsv1.DeploymentConfig{newReplicatedDeploymentConfig("system-app", masterToken)}
+
+	// The master user does not exist, so no access token can be issued
+	portal := fake.NewAdminPortal()
+	r, k8sClient, objs := newRotationTestReconciler(t, portal.ClientFactory(), dcs, cr, systemSeed, route)
+
+	if _, err := r.reconcileCredentialRotation(cr, objs); err == nil {
+		t.Fatal("expected an error")
+	}
+	seedNN := types.NamespacedName{Name: component.SystemSecretSystemSeedSecretName, Namespace: rotationTestNamespace}
+	if value := secretValue(t, k8sClient, seedNN, component.SystemSecretSystemSeedMasterAccessTokenFieldName); value != "master-token" {
+		t.Errorf("master access token changed to %s", value)
+	}
+	if value := cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation]; value != "masterAccessToken,zyncAuthenticationToken" {
+		t.Errorf("rotation annotation changed to %s", value)
+	}
+	if len(cr.Status.CredentialRotations) != 0 || rolledOut(t, k8sClient, "system-app") {
+		t.Errorf("unexpected rotation %+v", cr.Status.CredentialRotations)
+	}
+
+	// The rotation is retried once the user exists
+	portal.AddProviderUser("master", "master@example.com")
+	reconcileRotation(t, r, cr, objs)
+	newToken := secretValue(t, k8sClient, seedNN, component.SystemSecretSystemSeedMasterAccessTokenFieldName)
+	if newToken == "master-token" {
+		t.Fatal("master access token not rotated")
+	}
+
+	// The annotation update is lost, so the credential is still requested. The
+	// rotation in progress is resumed instead of rotating the credential again
+	stored := &appsv1alpha1.APIManager{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, stored); err != nil {
+		t.Fatal(err)
+	}
+	stored.Annotations[appsv1alpha1.RotateCredentialsAnnotation] = "masterAccessToken,zyncAuthenticationToken"
+	if err := k8sClient.Update(context.TODO(), stored); err != nil {
+		t.Fatal(err)
+	}
+	cr = stored
+	reconcileRotation(t, r, cr, objs)
+	if value := secretValue(t, k8sClient, seedNN, component.SystemSecretSystemSeedMasterAccessTokenFieldName); value != newToken {
+		t.Errorf("master access token rotated again: %s, expected %s", value, newToken)
+	}
+	if value := cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation]; value != "zyncAuthenticationToken" {
+		t.Errorf("rotation annotation = %s, expected zyncAuthenticationToken", value)
+	}
+	if !rolledOut(t, k8sClient, "system-app") {
+		t.Error("system-app not rolled out")
+	}
+}
+
+// failingStatusClient fails the status updates while fail is set
+type failingStatusClient struct {
+	client.Client
+	fail *bool
+}
+
+func (c failingStatusClient) Status() client.StatusWriter {
+	return failingStatusWriter{StatusWriter: c.Client.Status(), fail: c.fail}
+}
+
+type failingStatusWriter struct {
+	client.StatusWriter
+	fail *bool
+}
+
+func (w failingStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	if *w.fail {
+		return goerrors.New("status update failed")
+	}
+	return w.StatusWriter.Update(ctx, obj)
+}
+
+func TestReconcileCredentialRotationResumesIssuedAccessToken(t *testing.T) {
+	cr := newRotationTestAPIManager("masterAccessToken")
+	systemSeed := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemSecretSystemSeedSecretName, Namespace: rotationTestNamespace},
+		Data: map[string][]byte{
+			component.SystemSecretSystemSeedMasterUserFieldName:        []byte("master"),
+			component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("master-token"),
+		},
+	}
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemMasterRouteName, Namespace: rotationTestNamespace},
+		Spec:       routev1.RouteSpec{Host: "master.example.com"},
+	}
+
+	// Bindings holding the master access token, in a watched and in a not watched namespace
+	var k8sObjs []runtime.Object
+	for _, namespace := range []string{"tenants", "not-watched"} {
+		k8sObjs = append(k8sObjs,
+			&capabilitiesv1alpha1.Binding{
+				ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: namespace},
+				Spec:       capabilitiesv1alpha1.BindingSpec{CredentialsRef: v1.SecretReference{Name: "binding-credentials"}},
+			},
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "binding-credentials", Namespace: namespace},
+				Data:       map[string][]byte{"token": []byte("master-token")},
+			},
+		)
+	}
+
+	portal := fake.NewAdminPortal()
+	user := portal.AddProviderUser("master", "master@example.com")
+	previousToken := portal.AddAccessToken(user.ID, "master-token")
+
+	masterToken := secretEnvVar("MASTER_ACCESS_TOKEN", component.SystemSecretSystemSeedSecretName, component.SystemSecretSystemSeedMasterAccessTokenFieldName)
+	dcs := []*appsv1.DeploymentConfig{newReplicatedDeploymentConfig("system-app", masterToken)}
+	r, k8sClient, objs := newRotationTestReconciler(t, portal.ClientFactory(), dcs, append(k8sObjs, cr, systemSeed, route)...)
+	r.watchNamespaces = []string{rotationTestNamespace, "tenants"}
+
+	// The rotation cannot be recorded in the status
+	failStatus := true
+	r.client = failingStatusClient{Client: k8sClient, fail: &failStatus}
+	if _, err := r.reconcileCredentialRotation(cr, objs); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	seedNN := types.NamespacedName{Name: component.SystemSecretSystemSeedSecretName, Namespace: rotationTestNamespace}
+	newToken := secretValue(t, k8sClient, seedNN, component.SystemSecretSystemSeedMasterAccessTokenFieldName)
+	if newToken == "master-token" || newToken == "" {
+		t.Fatalf("master access token not stored: %s", newToken)
+	}
+	if value := secretValue(t, k8sClient, types.NamespacedName{Name: "binding-credentials", Namespace: "tenants"}, "token"); value != newToken {
+		t.Errorf("binding credentials = %s, expected %s", value, newToken)
+	}
+	if value := secretValue(t, k8sClient, types.NamespacedName{Name: "binding-credentials", Namespace: "not-watched"}, "token"); value != "master-token" {
+		t.Errorf("binding credentials in a not watched namespace changed to %s", value)
+	}
+
+	// The retried rotation reuses the stored access token
+	failStatus = false
+	stored := &appsv1alpha1.APIManager{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, stored); err != nil {
+		t.Fatal(err)
+	}
+	cr = stored
+	reconcileRotation(t, r, cr, objs)
+	if value := secretValue(t, k8sClient, seedNN, component.SystemSecretSystemSeedMasterAccessTokenFieldName); value != newToken {
+		t.Errorf("master access token issued again: %s, expected %s", value, newToken)
+	}
+	if tokens := portal.ListAccessTokens(user.ID); len(tokens) != 2 {
+		t.Errorf("expected the previous and the new access tokens, got %+v", tokens)
+	}
+	if rotation := cr.Status.CredentialRotationInProgress(); rotation == nil || rotation.RevokedAccessTokenID != previousToken.ID {
+		t.Fatalf("expected the previous access token to be revoked after the rollout, got %+v", rotation)
+	}
+
+	// The rotation record is removed once the rollout starts
+	reconcileRotation(t, r, cr, objs)
+	seed := &v1.Secret{}
+	if err := k8sClient.Get(context.TODO(), seedNN, seed); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range rotationAnnotations {
+		if _, ok := seed.Annotations[key]; ok {
+			t.Errorf("rotation annotation %s not removed", key)
+		}
+	}
+}
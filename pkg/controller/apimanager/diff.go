@@ -0,0 +1,326 @@
+package apimanager
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectDiffType is the type of difference between an object the operator
+// would create for an APIManager and the object in the cluster
+type ObjectDiffType string
+
+const (
+	// ObjectDiffMissing is an object the operator would create that does
+	// not exist in the cluster
+	ObjectDiffMissing ObjectDiffType = "Missing"
+	// ObjectDiffChanged is an object whose fields differ from the ones
+	// the operator would set
+	ObjectDiffChanged ObjectDiffType = "Changed"
+	// ObjectDiffLeftover is an object controlled by the APIManager that the
+	// operator would not create anymore, like the objects of a disabled
+	// feature
+	ObjectDiffLeftover ObjectDiffType = "Leftover"
+)
+
+const (
+	missingFieldValue = "<missing>"
+	hiddenFieldValue  = "<hidden>"
+)
+
+// ObjectDiff is a difference between an object the operator would create
+// for an APIManager and the object in the cluster
+type ObjectDiff struct {
+	Kind string
+	Name string
+	Type ObjectDiffType
+	// Fields are the differing fields of Changed objects
+	Fields []FieldDiff
+}
+
+// FieldDiff is a field whose live value differs from the desired one.
+// Values of Secrets are hidden
+type FieldDiff struct {
+	Path    string
+	Desired string
+	Live    string
+}
+
+// apiManagerObjectKinds are the kinds of objects created for an APIManager.
+// Their lists are read as unstructured objects because the OpenShift image
+// API registers some core lists, like SecretList, in its group too
+var apiManagerObjectKinds = []schema.GroupVersionKind{
+	imagev1.SchemeGroupVersion.WithKind("ImageStream"),
+	appsv1.SchemeGroupVersion.WithKind("DeploymentConfig"),
+	v1.SchemeGroupVersion.WithKind("Service"),
+	v1.SchemeGroupVersion.WithKind("ServiceAccount"),
+	v1.SchemeGroupVersion.WithKind("ConfigMap"),
+	v1.SchemeGroupVersion.WithKind("Secret"),
+	v1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"),
+	routev1.SchemeGroupVersion.WithKind("Route"),
+}
+
+// DiffAPIManagerObjects compares the objects the operator would create for
+// the given APIManager with the objects in the cluster. Only the fields set
+// by the operator are compared, so fields populated by the server are
+// ignored. The defaults of the APIManager have to be set before calling it
+func DiffAPIManagerObjects(c client.Client, scheme *runtime.Scheme, cr *appsv1alpha1.APIManager) ([]ObjectDiff, error) {
+	desiredObjs, err := RenderAPIManagerObjects(c, scheme, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := []ObjectDiff{}
+	desiredNames := map[string]bool{}
+	for idx := range desiredObjs {
+		desired := desiredObjs[idx].Object
+		objectMeta := desired.(metav1.Object)
+		kind := desired.GetObjectKind().GroupVersionKind().Kind
+		desiredNames[kind+"/"+objectMeta.GetName()] = true
+
+		live := reflect.New(reflect.TypeOf(desired).Elem()).Interface().(runtime.Object)
+		err := c.Get(context.TODO(), types.NamespacedName{Name: objectMeta.GetName(), Namespace: objectMeta.GetNamespace()}, live)
+		if errors.IsNotFound(err) {
+			diffs = append(diffs, ObjectDiff{Kind: kind, Name: objectMeta.GetName(), Type: ObjectDiffMissing})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fields, err := diffObject(kind, desired, live)
+		if err != nil {
+			return nil, fmt.Errorf("error comparing %s %s: %v", kind, objectMeta.GetName(), err)
+		}
+		if len(fields) > 0 {
+			diffs = append(diffs, ObjectDiff{Kind: kind, Name: objectMeta.GetName(), Type: ObjectDiffChanged, Fields: fields})
+		}
+	}
+
+	for _, gvk := range apiManagerObjectKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err := c.List(context.TODO(), &client.ListOptions{Namespace: cr.Namespace}, list)
+		if err != nil {
+			return nil, err
+		}
+		for idx := range list.Items {
+			item := &list.Items[idx]
+			controllerRef := metav1.GetControllerOf(item)
+			if controllerRef == nil || controllerRef.UID != cr.UID {
+				continue
+			}
+			if !desiredNames[gvk.Kind+"/"+item.GetName()] {
+				diffs = append(diffs, ObjectDiff{Kind: gvk.Kind, Name: item.GetName(), Type: ObjectDiffLeftover})
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// diffObject returns the fields set in desired whose value differs in live
+func diffObject(kind string, desired, live runtime.Object) ([]FieldDiff, error) {
+	desiredFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return nil, err
+	}
+	liveFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return nil, err
+	}
+	delete(desiredFields, "status")
+	// Typed objects read from the API server have no apiVersion and kind, and
+	// the metadata populated by the server is not set by the operator
+	for _, fields := range []map[string]interface{}{desiredFields, liveFields} {
+		removeServerFields(fields)
+	}
+
+	// The API server stores stringData into data
+	if stringData, ok := desiredFields["stringData"].(map[string]interface{}); ok {
+		data, _ := desiredFields["data"].(map[string]interface{})
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		for key, value := range stringData {
+			data[key] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+		}
+		desiredFields["data"] = data
+		delete(desiredFields, "stringData")
+	}
+
+	differ := &fieldDiffer{kind: kind}
+	differ.diff(desiredFields, liveFields, "", nil)
+	return differ.fields, nil
+}
+
+// serverMetadataFields are the metadata fields populated by the API server
+var serverMetadataFields = []string{"uid", "resourceVersion", "generation", "creationTimestamp", "selfLink", "deletionTimestamp", "deletionGracePeriodSeconds"}
+
+// removeServerFields removes the type fields and the metadata fields populated by the API server
+func removeServerFields(fields map[string]interface{}) {
+	delete(fields, "apiVersion")
+	delete(fields, "kind")
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverMetadataFields {
+			delete(metadata, field)
+		}
+	}
+}
+
+type fieldDiffer struct {
+	kind   string
+	fields []FieldDiff
+}
+
+// diff compares desired with live. keys are the map keys from the object
+// root, without list indexes. Zero values in desired are considered unset,
+// as the server usually populates them
+func (d *fieldDiffer) diff(desired, live interface{}, path string, keys []string) {
+	if desired == nil || d.ignored(keys) {
+		return
+	}
+
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			d.add(path, keys, desired, live)
+			return
+		}
+		fieldNames := []string{}
+		for fieldName := range desiredValue {
+			fieldNames = append(fieldNames, fieldName)
+		}
+		sort.Strings(fieldNames)
+		for _, fieldName := range fieldNames {
+			fieldPath := fieldName
+			if path != "" {
+				fieldPath = path + "." + fieldName
+			}
+			d.diff(desiredValue[fieldName], liveValue[fieldName], fieldPath, append(append([]string{}, keys...), fieldName))
+		}
+	case []interface{}:
+		liveValue, _ := live.([]interface{})
+		if names, ok := listElementNames(desiredValue); ok {
+			d.diffNamedList(desiredValue, liveValue, names, path, keys)
+			return
+		}
+		if len(desiredValue) != len(liveValue) {
+			d.add(path, keys, desired, live)
+			return
+		}
+		for idx := range desiredValue {
+			d.diff(desiredValue[idx], liveValue[idx], fmt.Sprintf("%s[%d]", path, idx), keys)
+		}
+	default:
+		if reflect.DeepEqual(desired, reflect.Zero(reflect.TypeOf(desired)).Interface()) {
+			return
+		}
+		if !reflect.DeepEqual(desired, live) {
+			d.add(path, keys, desired, live)
+		}
+	}
+}
+
+// diffNamedList compares the elements of lists whose elements have a name,
+// like containers or env vars, by name, so their order does not matter.
+// Live elements not in desired are reported as well
+func (d *fieldDiffer) diffNamedList(desired, live []interface{}, desiredNames []string, path string, keys []string) {
+	liveElements := map[string]interface{}{}
+	liveNames := []string{}
+	for _, elem := range live {
+		if elemFields, ok := elem.(map[string]interface{}); ok {
+			if name, ok := elemFields["name"].(string); ok {
+				liveElements[name] = elem
+				liveNames = append(liveNames, name)
+			}
+		}
+	}
+
+	desiredElements := map[string]bool{}
+	for idx, name := range desiredNames {
+		desiredElements[name] = true
+		d.diff(desired[idx], liveElements[name], fmt.Sprintf("%s[name=%s]", path, name), keys)
+	}
+	for _, name := range liveNames {
+		if !desiredElements[name] {
+			d.add(fmt.Sprintf("%s[name=%s]", path, name), keys, nil, liveElements[name])
+		}
+	}
+}
+
+func (d *fieldDiffer) add(path string, keys []string, desired, live interface{}) {
+	hidden := d.kind == "Secret" && len(keys) > 0 && keys[0] == "data"
+	d.fields = append(d.fields, FieldDiff{
+		Path:    path,
+		Desired: formatFieldValue(desired, hidden),
+		Live:    formatFieldValue(live, hidden),
+	})
+}
+
+// ignored returns whether the field is populated by the cluster. The images
+// of DeploymentConfig containers are set by their image change triggers
+func (d *fieldDiffer) ignored(keys []string) bool {
+	keyPath := strings.Join(keys, "/")
+	switch d.kind {
+	case "DeploymentConfig":
+		// The config hash of the live DeploymentConfig also covers the
+		// Secret keys not generated by the operator
+		return keyPath == "spec/template/spec/containers/image" || keyPath == "spec/template/spec/initContainers/image" ||
+			keyPath == "spec/template/metadata/annotations/"+ConfigHashAnnotation
+	}
+	return false
+}
+
+// listElementNames returns the names of the list elements when all of them
+// have a name
+func listElementNames(list []interface{}) ([]string, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+	names := []string{}
+	for _, elem := range list {
+		elemFields, ok := elem.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := elemFields["name"].(string)
+		if !ok || name == "" {
+			return nil, false
+		}
+		names = append(names, name)
+	}
+	return names, true
+}
+
+func formatFieldValue(value interface{}, hidden bool) string {
+	if value == nil {
+		return missingFieldValue
+	}
+	if hidden {
+		return hiddenFieldValue
+	}
+	result, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(result)
+}
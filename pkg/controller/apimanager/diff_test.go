@@ -0,0 +1,202 @@
+package apimanager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// typedListClient lists unstructured lists as typed lists, as the fake
+// client cannot decode unstructured lists
+type typedListClient struct {
+	client.Client
+	scheme *runtime.Scheme
+}
+
+func (c *typedListClient) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	unstructuredList, ok := list.(*unstructured.UnstructuredList)
+	if !ok {
+		return c.Client.List(ctx, opts, list)
+	}
+
+	listGVK := unstructuredList.GroupVersionKind()
+	typedList, err := c.scheme.New(listGVK)
+	if err != nil {
+		return err
+	}
+	// Some lists are registered in several groups, so the object kind is
+	// set explicitly
+	typedOpts := *opts
+	typedOpts.Raw = &metav1.ListOptions{TypeMeta: metav1.TypeMeta{
+		APIVersion: listGVK.GroupVersion().String(),
+		Kind:       strings.TrimSuffix(listGVK.Kind, "List"),
+	}}
+	if err := c.Client.List(ctx, &typedOpts, typedList); err != nil {
+		return err
+	}
+
+	items, err := meta.ExtractList(typedList)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		itemFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+		if err != nil {
+			return err
+		}
+		unstructuredList.Items = append(unstructuredList.Items, unstructured.Unstructured{Object: itemFields})
+	}
+	return nil
+}
+
+func TestDiffAPIManagerObjects(t *testing.T) {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{v1.AddToScheme, appsv1.AddToScheme, imagev1.AddToScheme, routev1.AddToScheme, appsv1alpha1.SchemeBuilder.AddToScheme} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	namespace := "operator-test"
+	cr := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace, UID: types.UID("apimanager-uid")},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				ProductVersion: product.ProductUpstream,
+				WildcardDomain: "example.com",
+			},
+		},
+	}
+	if _, err := cr.SetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+
+	desiredObjs, err := RenderAPIManagerObjects(k8sfake.NewFakeClientWithScheme(s), s, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	liveObjs := []runtime.Object{}
+	for idx := range desiredObjs {
+		obj := desiredObjs[idx].Object
+		switch typedObj := obj.(type) {
+		case *v1.Service:
+			if typedObj.Name == "system-sphinx" {
+				continue
+			}
+		case *v1.Secret:
+			// The API server stores stringData into data
+			for key, value := range typedObj.StringData {
+				if typedObj.Data == nil {
+					typedObj.Data = map[string][]byte{}
+				}
+				typedObj.Data[key] = []byte(value)
+			}
+			typedObj.StringData = nil
+		}
+		liveObjs = append(liveObjs, obj)
+	}
+	leftover := &v1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "leftover", Namespace: namespace},
+	}
+	if err := controllerutil.SetControllerReference(cr, leftover, s); err != nil {
+		t.Fatal(err)
+	}
+	unrelated := &v1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: namespace},
+	}
+	liveObjs = append(liveObjs, leftover, unrelated)
+	k8sClient := &typedListClient{Client: k8sfake.NewFakeClientWithScheme(s, liveObjs...), scheme: s}
+
+	systemApp := &appsv1.DeploymentConfig{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "system-app", Namespace: namespace}, systemApp); err != nil {
+		t.Fatal(err)
+	}
+	systemApp.Spec.Replicas = 3
+	systemApp.Spec.Template.Spec.Containers[0].Image = "docker-registry/amp-system@sha256:1234"
+	if err := k8sClient.Update(context.TODO(), systemApp); err != nil {
+		t.Fatal(err)
+	}
+
+	diffs, err := DiffAPIManagerObjects(k8sClient, s, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ObjectDiff{
+		{Kind: "Service", Name: "system-sphinx", Type: ObjectDiffMissing},
+		{Kind: "DeploymentConfig", Name: "system-app", Type: ObjectDiffChanged, Fields: []FieldDiff{{Path: "spec.replicas", Desired: "1", Live: "3"}}},
+		{Kind: "ConfigMap", Name: "leftover", Type: ObjectDiffLeftover},
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("expected diffs %v, got %v", expected, diffs)
+	}
+	for idx := range expected {
+		if diffs[idx].Kind != expected[idx].Kind || diffs[idx].Name != expected[idx].Name || diffs[idx].Type != expected[idx].Type {
+			t.Errorf("expected diff %v, got %v", expected[idx], diffs[idx])
+		}
+		if len(diffs[idx].Fields) != len(expected[idx].Fields) {
+			t.Errorf("%s %s: expected fields %v, got %v", expected[idx].Kind, expected[idx].Name, expected[idx].Fields, diffs[idx].Fields)
+			continue
+		}
+		for fieldIdx := range expected[idx].Fields {
+			if diffs[idx].Fields[fieldIdx] != expected[idx].Fields[fieldIdx] {
+				t.Errorf("%s %s: expected field %v, got %v", expected[idx].Kind, expected[idx].Name, expected[idx].Fields[fieldIdx], diffs[idx].Fields[fieldIdx])
+			}
+		}
+	}
+}
+
+func TestDiffObjectIgnoresServerFields(t *testing.T) {
+	desired := &v1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "smtp", Namespace: "operator-test", Labels: map[string]string{"app": "3scale-api-management"}},
+		Data:       map[string]string{"address": ""},
+	}
+	// Typed objects read from the API server have no TypeMeta
+	live := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "smtp",
+			Namespace:         "operator-test",
+			Labels:            map[string]string{"app": "3scale-api-management"},
+			UID:               types.UID("smtp-uid"),
+			ResourceVersion:   "42",
+			Generation:        1,
+			CreationTimestamp: metav1.Now(),
+		},
+		Data: map[string]string{"address": "smtp.example.com"},
+	}
+
+	fields, err := diffObject("ConfigMap", desired, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 0 {
+		t.Errorf("expected no differences, got %v", fields)
+	}
+
+	desired.Labels["app"] = "3scale"
+	fields, err = diffObject("ConfigMap", desired, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 1 || fields[0].Path != "metadata.labels.app" {
+		t.Errorf("expected the label difference, got %v", fields)
+	}
+}
@@ -0,0 +1,52 @@
+package apimanager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	imagev1 "github.com/openshift/api/image/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// reconcileImageStream sets the source and the import policy of the tags generated
+// by the operator on the existing ImageStream, so changes of the registry mirror,
+// the image digests or the release images are imported. Tags not generated by the
+// operator are left as they are
+func (r *ReconcileAPIManager) reconcileImageStream(cr *appsv1alpha1.APIManager, desired, current *imagev1.ImageStream) error {
+	currentCopy := current.DeepCopy()
+
+	changed := false
+	for _, desiredTag := range desired.Spec.Tags {
+		found := false
+		for idx := range currentCopy.Spec.Tags {
+			tag := &currentCopy.Spec.Tags[idx]
+			if tag.Name != desiredTag.Name {
+				continue
+			}
+			found = true
+			if !reflect.DeepEqual(tag.From, desiredTag.From) || tag.ImportPolicy != desiredTag.ImportPolicy {
+				tag.From = desiredTag.From.DeepCopy()
+				tag.ImportPolicy = desiredTag.ImportPolicy
+				changed = true
+			}
+			break
+		}
+		if !found {
+			currentCopy.Spec.Tags = append(currentCopy.Spec.Tags, *desiredTag.DeepCopy())
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	r.reqLogger.Info(fmt.Sprintf("Updating the tags of ImageStream %s", current.Name))
+	err := r.client.Update(context.TODO(), currentCopy)
+	if err != nil {
+		return err
+	}
+	r.recorder.Eventf(cr, v1.EventTypeNormal, EventReasonUpdated, "Updated the tags of ImageStream %s", current.Name)
+	return nil
+}
@@ -0,0 +1,97 @@
+package apimanager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func renderImageStream(t *testing.T, k8sClient client.Client, s *runtime.Scheme, cr *appsv1alpha1.APIManager, name string) *imagev1.ImageStream {
+	objs, err := RenderAPIManagerObjects(k8sClient, s, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx := range objs {
+		if imageStream, ok := objs[idx].Object.(*imagev1.ImageStream); ok && imageStream.Name == name {
+			return imageStream
+		}
+	}
+	t.Fatalf("ImageStream %s not rendered", name)
+	return nil
+}
+
+func TestReconcileImageStreamMirror(t *testing.T) {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{v1.AddToScheme, appsv1.AddToScheme, imagev1.AddToScheme, appsv1alpha1.SchemeBuilder.AddToScheme} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	namespace := "operator-test"
+	cr := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				ProductVersion: product.ProductRelease_2_5,
+				WildcardDomain: "example.com",
+			},
+		},
+	}
+	if _, err := cr.SetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, cr)
+
+	// The ImageStream created before the registry mirror was configured,
+	// with a tag added by the user
+	existing := renderImageStream(t, k8sClient, s, cr, "amp-backend").DeepCopy()
+	existing.Spec.Tags = append(existing.Spec.Tags, imagev1.TagReference{
+		Name: "custom",
+		From: &v1.ObjectReference{Kind: "DockerImage", Name: "registry.example.com/backend:custom"},
+	})
+	if err := k8sClient.Create(context.TODO(), existing); err != nil {
+		t.Fatal(err)
+	}
+
+	mirror := "mirror.example.com:5000/3scale/"
+	cr.Spec.Registry = &appsv1alpha1.RegistrySpec{Mirror: &mirror}
+	desired := renderImageStream(t, k8sClient, s, cr, "amp-backend")
+
+	r := &ReconcileAPIManager{client: k8sClient, apiClientReader: k8sClient, scheme: s, reqLogger: logf.Log, recorder: record.NewFakeRecorder(10)}
+	if err := r.reconcileObject(cr, desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current := &imagev1.ImageStream{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "amp-backend", Namespace: namespace}, current); err != nil {
+		t.Fatal(err)
+	}
+	tags := map[string]*v1.ObjectReference{}
+	for _, tag := range current.Spec.Tags {
+		tags[tag.Name] = tag.From
+	}
+	release := string(product.ProductRelease_2_5)
+	if from := tags[release]; from == nil || !strings.HasPrefix(from.Name, mirror) {
+		t.Errorf("tag %s not imported from the mirror: %+v", release, from)
+	}
+	if from := tags["latest"]; from == nil || from.Kind != "ImageStreamTag" || from.Name != release {
+		t.Errorf("tag latest changed: %+v", from)
+	}
+	if from := tags["custom"]; from == nil || from.Name != "registry.example.com/backend:custom" {
+		t.Errorf("user tag changed: %+v", from)
+	}
+}
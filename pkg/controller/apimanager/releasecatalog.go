@@ -0,0 +1,82 @@
+package apimanager
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReleaseCatalogConfigMapEnvVar is the name of the ConfigMap, in the
+	// operator namespace, whose 'catalog.yaml' key overrides releases of the
+	// built-in release catalog
+	ReleaseCatalogConfigMapEnvVar = "RELEASE_CATALOG_CONFIGMAP"
+	ReleaseCatalogConfigMapKey    = "catalog.yaml"
+)
+
+// ReleaseCatalogConfigMap returns the release catalog ConfigMap referenced by
+// the RELEASE_CATALOG_CONFIGMAP environment variable, nil when it is not set
+func ReleaseCatalogConfigMap(watchNamespaces []string) (*types.NamespacedName, error) {
+	configMapName := os.Getenv(ReleaseCatalogConfigMapEnvVar)
+	if configMapName == "" {
+		return nil, nil
+	}
+
+	namespace, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		// Running outside of a cluster
+		if len(watchNamespaces) != 1 {
+			return nil, fmt.Errorf("the operator namespace is unknown outside of a cluster unless a single namespace is watched")
+		}
+		namespace = watchNamespaces[0]
+	}
+	return &types.NamespacedName{Name: configMapName, Namespace: namespace}, nil
+}
+
+// ReadReleaseCatalog returns the catalog data of the release catalog ConfigMap
+func ReadReleaseCatalog(c client.Reader, nn types.NamespacedName) (string, error) {
+	configMap := &v1.ConfigMap{}
+	err := c.Get(context.TODO(), nn, configMap)
+	if err != nil {
+		return "", err
+	}
+	data, ok := configMap.Data[ReleaseCatalogConfigMapKey]
+	if !ok {
+		return "", fmt.Errorf("ConfigMap %s has no '%s' key", nn, ReleaseCatalogConfigMapKey)
+	}
+	return data, nil
+}
+
+// reconcileReleaseCatalog reloads the release catalog ConfigMap when its
+// catalog changes, so new releases are available without restarting the
+// operator. An invalid catalog is reported once and the current one is kept
+func (r *ReconcileAPIManager) reconcileReleaseCatalog(cr *appsv1alpha1.APIManager) {
+	if r.releaseCatalog == nil {
+		return
+	}
+
+	data, err := ReadReleaseCatalog(r.apiClientReader, *r.releaseCatalog)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to read the release catalog. Keeping the current one")
+		return
+	}
+	if data == r.releaseCatalogData {
+		return
+	}
+	r.releaseCatalogData = data
+
+	err = product.LoadCatalogOverride([]byte(data))
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to reload the release catalog. Keeping the current one")
+		r.recorder.Eventf(cr, v1.EventTypeWarning, EventReasonInvalidReleaseCatalog, "Error reloading the release catalog ConfigMap %s: %v", r.releaseCatalog, err)
+		return
+	}
+	r.reqLogger.Info(fmt.Sprintf("Loaded release catalog ConfigMap %s", r.releaseCatalog))
+}
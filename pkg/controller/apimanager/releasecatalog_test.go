@@ -0,0 +1,90 @@
+package apimanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+const testReleaseCatalog = `
+releases:
+- version: "2.5.1"
+  upgradesFrom:
+  - "2.5"
+  features:
+    productized: true
+  images:
+    apicast: registry.example.com/3scale-amp25/apicast-gateway
+    backend: registry.example.com/3scale-amp25/backend
+    backendRedis: registry.example.com/rhscl/redis-32-rhel7:3.2
+    system: registry.example.com/3scale-amp25/system
+    systemRedis: registry.example.com/rhscl/redis-32-rhel7:3.2
+    systemMySQL: registry.example.com/rhscl/mysql-57-rhel7:5.7
+    systemPostgreSQL: registry.example.com/rhscl/postgresql-10-rhel7
+    systemMemcached: registry.example.com/3scale-amp20/memcached
+    wildcardRouter: registry.example.com/3scale-amp22/wildcard-router
+    zync: registry.example.com/3scale-amp25/zync
+    zyncPostgreSQL: registry.example.com/rhscl/postgresql-10-rhel7
+`
+
+func TestReconcileReleaseCatalog(t *testing.T) {
+	namespace := "operator-unittest"
+	// Restore the built-in catalog
+	defer product.LoadCatalogOverride([]byte(`releases: []`))
+
+	cr := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+	}
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "release-catalog", Namespace: namespace},
+		Data:       map[string]string{ReleaseCatalogConfigMapKey: `releases: []`},
+	}
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, configMap)
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileAPIManager{
+		client: k8sClient, apiClientReader: k8sClient, scheme: s, reqLogger: logf.Log, recorder: recorder,
+		releaseCatalog: &types.NamespacedName{Name: configMap.Name, Namespace: namespace},
+	}
+
+	r.reconcileReleaseCatalog(cr)
+	if _, err := product.GetRelease("2.5.1"); err == nil {
+		t.Fatal("expected release 2.5.1 not to be in the catalog")
+	}
+
+	// A change of the ConfigMap is loaded without restarting the operator
+	configMap.Data[ReleaseCatalogConfigMapKey] = testReleaseCatalog
+	if err := k8sClient.Update(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	r.reconcileReleaseCatalog(cr)
+	if _, err := product.GetRelease("2.5.1"); err != nil {
+		t.Fatalf("expected release 2.5.1 to be loaded: %v", err)
+	}
+
+	// An invalid catalog is reported once and the current one kept
+	configMap.Data[ReleaseCatalogConfigMapKey] = `releases: [`
+	if err := k8sClient.Update(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	r.reconcileReleaseCatalog(cr)
+	r.reconcileReleaseCatalog(cr)
+	if _, err := product.GetRelease("2.5.1"); err != nil {
+		t.Fatalf("expected release 2.5.1 to be kept: %v", err)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(recorder.Events))
+	}
+}
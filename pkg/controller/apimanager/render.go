@@ -0,0 +1,117 @@
+package apimanager
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RenderAPIManagerObjects returns the objects the operator would create for
+// the given APIManager, in the order it creates them, with their namespace,
+// owner reference and the config hash of the DeploymentConfigs set. The
+// Secrets read by the APIManager are looked up with the given client, so it
+// can be a fake client holding local Secrets. The defaults of the APIManager
+// have to be set before calling it
+func RenderAPIManagerObjects(c client.Client, scheme *runtime.Scheme, cr *appsv1alpha1.APIManager) ([]runtime.RawExtension, error) {
+	r := &ReconcileAPIManager{
+		client:    c,
+		scheme:    scheme,
+		reqLogger: log.WithValues("Request.Namespace", cr.Namespace, "Request.Name", cr.Name),
+	}
+
+	objs, err := r.apiManagerObjects(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.setAPIManagerOwnership(cr, objs)
+	if err != nil {
+		return nil, err
+	}
+
+	objs = creationOrder(objs)
+
+	// The config hash is computed from the rendered Secrets and ConfigMaps,
+	// as the operator computes it once they are created
+	r.apiClientReader = newRenderedConfigReader(c, objs)
+	for idx := range objs {
+		if dc, ok := objs[idx].Object.(*appsv1.DeploymentConfig); ok {
+			if _, err := r.setConfigHash(dc); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return objs, nil
+}
+
+// creationOrder returns the objects in the order the operator creates them:
+// the DeploymentConfigs last, by rollout phase
+func creationOrder(objs []runtime.RawExtension) []runtime.RawExtension {
+	result := []runtime.RawExtension{}
+	for idx := range objs {
+		if _, ok := objs[idx].Object.(*appsv1.DeploymentConfig); !ok {
+			result = append(result, objs[idx])
+		}
+	}
+	for _, phase := range rolloutPhases(objs) {
+		for _, dc := range phase.deploymentConfigs {
+			result = append(result, runtime.RawExtension{Object: dc})
+		}
+	}
+	return result
+}
+
+// renderedConfigReader reads the rendered Secrets and ConfigMaps, as stored by
+// the API server, and the other objects from the embedded Reader
+type renderedConfigReader struct {
+	client.Reader
+	secrets    map[types.NamespacedName]*v1.Secret
+	configMaps map[types.NamespacedName]*v1.ConfigMap
+}
+
+func newRenderedConfigReader(c client.Reader, objs []runtime.RawExtension) *renderedConfigReader {
+	reader := &renderedConfigReader{
+		Reader:     c,
+		secrets:    map[types.NamespacedName]*v1.Secret{},
+		configMaps: map[types.NamespacedName]*v1.ConfigMap{},
+	}
+	for idx := range objs {
+		switch obj := objs[idx].Object.(type) {
+		case *v1.Secret:
+			secret := obj.DeepCopy()
+			if secret.Data == nil {
+				secret.Data = map[string][]byte{}
+			}
+			for key, value := range secretStringDataToData(secret.StringData) {
+				secret.Data[key] = value
+			}
+			secret.StringData = nil
+			reader.secrets[types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}] = secret
+		case *v1.ConfigMap:
+			reader.configMaps[types.NamespacedName{Name: obj.Name, Namespace: obj.Namespace}] = obj
+		}
+	}
+	return reader
+}
+
+func (r *renderedConfigReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	switch o := obj.(type) {
+	case *v1.Secret:
+		if secret, ok := r.secrets[key]; ok {
+			secret.DeepCopyInto(o)
+			return nil
+		}
+	case *v1.ConfigMap:
+		if configMap, ok := r.configMaps[key]; ok {
+			configMap.DeepCopyInto(o)
+			return nil
+		}
+	}
+	return r.Reader.Get(ctx, key, obj)
+}
@@ -0,0 +1,134 @@
+package apimanager
+
+import (
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRenderAPIManagerObjects(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "operator-test"
+	cr := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				ProductVersion: product.ProductUpstream,
+				WildcardDomain: "example.com",
+			},
+		},
+	}
+	if _, err := cr.SetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	seed := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-seed", Namespace: namespace},
+		Data:       map[string][]byte{"MASTER_PASSWORD": []byte("masterpasswd")},
+	}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, seed)
+
+	objs, err := RenderAPIManagerObjects(k8sClient, s, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var renderedSeed *v1.Secret
+	for idx := range objs {
+		objectMeta := objs[idx].Object.(metav1.Object)
+		if objectMeta.GetNamespace() != namespace {
+			t.Errorf("%s: expected namespace %s, got %s", objectMeta.GetName(), namespace, objectMeta.GetNamespace())
+		}
+		ownerRef := metav1.GetControllerOf(objectMeta)
+		if ownerRef == nil || ownerRef.Kind != "APIManager" || ownerRef.Name != cr.Name {
+			t.Errorf("%s: expected APIManager %s as controller, got %v", objectMeta.GetName(), cr.Name, ownerRef)
+		}
+		if secret, ok := objs[idx].Object.(*v1.Secret); ok && secret.Name == seed.Name {
+			renderedSeed = secret
+		}
+	}
+
+	if renderedSeed == nil {
+		t.Fatalf("secret %s not rendered", seed.Name)
+	}
+	if renderedSeed.StringData["MASTER_PASSWORD"] != "masterpasswd" {
+		t.Errorf("expected MASTER_PASSWORD read from the existing secret, got %s", renderedSeed.StringData["MASTER_PASSWORD"])
+	}
+}
+
+func TestRenderAPIManagerObjectsConfigHash(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "operator-test"
+	cr := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				ProductVersion: product.ProductUpstream,
+				WildcardDomain: "example.com",
+			},
+		},
+	}
+	if _, err := cr.SetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+
+	render := func(masterPassword string) map[string]string {
+		seed := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "system-seed", Namespace: namespace},
+			Data:       map[string][]byte{"MASTER_PASSWORD": []byte(masterPassword)},
+		}
+		objs, err := RenderAPIManagerObjects(k8sfake.NewFakeClientWithScheme(s, seed), s, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hashes := map[string]string{}
+		seenDeploymentConfig := false
+		for idx := range objs {
+			dc, ok := objs[idx].Object.(*appsv1.DeploymentConfig)
+			if !ok {
+				if seenDeploymentConfig {
+					t.Errorf("%s rendered after the DeploymentConfigs", objs[idx].Object.(metav1.Object).GetName())
+				}
+				continue
+			}
+			seenDeploymentConfig = true
+			hashes[dc.Name] = dc.Spec.Template.Annotations[ConfigHashAnnotation]
+			if hashes[dc.Name] == "" {
+				t.Errorf("DeploymentConfig %s rendered without config hash", dc.Name)
+			}
+			if dc.Name == "system-app" && hashes["backend-listener"] == "" {
+				t.Error("system-app rendered before the backend rollout phase")
+			}
+		}
+		return hashes
+	}
+
+	first := render("masterpasswd")
+	second := render("changed")
+	if first["system-app"] == second["system-app"] {
+		t.Error("system-app config hash does not cover the system-seed secret")
+	}
+	if first["backend-redis"] != second["backend-redis"] {
+		t.Error("backend-redis config hash changed with a secret it does not read")
+	}
+}
@@ -0,0 +1,98 @@
+package apimanager
+
+import (
+	"fmt"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// rolloutPhaseOrder is the order in which the DeploymentConfigs of the
+// APIManager are rolled out. Each phase depends on the previous ones
+var rolloutPhaseOrder = []appsv1alpha1.APIManagerPhase{
+	appsv1alpha1.APIManagerPhaseDatabases,
+	appsv1alpha1.APIManagerPhaseBackend,
+	appsv1alpha1.APIManagerPhaseSystem,
+	appsv1alpha1.APIManagerPhaseZyncAndApicast,
+}
+
+// deploymentConfigPhases are the rollout phases of the DeploymentConfigs.
+// The ones not listed, like zync and the apicast gateways, are rolled out
+// in the last phase
+var deploymentConfigPhases = map[string]appsv1alpha1.APIManagerPhase{
+	"backend-redis":         appsv1alpha1.APIManagerPhaseDatabases,
+	"backend-redis-replica": appsv1alpha1.APIManagerPhaseDatabases,
+	"system-redis":          appsv1alpha1.APIManagerPhaseDatabases,
+	"system-redis-replica":  appsv1alpha1.APIManagerPhaseDatabases,
+	"redis-sentinel":        appsv1alpha1.APIManagerPhaseDatabases,
+	"system-mysql":          appsv1alpha1.APIManagerPhaseDatabases,
+	"system-postgresql":     appsv1alpha1.APIManagerPhaseDatabases,
+	"system-memcache":       appsv1alpha1.APIManagerPhaseDatabases,
+	"zync-database":         appsv1alpha1.APIManagerPhaseDatabases,
+	"backend-listener":      appsv1alpha1.APIManagerPhaseBackend,
+	"backend-worker":        appsv1alpha1.APIManagerPhaseBackend,
+	"backend-cron":          appsv1alpha1.APIManagerPhaseBackend,
+	"system-app":            appsv1alpha1.APIManagerPhaseSystem,
+	"system-sidekiq":        appsv1alpha1.APIManagerPhaseSystem,
+	"system-sphinx":         appsv1alpha1.APIManagerPhaseSystem,
+}
+
+type rolloutPhase struct {
+	phase             appsv1alpha1.APIManagerPhase
+	deploymentConfigs []*appsv1.DeploymentConfig
+}
+
+// rolloutPhases groups the DeploymentConfigs of the objects by rollout phase,
+// in rollout order. Phases without DeploymentConfigs are omitted
+func rolloutPhases(objs []runtime.RawExtension) []rolloutPhase {
+	dcsByPhase := map[appsv1alpha1.APIManagerPhase][]*appsv1.DeploymentConfig{}
+	for idx := range objs {
+		dc, ok := objs[idx].Object.(*appsv1.DeploymentConfig)
+		if !ok {
+			continue
+		}
+		phase, ok := deploymentConfigPhases[dc.Name]
+		if !ok {
+			phase = rolloutPhaseOrder[len(rolloutPhaseOrder)-1]
+		}
+		dcsByPhase[phase] = append(dcsByPhase[phase], dc)
+	}
+
+	phases := []rolloutPhase{}
+	for _, phase := range rolloutPhaseOrder {
+		if dcs, ok := dcsByPhase[phase]; ok {
+			phases = append(phases, rolloutPhase{phase: phase, deploymentConfigs: dcs})
+		}
+	}
+	return phases
+}
+
+// reconcileRollout reconciles the DeploymentConfigs phase by phase. The
+// DeploymentConfigs of a phase are not reconciled until every DeploymentConfig
+// of the previous phases is available. It returns the phase being waited for,
+// or Completed when every DeploymentConfig is available
+func (r *ReconcileAPIManager) reconcileRollout(cr *appsv1alpha1.APIManager, phases []rolloutPhase) (appsv1alpha1.APIManagerPhase, error) {
+	for _, phase := range phases {
+		phaseAvailable := true
+		for _, dc := range phase.deploymentConfigs {
+			err := r.reconcileObject(cr, dc)
+			if err != nil {
+				return "", err
+			}
+
+			available, err := r.deploymentConfigAvailable(cr.Namespace, dc.Name)
+			if err != nil {
+				return "", err
+			}
+			if !available {
+				phaseAvailable = false
+			}
+		}
+		if !phaseAvailable {
+			r.reqLogger.Info(fmt.Sprintf("Waiting for the DeploymentConfigs of rollout phase %s to be available", phase.phase))
+			return phase.phase, nil
+		}
+	}
+	return appsv1alpha1.APIManagerPhaseCompleted, nil
+}
@@ -0,0 +1,92 @@
+package apimanager
+
+import (
+	"context"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestReconcileRollout(t *testing.T) {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{v1.AddToScheme, appsv1.AddToScheme, appsv1alpha1.SchemeBuilder.AddToScheme} {
+		if err := addToScheme(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	namespace := "operator-test"
+	cr := &appsv1alpha1.APIManager{ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace}}
+	objs := []runtime.RawExtension{}
+	for _, name := range []string{"apicast-production", "system-app", "backend-listener", "backend-redis"} {
+		objs = append(objs, runtime.RawExtension{Object: newTestDeploymentConfig(name, namespace)})
+	}
+
+	phases := rolloutPhases(objs)
+	expectedPhases := []appsv1alpha1.APIManagerPhase{
+		appsv1alpha1.APIManagerPhaseDatabases,
+		appsv1alpha1.APIManagerPhaseBackend,
+		appsv1alpha1.APIManagerPhaseSystem,
+		appsv1alpha1.APIManagerPhaseZyncAndApicast,
+	}
+	if len(phases) != len(expectedPhases) {
+		t.Fatalf("expected %d rollout phases, got %d", len(expectedPhases), len(phases))
+	}
+
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, cr)
+	r := &ReconcileAPIManager{client: k8sClient, apiClientReader: k8sClient, scheme: s, reqLogger: logf.Log, recorder: record.NewFakeRecorder(10)}
+
+	for idx, expectedPhase := range expectedPhases {
+		phase, err := r.reconcileRollout(cr, phases)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if phase != expectedPhase {
+			t.Fatalf("expected rollout phase %s, got %s", expectedPhase, phase)
+		}
+
+		for _, later := range phases[idx+1:] {
+			for _, dc := range later.deploymentConfigs {
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: dc.Name, Namespace: namespace}, &appsv1.DeploymentConfig{})
+				if err == nil {
+					t.Fatalf("DeploymentConfig %s created during rollout phase %s", dc.Name, phase)
+				}
+			}
+		}
+
+		for _, dc := range phases[idx].deploymentConfigs {
+			setDeploymentConfigAvailable(t, k8sClient, namespace, dc.Name)
+		}
+	}
+
+	phase, err := r.reconcileRollout(cr, phases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if phase != appsv1alpha1.APIManagerPhaseCompleted {
+		t.Errorf("expected rollout phase %s, got %s", appsv1alpha1.APIManagerPhaseCompleted, phase)
+	}
+}
+
+func setDeploymentConfigAvailable(t *testing.T, c client.Client, namespace, name string) {
+	dc := &appsv1.DeploymentConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, dc); err != nil {
+		t.Fatal(err)
+	}
+	dc.Status.AvailableReplicas = dc.Spec.Replicas
+	dc.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentAvailable, Status: v1.ConditionTrue},
+	}
+	if err := c.Update(context.TODO(), dc); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,49 @@
+package apimanager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// reconcileRoute updates the host and the TLS settings of the Routes
+// configured in the APIManager, so rotated certificates reach the router.
+// Routes whose configuration was removed from the APIManager are reset to the
+// default host and TLS settings. Other Routes are left as they are
+func (r *ReconcileAPIManager) reconcileRoute(cr *appsv1alpha1.APIManager, desired, current *routev1.Route) error {
+	_, configured := operator.APIManagerRouteSpecs(&cr.Spec)[current.Name]
+	_, wasConfigured := current.Annotations[component.ConfiguredRouteAnnotation]
+	if !configured && !wasConfigured {
+		return nil
+	}
+	_, desiredConfigured := desired.Annotations[component.ConfiguredRouteAnnotation]
+	if desired.Spec.Host == current.Spec.Host && reflect.DeepEqual(desired.Spec.TLS, current.Spec.TLS) && desiredConfigured == wasConfigured {
+		return nil
+	}
+
+	currentCopy := current.DeepCopy()
+	currentCopy.Spec.Host = desired.Spec.Host
+	currentCopy.Spec.TLS = desired.Spec.TLS.DeepCopy()
+	if desiredConfigured {
+		if currentCopy.Annotations == nil {
+			currentCopy.Annotations = map[string]string{}
+		}
+		currentCopy.Annotations[component.ConfiguredRouteAnnotation] = desired.Annotations[component.ConfiguredRouteAnnotation]
+	} else {
+		delete(currentCopy.Annotations, component.ConfiguredRouteAnnotation)
+	}
+
+	r.reqLogger.Info(fmt.Sprintf("Updating host and TLS settings of Route %s", current.Name))
+	err := r.client.Update(context.TODO(), currentCopy)
+	if err != nil {
+		return err
+	}
+	r.recorder.Eventf(cr, v1.EventTypeNormal, EventReasonUpdated, "Updated host and TLS settings of Route %s", current.Name)
+	return nil
+}
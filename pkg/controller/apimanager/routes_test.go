@@ -0,0 +1,127 @@
+package apimanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func newTestRoute(name, namespace, host, certificate string) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: routev1.RouteSpec{
+			Host: host,
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationEdge,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyAllow,
+				Certificate:                   certificate,
+			},
+		},
+	}
+}
+
+func TestReconcileRoute(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := routev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "operator-test"
+	host := "admin.example.com"
+	cr := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			Routes: &appsv1alpha1.RoutesSpec{
+				SystemProvider: &appsv1alpha1.RouteSpec{Host: &host},
+			},
+		},
+	}
+	configured := newTestRoute("system-provider-admin", namespace, "3scale-admin.apps.example.com", "")
+	notConfigured := newTestRoute("system-developer", namespace, "3scale.apps.example.com", "")
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, configured, notConfigured)
+	r := &ReconcileAPIManager{client: k8sClient, scheme: s, reqLogger: logf.Log, recorder: record.NewFakeRecorder(10)}
+
+	cases := []struct {
+		name         string
+		desired      *routev1.Route
+		expectedHost string
+		expectedCert string
+	}{
+		{"system-provider-admin", newTestRoute("system-provider-admin", namespace, host, "rotated"), host, "rotated"},
+		{"system-developer", newTestRoute("system-developer", namespace, "developer.example.com", "rotated"), "3scale.apps.example.com", ""},
+	}
+	for _, tc := range cases {
+		found := &routev1.Route{}
+		if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: tc.name, Namespace: namespace}, found); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.reconcileRoute(cr, tc.desired, found); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: tc.name, Namespace: namespace}, found); err != nil {
+			t.Fatal(err)
+		}
+		if found.Spec.Host != tc.expectedHost {
+			t.Errorf("Route %s: expected host %s, got %s", tc.name, tc.expectedHost, found.Spec.Host)
+		}
+		if found.Spec.TLS.Certificate != tc.expectedCert {
+			t.Errorf("Route %s: expected certificate %q, got %q", tc.name, tc.expectedCert, found.Spec.TLS.Certificate)
+		}
+	}
+}
+
+func TestReconcileRouteRemovedConfiguration(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := routev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "operator-test"
+	// The Route was configured in the APIManager, whose routes field is now removed
+	configured := newTestRoute("system-provider-admin", namespace, "admin.example.com", "custom")
+	configured.Annotations = map[string]string{component.ConfiguredRouteAnnotation: "true"}
+	cr := &appsv1alpha1.APIManager{ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace}}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, configured)
+	r := &ReconcileAPIManager{client: k8sClient, scheme: s, reqLogger: logf.Log, recorder: record.NewFakeRecorder(10)}
+
+	desired := newTestRoute("system-provider-admin", namespace, "3scale-admin.apps.example.com", "")
+	if err := r.reconcileRoute(cr, desired, configured); err != nil {
+		t.Fatal(err)
+	}
+
+	found := &routev1.Route{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "system-provider-admin", Namespace: namespace}, found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Spec.Host != desired.Spec.Host || found.Spec.TLS.Certificate != "" {
+		t.Errorf("Route not reset to the defaults: host %s, certificate %q", found.Spec.Host, found.Spec.TLS.Certificate)
+	}
+	if _, ok := found.Annotations[component.ConfiguredRouteAnnotation]; ok {
+		t.Errorf("annotation %s not removed", component.ConfiguredRouteAnnotation)
+	}
+
+	// Once reset, the Route is not reconciled anymore
+	found.Spec.Host = "manual.example.com"
+	if err := k8sClient.Update(context.TODO(), found); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reconcileRoute(cr, desired, found); err != nil {
+		t.Fatal(err)
+	}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "system-provider-admin", Namespace: namespace}, found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Spec.Host != "manual.example.com" {
+		t.Errorf("Route not configured in the APIManager updated to %s", found.Spec.Host)
+	}
+}
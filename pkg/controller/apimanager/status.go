@@ -0,0 +1,73 @@
+package apimanager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileStatus sets the APIManager Ready and Progressing conditions
+// from the availability of its DeploymentConfigs, the current rollout
+// phase and the reconciled release. It returns whether the APIManager is ready
+func (r *ReconcileAPIManager) reconcileStatus(cr *appsv1alpha1.APIManager, objs []runtime.RawExtension, phase appsv1alpha1.APIManagerPhase) (bool, error) {
+	ready := true
+	for idx := range objs {
+		dc, ok := objs[idx].Object.(*appsv1.DeploymentConfig)
+		if !ok {
+			continue
+		}
+
+		available, err := r.deploymentConfigAvailable(cr.Namespace, dc.Name)
+		if err != nil {
+			return false, err
+		}
+		if !available {
+			r.reqLogger.Info(fmt.Sprintf("DeploymentConfig %s not available yet", dc.Name))
+			ready = false
+		}
+	}
+
+	readyStatus, progressingStatus := v1.ConditionTrue, v1.ConditionFalse
+	if !ready {
+		readyStatus, progressingStatus = v1.ConditionFalse, v1.ConditionTrue
+	}
+
+	status := cr.Status.DeepCopy()
+	status.Conditions = []appsv1alpha1.APIManagerCondition{
+		{Type: appsv1alpha1.APIManagerReady, Status: readyStatus},
+		{Type: appsv1alpha1.APIManagerProgressing, Status: progressingStatus},
+	}
+	status.Phase = phase
+	status.ProductVersion = cr.Spec.ProductVersion
+
+	// don't update the status if there aren't any changes.
+	if reflect.DeepEqual(cr.Status, *status) {
+		return ready, nil
+	}
+	cr.Status = *status
+	return ready, r.client.Status().Update(context.TODO(), cr)
+}
+
+func (r *ReconcileAPIManager) deploymentConfigAvailable(namespace, name string) (bool, error) {
+	dc := &appsv1.DeploymentConfig{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, dc)
+	if err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, condition := range dc.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			return condition.Status == v1.ConditionTrue && dc.Status.AvailableReplicas >= dc.Spec.Replicas, nil
+		}
+	}
+	return false, nil
+}
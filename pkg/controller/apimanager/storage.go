@@ -0,0 +1,64 @@
+package apimanager
+
+import (
+	"context"
+	"fmt"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ExpansionSkippedAnnotation records on a PersistentVolumeClaim the size it
+// could not be expanded to, so the expansion is not retried, and the
+// ExpansionSkipped event not emitted again, until the desired size changes
+const ExpansionSkippedAnnotation = "apps.3scale.net/expansion-skipped"
+
+// reconcilePersistentVolumeClaim expands the existing claim when the desired
+// size is larger than the requested one. The other fields of a claim cannot
+// be changed once created, and claims are never shrunk
+func (r *ReconcileAPIManager) reconcilePersistentVolumeClaim(cr *appsv1alpha1.APIManager, desired, current *v1.PersistentVolumeClaim) error {
+	desiredSize, ok := desired.Spec.Resources.Requests[v1.ResourceStorage]
+	if !ok {
+		return nil
+	}
+	currentSize := current.Spec.Resources.Requests[v1.ResourceStorage]
+	if desiredSize.Cmp(currentSize) <= 0 {
+		return nil
+	}
+	if current.Annotations[ExpansionSkippedAnnotation] == desiredSize.String() {
+		return nil
+	}
+
+	currentCopy := current.DeepCopy()
+	if currentCopy.Spec.Resources.Requests == nil {
+		currentCopy.Spec.Resources.Requests = v1.ResourceList{}
+	}
+	currentCopy.Spec.Resources.Requests[v1.ResourceStorage] = desiredSize
+	delete(currentCopy.Annotations, ExpansionSkippedAnnotation)
+
+	r.reqLogger.Info(fmt.Sprintf("Expanding PersistentVolumeClaim %s from %s to %s", current.Name, currentSize.String(), desiredSize.String()))
+	err := r.client.Update(context.TODO(), currentCopy)
+	if err != nil {
+		// The API rejects the expansion when the storage class of the
+		// claim does not allow it
+		if errors.IsForbidden(err) || errors.IsInvalid(err) {
+			r.recorder.Eventf(cr, v1.EventTypeWarning, EventReasonExpansionSkipped, "PersistentVolumeClaim %s cannot be expanded to %s: %v", current.Name, desiredSize.String(), err)
+			return r.recordSkippedExpansion(current, desiredSize.String())
+		}
+		return err
+	}
+	r.recorder.Eventf(cr, v1.EventTypeNormal, EventReasonUpdated, "Expanding PersistentVolumeClaim %s from %s to %s", current.Name, currentSize.String(), desiredSize.String())
+	return nil
+}
+
+// recordSkippedExpansion sets the size the claim could not be expanded to in
+// its ExpansionSkippedAnnotation
+func (r *ReconcileAPIManager) recordSkippedExpansion(current *v1.PersistentVolumeClaim, size string) error {
+	currentCopy := current.DeepCopy()
+	if currentCopy.Annotations == nil {
+		currentCopy.Annotations = map[string]string{}
+	}
+	currentCopy.Annotations[ExpansionSkippedAnnotation] = size
+	return r.client.Update(context.TODO(), currentCopy)
+}
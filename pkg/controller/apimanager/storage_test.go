@@ -0,0 +1,131 @@
+package apimanager
+
+import (
+	"context"
+	goerrors "errors"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func newTestPVC(name, namespace, size string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+	}
+}
+
+func TestReconcilePersistentVolumeClaim(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "operator-test"
+	cr := &appsv1alpha1.APIManager{ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace}}
+	current := newTestPVC("mysql-storage", namespace, "1Gi")
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, current)
+	r := &ReconcileAPIManager{client: k8sClient, scheme: s, reqLogger: logf.Log, recorder: record.NewFakeRecorder(10)}
+
+	cases := []struct {
+		desiredSize  string
+		expectedSize string
+	}{
+		{"1Gi", "1Gi"},
+		{"512Mi", "1Gi"},
+		{"5Gi", "5Gi"},
+	}
+	for _, tc := range cases {
+		found := &v1.PersistentVolumeClaim{}
+		if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: current.Name, Namespace: namespace}, found); err != nil {
+			t.Fatal(err)
+		}
+		err := r.reconcilePersistentVolumeClaim(cr, newTestPVC(current.Name, namespace, tc.desiredSize), found)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: current.Name, Namespace: namespace}, found); err != nil {
+			t.Fatal(err)
+		}
+		size := found.Spec.Resources.Requests[v1.ResourceStorage]
+		if size.Cmp(resource.MustParse(tc.expectedSize)) != 0 {
+			t.Errorf("desired size %s: expected claim size %s, got %s", tc.desiredSize, tc.expectedSize, size.String())
+		}
+	}
+}
+
+// nonExpandableClient rejects the expansion of PersistentVolumeClaims, like
+// the API server does when their storage class does not allow it
+type nonExpandableClient struct {
+	client.Client
+}
+
+func (c nonExpandableClient) Update(ctx context.Context, obj runtime.Object) error {
+	if pvc, ok := obj.(*v1.PersistentVolumeClaim); ok {
+		current := &v1.PersistentVolumeClaim{}
+		if err := c.Client.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, current); err != nil {
+			return err
+		}
+		size := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+		if size.Cmp(current.Spec.Resources.Requests[v1.ResourceStorage]) != 0 {
+			return errors.NewForbidden(v1.Resource("persistentvolumeclaims"), pvc.Name, goerrors.New("storage class does not allow volume expansion"))
+		}
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+func TestReconcilePersistentVolumeClaimExpansionSkipped(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "operator-test"
+	cr := &appsv1alpha1.APIManager{ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace}}
+	current := newTestPVC("mysql-storage", namespace, "1Gi")
+	k8sClient := nonExpandableClient{Client: k8sfake.NewFakeClientWithScheme(s, current)}
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileAPIManager{client: k8sClient, scheme: s, reqLogger: logf.Log, recorder: recorder}
+
+	// The skipped expansion is only reported once per requested size
+	for _, desiredSize := range []string{"5Gi", "5Gi", "10Gi", "10Gi"} {
+		found := &v1.PersistentVolumeClaim{}
+		if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: current.Name, Namespace: namespace}, found); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.reconcilePersistentVolumeClaim(cr, newTestPVC(current.Name, namespace, desiredSize), found); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(recorder.Events) != 2 {
+		t.Errorf("expected an ExpansionSkipped event per requested size, got %d events", len(recorder.Events))
+	}
+	found := &v1.PersistentVolumeClaim{}
+	if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: current.Name, Namespace: namespace}, found); err != nil {
+		t.Fatal(err)
+	}
+	if value := found.Annotations[ExpansionSkippedAnnotation]; value != "10Gi" {
+		t.Errorf("expansion skipped annotation = %s, expected 10Gi", value)
+	}
+	size := found.Spec.Resources.Requests[v1.ResourceStorage]
+	if size.Cmp(resource.MustParse("1Gi")) != 0 {
+		t.Errorf("claim size changed to %s", size.String())
+	}
+}
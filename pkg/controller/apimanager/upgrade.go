@@ -0,0 +1,31 @@
+package apimanager
+
+import (
+	"context"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// deployedProductVersion returns the release the APIManager objects were
+// reconciled to. APIManagers reconciled before the release was recorded in
+// the status use the AMP_RELEASE of the system-environment ConfigMap. It is
+// empty when nothing has been deployed yet
+func (r *ReconcileAPIManager) deployedProductVersion(cr *appsv1alpha1.APIManager) (product.Version, error) {
+	if cr.Status.ProductVersion != "" {
+		return cr.Status.ProductVersion, nil
+	}
+
+	configMap := &v1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "system-environment", Namespace: cr.Namespace}, configMap)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return product.Version(configMap.Data["AMP_RELEASE"]), nil
+}
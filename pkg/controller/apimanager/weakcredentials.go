@@ -0,0 +1,103 @@
+package apimanager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// generatedCredential is a secret field generated by the operator when not provided by the user
+type generatedCredential struct {
+	secretName     string
+	key            string
+	credentialType appsv1alpha1.CredentialType
+	// rotation is the credential to rotate to regenerate the field.
+	// Empty when the field cannot be rotated
+	rotation appsv1alpha1.CredentialName
+}
+
+var generatedCredentials = []generatedCredential{
+	{component.BackendSecretInternalApiSecretName, component.BackendSecretInternalApiPasswordFieldName, appsv1alpha1.PasswordCredentialType, appsv1alpha1.BackendInternalAPIPasswordCredential},
+	{component.SystemSecretSystemEventsHookSecretName, component.SystemSecretSystemEventsHookPasswordFieldName, appsv1alpha1.SharedSecretCredentialType, appsv1alpha1.BackendSharedSecretCredential},
+	{component.SystemSecretSystemAppSecretName, component.SystemSecretSystemAppSecretKeyBaseFieldName, appsv1alpha1.SecretKeyBaseCredentialType, appsv1alpha1.AppSecretKeyBaseCredential},
+	{component.SystemSecretSystemSeedSecretName, component.SystemSecretSystemSeedMasterPasswordFieldName, appsv1alpha1.PasswordCredentialType, ""},
+	{component.SystemSecretSystemSeedSecretName, component.SystemSecretSystemSeedAdminPasswordFieldName, appsv1alpha1.PasswordCredentialType, ""},
+	{component.SystemSecretSystemSeedSecretName, component.SystemSecretSystemSeedMasterAccessTokenFieldName, appsv1alpha1.AccessTokenCredentialType, appsv1alpha1.MasterAccessTokenCredential},
+	{component.SystemSecretSystemSeedSecretName, component.SystemSecretSystemSeedAdminAccessTokenFieldName, appsv1alpha1.AccessTokenCredentialType, appsv1alpha1.AdminAccessTokenCredential},
+	{component.SystemSecretSystemMasterApicastSecretName, component.SystemSecretSystemMasterApicastAccessToken, appsv1alpha1.AccessTokenCredentialType, ""},
+	{component.SystemSecretSystemDatabaseSecretName, component.SystemSecretSystemDatabasePasswordFieldName, appsv1alpha1.PasswordCredentialType, ""},
+	{component.SystemSecretSystemDatabaseSecretName, component.SystemSecretSystemDatabaseRootPasswordFieldName, appsv1alpha1.PasswordCredentialType, ""},
+	{component.ZyncSecretName, component.ZyncSecretKeyBaseFieldName, appsv1alpha1.SecretKeyBaseCredentialType, ""},
+	{component.ZyncSecretName, component.ZyncSecretDatabasePasswordFieldName, appsv1alpha1.PasswordCredentialType, ""},
+	{component.ZyncSecretName, component.ZyncSecretAuthenticationTokenFieldName, appsv1alpha1.AccessTokenCredentialType, appsv1alpha1.ZyncAuthenticationTokenCredential},
+}
+
+// reconcileWeakCredentials records in the status the generated credentials whose value does not
+// meet the minimum entropy of their policy. When spec.credentialPolicies.regenerateWeakCredentials
+// is set, the rotation of the weak rotatable credentials is requested
+func (r *ReconcileAPIManager) reconcileWeakCredentials(cr *appsv1alpha1.APIManager) error {
+	weak := []string{}
+	rotations := []string{}
+	secrets := map[string]*v1.Secret{}
+	for _, credential := range generatedCredentials {
+		secret, ok := secrets[credential.secretName]
+		if !ok {
+			secret = &v1.Secret{}
+			err := r.client.Get(context.TODO(), types.NamespacedName{Name: credential.secretName, Namespace: cr.Namespace}, secret)
+			if err != nil && errors.IsNotFound(err) {
+				secret = nil
+			} else if err != nil {
+				return err
+			}
+			secrets[credential.secretName] = secret
+		}
+		if secret == nil {
+			continue
+		}
+
+		value, ok := secret.Data[credential.key]
+		if !ok || !cr.Spec.CredentialPolicy(credential.credentialType).Weak(string(value)) {
+			continue
+		}
+		weak = append(weak, fmt.Sprintf("%s/%s", credential.secretName, credential.key))
+		if credential.rotation != "" {
+			rotations = append(rotations, string(credential.rotation))
+		}
+	}
+
+	if len(weak) == 0 {
+		weak = nil
+	}
+	if !reflect.DeepEqual(cr.Status.WeakCredentials, weak) {
+		if len(weak) > 0 {
+			r.recorder.Eventf(cr, v1.EventTypeWarning, EventReasonWeakCredentials, "Credentials not meeting the credential policy: %s", strings.Join(weak, ", "))
+		}
+		cr.Status.WeakCredentials = weak
+		err := r.client.Status().Update(context.TODO(), cr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.CredentialPolicies == nil || !cr.Spec.CredentialPolicies.RegenerateWeakCredentials || len(rotations) == 0 {
+		return nil
+	}
+	if _, ok := cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation]; ok {
+		// Wait for the requested rotations to complete
+		return nil
+	}
+
+	r.reqLogger.Info(fmt.Sprintf("Requesting rotation of weak credentials %s", strings.Join(rotations, ",")))
+	if cr.Annotations == nil {
+		cr.Annotations = map[string]string{}
+	}
+	cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation] = strings.Join(rotations, ",")
+	return r.client.Update(context.TODO(), cr)
+}
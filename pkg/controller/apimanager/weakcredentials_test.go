@@ -0,0 +1,102 @@
+package apimanager
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta/fake"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newWeakCredentialsFixture returns a zync secret with a weak, rotatable, authentication token
+// and a system-seed secret with a weak master password, which cannot be rotated
+func newWeakCredentialsFixture() []*v1.Secret {
+	return []*v1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: component.ZyncSecretName, Namespace: rotationTestNamespace},
+			Data: map[string][]byte{
+				component.ZyncSecretAuthenticationTokenFieldName: []byte("zync"),
+				component.ZyncSecretDatabasePasswordFieldName:    []byte("Xk9vQ2pLm7RtZw4B"),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: component.SystemSecretSystemSeedSecretName, Namespace: rotationTestNamespace},
+			Data: map[string][]byte{
+				component.SystemSecretSystemSeedMasterPasswordFieldName: []byte("password"),
+			},
+		},
+	}
+}
+
+func TestReconcileWeakCredentialsDetection(t *testing.T) {
+	cr := newRotationTestAPIManager("")
+	delete(cr.Annotations, appsv1alpha1.RotateCredentialsAnnotation)
+	secrets := newWeakCredentialsFixture()
+	r, _, _ := newRotationTestReconciler(t, fake.NewAdminPortal().ClientFactory(), nil, cr, secrets[0], secrets[1])
+
+	if err := r.reconcileWeakCredentials(cr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"system-seed/MASTER_PASSWORD",
+		"zync/ZYNC_AUTHENTICATION_TOKEN",
+	}
+	if !reflect.DeepEqual(cr.Status.WeakCredentials, expected) {
+		t.Errorf("weak credentials = %v, expected %v", cr.Status.WeakCredentials, expected)
+	}
+	if _, ok := cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation]; ok {
+		t.Errorf("rotation requested without regenerateWeakCredentials: %v", cr.Annotations)
+	}
+}
+
+func TestReconcileWeakCredentialsReplacement(t *testing.T) {
+	cr := newRotationTestAPIManager("")
+	delete(cr.Annotations, appsv1alpha1.RotateCredentialsAnnotation)
+	cr.Spec.CredentialPolicies = &appsv1alpha1.CredentialPoliciesSpec{RegenerateWeakCredentials: true}
+	secrets := newWeakCredentialsFixture()
+	zyncToken := secretEnvVar("ZYNC_AUTHENTICATION_TOKEN", component.ZyncSecretName, component.ZyncSecretAuthenticationTokenFieldName)
+	dcs := []*appsv1.DeploymentConfig{newReplicatedDeploymentConfig("zync", zyncToken)}
+	r, k8sClient, objs := newRotationTestReconciler(t, fake.NewAdminPortal().ClientFactory(), dcs, cr, secrets[0], secrets[1])
+
+	if err := r.reconcileWeakCredentials(cr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value := cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation]; value != string(appsv1alpha1.ZyncAuthenticationTokenCredential) {
+		t.Fatalf("rotation annotation = %q, expected only the rotatable weak credential", value)
+	}
+
+	// The requested rotation replaces the weak value and rolls out its consumer
+	reconcileRotation(t, r, cr, objs)
+	reconcileRotation(t, r, cr, objs)
+	markRolledOut(t, k8sClient, "zync")
+	reconcileRotation(t, r, cr, objs)
+	if rotation := cr.Status.CredentialRotationInProgress(); rotation != nil {
+		t.Fatalf("rotation not completed: %+v", rotation)
+	}
+	zyncNN := types.NamespacedName{Name: component.ZyncSecretName, Namespace: rotationTestNamespace}
+	value := secretValue(t, k8sClient, zyncNN, component.ZyncSecretAuthenticationTokenFieldName)
+	if cr.Spec.CredentialPolicy(appsv1alpha1.AccessTokenCredentialType).Weak(value) {
+		t.Errorf("zync authentication token not replaced with a strong value: %s", value)
+	}
+	if value := secretValue(t, k8sClient, zyncNN, component.ZyncSecretDatabasePasswordFieldName); value != "Xk9vQ2pLm7RtZw4B" {
+		t.Errorf("strong zync database password changed to %s", value)
+	}
+
+	// Only the weak credential that cannot be rotated is reported, and no
+	// further rotation is requested
+	if err := r.reconcileWeakCredentials(cr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"system-seed/MASTER_PASSWORD"}; !reflect.DeepEqual(cr.Status.WeakCredentials, expected) {
+		t.Errorf("weak credentials = %v, expected %v", cr.Status.WeakCredentials, expected)
+	}
+	if _, ok := cr.Annotations[appsv1alpha1.RotateCredentialsAnnotation]; ok {
+		t.Errorf("unexpected rotation request: %v", cr.Annotations)
+	}
+}
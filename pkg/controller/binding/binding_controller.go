@@ -2,12 +2,17 @@ package binding
 
 import (
 	"context"
+	"fmt"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
 	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/metrics"
 	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -20,6 +25,22 @@ import (
 
 var log = logf.Log.WithName("controller_binding")
 
+// Reasons of the events emitted on the Binding resource
+const (
+	EventReasonAPICreated     = "APICreated"
+	EventReasonAPIUpdated     = "APIUpdated"
+	EventReasonAPIDeleted     = "APIDeleted"
+	EventReasonAPIDeleteError = "APIDeleteFailed"
+	EventReasonSyncFailed     = "SyncFailed"
+	EventReasonCleanUpFailed  = "CleanUpFailed"
+	EventReasonTenantNotReady = "TenantNotReady"
+)
+
+// nonBindingRequestName is the name of the requests triggered by objects other
+// than Bindings. They reconcile all the Bindings of the request namespace, as
+// Bindings only select APIs and reference Tenants of their own namespace
+const nonBindingRequestName = "_NonBinding"
+
 func Add(mgr manager.Manager) error {
 	return add(mgr, newReconciler(mgr))
 }
@@ -32,22 +53,30 @@ func (r NonBindingTrigger) Map(o handler.MapObject) []reconcile.Request {
 
 // newReconciler returns a new reconcile.
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileBinding{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileBinding{
+		client:             mgr.GetClient(),
+		scheme:             mgr.GetScheme(),
+		recorder:           mgr.GetRecorder("binding-controller"),
+		portaClientFactory: helper.PortaClientFromURLString,
+	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	var NonBindingTriggerFunc NonBindingTrigger = func(o handler.MapObject) []reconcile.Request {
+		if o.Meta.GetNamespace() == "" {
+			return nil
+		}
 		return []reconcile.Request{
 			{NamespacedName: types.NamespacedName{
 				Namespace: o.Meta.GetNamespace(),
-				Name:      "_NonBinding",
+				Name:      nonBindingRequestName,
 			}},
 		}
 	}
 	// Create a new controller
-	c, err := controller.New("binding-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("binding-controller", mgr, controller.Options{Reconciler: metrics.NewInstrumentedReconciler("binding-controller", r)})
 	if err != nil {
 		return err
 	}
@@ -77,6 +106,10 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
+	err = c.Watch(&source.Kind{Type: &apiv1alpha1.Tenant{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: NonBindingTriggerFunc})
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -88,8 +121,10 @@ var _ reconcile.Reconciler = &ReconcileBinding{}
 type ReconcileBinding struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client client.Client
-	scheme *runtime.Scheme
+	client             client.Client
+	scheme             *runtime.Scheme
+	recorder           record.EventRecorder
+	portaClientFactory porta.ClientFactory
 }
 
 func (r *ReconcileBinding) Reconcile(request reconcile.Request) (reconcile.Result, error) {
@@ -98,7 +133,11 @@ func (r *ReconcileBinding) Reconcile(request reconcile.Request) (reconcile.Resul
 	// If the trigger comes from an object different from a Binding, we will get
 	// all the binding object from the same namespace and reconcile them.
 	// This is a hack. but we don't have owner references, so it should work.
-	if request.Name == "_NonBinding" {
+	if request.Name == nonBindingRequestName {
+		if request.Namespace == "" {
+			// An empty namespace would list the Bindings of all namespaces
+			return reconcile.Result{}, nil
+		}
 		opts := client.ListOptions{}
 		opts.InNamespace(request.Namespace)
 		BindingList := &apiv1alpha1.BindingList{}
@@ -109,7 +148,7 @@ func (r *ReconcileBinding) Reconcile(request reconcile.Request) (reconcile.Resul
 		}
 
 		for _, binding := range BindingList.Items {
-			_, err := ReconcileBindingFunc(binding, r.client, reqLogger)
+			_, err := ReconcileBindingFunc(binding, r.client, r.portaClientFactory, r.recorder, reqLogger)
 			if err != nil {
 				reqLogger.Error(err, "error")
 			}
@@ -128,12 +167,12 @@ func (r *ReconcileBinding) Reconcile(request reconcile.Request) (reconcile.Resul
 			reqLogger.Error(err, "error")
 			return reconcile.Result{Requeue: true}, err
 		}
-		return ReconcileBindingFunc(*binding, r.client, reqLogger)
+		return ReconcileBindingFunc(*binding, r.client, r.portaClientFactory, r.recorder, reqLogger)
 
 	}
 }
 
-func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr.Logger) (reconcile.Result, error) {
+func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, newPortaClient porta.ClientFactory, recorder record.EventRecorder, log logr.Logger) (reconcile.Result, error) {
 
 	// UpdateRequired controls whether if we need to update the status of the object or not
 	UpdateRequired := false
@@ -142,10 +181,12 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 	if binding.HasFinalizer() {
 		if binding.IsTerminating() {
 			log.Info("Binding is terminating, cleaning up.", binding.Name, binding.Namespace)
-			err := binding.CleanUp(c)
+			err := binding.CleanUp(c, newPortaClient)
 			if err != nil {
 				log.Info("Clean up for Binding failed.", binding.Name, binding.Namespace)
+				recorder.Eventf(&binding, v1.EventTypeWarning, EventReasonCleanUpFailed, "Clean up failed: %v", err)
 			}
+			metrics.DeleteBindingMetrics(binding.Namespace, binding.Name)
 			return reconcile.Result{}, nil
 		}
 	} else {
@@ -157,6 +198,32 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 		return reconcile.Result{Requeue: true}, err
 	}
 
+	// Wait for the referenced tenant to be ready.
+	tenantReady, err := binding.TenantReady(c)
+	if err != nil {
+		log.Error(err, "Error checking the binding tenant")
+		return reconcile.Result{RequeueAfter: 1 * time.Minute, Requeue: true}, err
+	}
+	if !tenantReady {
+		log.Info("Waiting for tenant to be ready", "Tenant", binding.Spec.TenantRef.Name)
+		waiting := binding.Status.GetCondition(apiv1alpha1.BindingWaiting)
+		if waiting == nil || waiting.Status != v1.ConditionTrue {
+			message := fmt.Sprintf("Waiting for Tenant %s to be Ready", binding.Spec.TenantRef.Name)
+			recorder.Eventf(&binding, v1.EventTypeNormal, EventReasonTenantNotReady, "%s", message)
+			binding.Status.SetCondition(apiv1alpha1.BindingWaiting, v1.ConditionTrue, EventReasonTenantNotReady, message)
+			err = binding.UpdateStatus(c)
+			if err != nil {
+				log.Error(err, "Failed to update status of binding object")
+				return reconcile.Result{Requeue: true}, err
+			}
+		}
+		return reconcile.Result{RequeueAfter: 30 * time.Second, Requeue: true}, nil
+	}
+	if waiting := binding.Status.GetCondition(apiv1alpha1.BindingWaiting); waiting != nil && waiting.Status == v1.ConditionTrue {
+		binding.Status.SetCondition(apiv1alpha1.BindingWaiting, v1.ConditionFalse, "TenantReady", "")
+		UpdateRequired = true
+	}
+
 	// Get the current state in the binding object
 	initialState, err := binding.GetCurrentState()
 	if err != nil {
@@ -165,9 +232,10 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 	}
 
 	// Generate a new current state from 3scale
-	currentState, err := binding.NewCurrentState(c)
+	currentState, err := binding.NewCurrentState(c, newPortaClient)
 	if err != nil {
 		log.Error(err, "Error getting current state from binding status")
+		recorder.Eventf(&binding, v1.EventTypeWarning, EventReasonSyncFailed, "Error reading current state from 3scale: %v", err)
 		return reconcile.Result{RequeueAfter: 1 * time.Minute, Requeue: true}, err
 
 	}
@@ -205,7 +273,7 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 	if previousState != nil {
 		log.Info("Previous State exists, reconciling.", binding.Name, binding.Namespace)
 
-		c, err := helper.PortaClientFromURLString(currentState.Credentials.AdminURL, currentState.Credentials.AuthToken)
+		portaClient, err := binding.PortaClient(c, currentState.Credentials, newPortaClient)
 		if err != nil {
 			log.Error(err, "Failed creating client")
 		}
@@ -215,9 +283,12 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 		}
 		apisDiff := apiv1alpha1.DiffAPIs(previousState.APIs, desiredState.APIs)
 		for _, api := range apisDiff.MissingFromB {
-			err := api.DeleteFrom3scale(c)
+			err := api.DeleteFrom3scale(portaClient)
 			if err != nil {
 				log.Error(err, "Failed to delete internal api from 3scale")
+				recorder.Eventf(&binding, v1.EventTypeWarning, EventReasonAPIDeleteError, "Error deleting API %s: %v", api.Name, err)
+			} else {
+				recorder.Eventf(&binding, v1.EventTypeNormal, EventReasonAPIDeleted, "Deleted API %s", api.Name)
 			}
 		}
 		// Clean the "PreviousState" if needed, and mark the object for udpate
@@ -233,13 +304,19 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 	} else {
 		log.Info("State is not in sync, reconciling APIs")
 		apisDiff := apiv1alpha1.DiffAPIs(desiredState.APIs, currentState.APIs)
-		err = apisDiff.ReconcileWith3scale(desiredState.Credentials)
+		portaClient, err := binding.PortaClient(c, desiredState.Credentials, newPortaClient)
+		if err == nil {
+			err = apisDiff.ReconcileWith3scale(portaClient)
+		}
 		if err != nil {
 			log.Error(err, "Error Reconciling APIs")
+			recorder.Eventf(&binding, v1.EventTypeWarning, EventReasonSyncFailed, "API sync failed: %v", err)
+		} else {
+			recordAPIsDiffEvents(&binding, recorder, apisDiff)
 		}
 
 		// Refresh the current State
-		currentState, err := binding.NewCurrentState(c)
+		currentState, err := binding.NewCurrentState(c, newPortaClient)
 		if err != nil {
 			log.Error(err, "Error getting current state from binding status")
 		}
@@ -257,6 +334,8 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 		log.Info("Reconciliation finished.")
 	}
 
+	updateBindingMetrics(&binding)
+
 	// Update the object status fields.
 	if UpdateRequired {
 		err = binding.UpdateStatus(c)
@@ -268,3 +347,33 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 
 	return reconcile.Result{RequeueAfter: 1 * time.Minute, Requeue: true}, nil
 }
+
+// recordAPIsDiffEvents emits an event for every API changed in 3scale by a
+// successful reconciliation of the given diff
+func recordAPIsDiffEvents(binding *apiv1alpha1.Binding, recorder record.EventRecorder, apisDiff apiv1alpha1.APIsDiff) {
+	for _, api := range apisDiff.MissingFromB {
+		recorder.Eventf(binding, v1.EventTypeNormal, EventReasonAPICreated, "Created API %s", api.Name)
+	}
+	for _, api := range apisDiff.MissingFromA {
+		recorder.Eventf(binding, v1.EventTypeNormal, EventReasonAPIDeleted, "Deleted API %s", api.Name)
+	}
+	for _, apiPair := range apisDiff.NotEqual {
+		recorder.Eventf(binding, v1.EventTypeNormal, EventReasonAPIUpdated, "Updated API %s", apiPair.A.Name)
+	}
+}
+
+// updateBindingMetrics updates the binding gauges from the binding status
+func updateBindingMetrics(binding *apiv1alpha1.Binding) {
+	apisManaged := 0
+	if currentState, err := binding.GetCurrentState(); err == nil && currentState != nil {
+		apisManaged = len(currentState.APIs)
+	}
+
+	var lastSync *time.Time
+	if timestamp := binding.GetLastSuccessfulSync(); timestamp != nil {
+		lastSyncTime := time.Unix(timestamp.Seconds, int64(timestamp.Nanos))
+		lastSync = &lastSyncTime
+	}
+
+	metrics.SetBindingMetrics(binding.Namespace, binding.Name, binding.StateInSync(), apisManaged, lastSync)
+}
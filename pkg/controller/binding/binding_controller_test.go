@@ -0,0 +1,156 @@
+package binding
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta/fake"
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func newTestClient(t *testing.T, objs ...runtime.Object) client.Client {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := apiv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return k8sfake.NewFakeClientWithScheme(s, objs...)
+}
+
+// terminatingBinding returns a Binding marked for deletion whose current
+// state manages the given APIs
+func terminatingBinding(t *testing.T, apiNames ...string) *apiv1alpha1.Binding {
+	binding := &apiv1alpha1.Binding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "ecorp-binding",
+			Namespace:         "operator-test",
+			Finalizers:        []string{apiv1alpha1.BINDING_FINALIZER},
+			DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+		},
+	}
+	state := apiv1alpha1.State{
+		Credentials: apiv1alpha1.InternalCredentials{AuthToken: "token", AdminURL: "https://ecorp-admin.example.com"},
+	}
+	for _, name := range apiNames {
+		state.APIs = append(state.APIs, apiv1alpha1.InternalAPI{Name: name})
+	}
+	if err := binding.SetCurrentState(state); err != nil {
+		t.Fatal(err)
+	}
+	return binding
+}
+
+func assertFinalizerRemoved(t *testing.T, c client.Client, binding *apiv1alpha1.Binding) {
+	found := &apiv1alpha1.Binding{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}, found)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.HasFinalizer() {
+		t.Error("expected the binding finalizer to be removed")
+	}
+}
+
+func TestReconcileBindingDeletionRemovesAPIs(t *testing.T) {
+	portal := fake.NewAdminPortal()
+	for _, name := range []string{"echo-api", "other-api"} {
+		if _, err := portal.CreateService(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	binding := terminatingBinding(t, "echo-api")
+	k8sClient := newTestClient(t, binding)
+
+	_, err := ReconcileBindingFunc(*binding, k8sClient, portal.ClientFactory(), record.NewFakeRecorder(10), logf.Log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	services, err := portal.ListServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services.Services) != 1 || services.Services[0].SystemName != "other-api" {
+		t.Errorf("expected only the service not managed by the binding to remain, got %v", services.Services)
+	}
+	assertFinalizerRemoved(t, k8sClient, binding)
+}
+
+func TestReconcileBindingDeletionWithoutPortaClient(t *testing.T) {
+	var failingFactory porta.ClientFactory = func(string, string, *tls.Config) (porta.Client, error) {
+		return nil, errors.New("admin portal unreachable")
+	}
+
+	binding := terminatingBinding(t, "echo-api")
+	k8sClient := newTestClient(t, binding)
+
+	_, err := ReconcileBindingFunc(*binding, k8sClient, failingFactory, record.NewFakeRecorder(10), logf.Log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertFinalizerRemoved(t, k8sClient, binding)
+}
+
+func TestReconcileBindingWaitsForTenant(t *testing.T) {
+	tenant := &apiv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "ecorp", Namespace: "operator-test"},
+	}
+	tenant.Status.SetCondition(apiv1alpha1.TenantReady, v1.ConditionFalse, "TenantNotCreated", "3scale tenant has not been created")
+	binding := &apiv1alpha1.Binding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "ecorp-binding",
+			Namespace:  "operator-test",
+			Finalizers: []string{apiv1alpha1.BINDING_FINALIZER},
+		},
+		Spec: apiv1alpha1.BindingSpec{TenantRef: &v1.LocalObjectReference{Name: "ecorp"}},
+	}
+	k8sClient := newTestClient(t, tenant, binding)
+	recorder := record.NewFakeRecorder(10)
+
+	for i := 0; i < 2; i++ {
+		result, err := ReconcileBindingFunc(*binding, k8sClient, unreachableAdminPortal(t), recorder, logf.Log)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.RequeueAfter == 0 {
+			t.Error("expected the binding to be requeued while the tenant is not ready")
+		}
+		if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}, binding); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waiting := binding.Status.GetCondition(apiv1alpha1.BindingWaiting)
+	if waiting == nil || waiting.Status != v1.ConditionTrue || waiting.Reason != EventReasonTenantNotReady {
+		t.Errorf("expected a Waiting condition with reason %s, got %+v", EventReasonTenantNotReady, waiting)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected a single event while waiting, got %d", len(recorder.Events))
+	}
+	if event := <-recorder.Events; !strings.Contains(event, EventReasonTenantNotReady) {
+		t.Errorf("unexpected event %q", event)
+	}
+}
+
+// unreachableAdminPortal fails the test if the admin portal is contacted
+func unreachableAdminPortal(t *testing.T) porta.ClientFactory {
+	return func(string, string, *tls.Config) (porta.Client, error) {
+		t.Error("admin portal must not be contacted")
+		return nil, errors.New("admin portal unreachable")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
 	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
 	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
 	"github.com/go-logr/logr"
@@ -13,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,18 +22,20 @@ import (
 type InternalReconciler struct {
 	k8sClient   client.Client
 	tenantR     *apiv1alpha1.Tenant
-	portaClient *porta_client_pkg.ThreeScaleClient
+	portaClient porta.Client
 	logger      logr.Logger
+	recorder    record.EventRecorder
 }
 
 // NewInternalReconciler constructs InternalReconciler object
 func NewInternalReconciler(k8sClient client.Client, tenantR *apiv1alpha1.Tenant,
-	portaClient *porta_client_pkg.ThreeScaleClient, log logr.Logger) *InternalReconciler {
+	portaClient porta.Client, log logr.Logger, recorder record.EventRecorder) *InternalReconciler {
 	return &InternalReconciler{
 		k8sClient:   k8sClient,
 		tenantR:     tenantR,
 		portaClient: portaClient,
 		logger:      log,
+		recorder:    recorder,
 	}
 }
 
@@ -56,11 +60,132 @@ func (r *InternalReconciler) Run() error {
 		return err
 	}
 
-	tenantStatus := r.getTenantStatus(tenantDef, adminUserDef)
+	tenantStatus, err := r.getTenantStatus(tenantDef, adminUserDef)
+	if err != nil {
+		return err
+	}
 
 	return r.updateTenantStatus(tenantStatus)
 }
 
+// Finalize tenant cleanup logic, run when the tenant object is being deleted
+// Facts to reconcile:
+// - 3scale Tenant Account deleted or suspended, according to the deletion policy.
+//   Skipped when portaClient is nil, because the master portal is gone
+// - Secret with tenant's access_token removed
+// - Tenant finalizer removed
+func (r *InternalReconciler) Finalize() error {
+	policy := r.tenantR.Spec.DeletionPolicy
+	if policy == "" {
+		policy = apiv1alpha1.TenantDeletionPolicyDelete
+	}
+
+	err := r.updateDeletionStatus(policy, apiv1alpha1.TenantDeletionPhaseInProgress, "")
+	if err != nil {
+		return err
+	}
+
+	err = r.finalizeTenant(policy)
+	if err != nil {
+		r.recorder.Eventf(r.tenantR, v1.EventTypeWarning, EventReasonDeletionFailed,
+			"Tenant %d cleanup failed: %v", r.tenantR.Status.TenantId, err)
+		statusErr := r.updateDeletionStatus(policy, apiv1alpha1.TenantDeletionPhaseFailed, err.Error())
+		if statusErr != nil {
+			r.logger.Error(statusErr, "Error updating tenant deletion status")
+		}
+		return err
+	}
+
+	message := ""
+	if r.portaClient == nil && r.tenantR.Status.TenantId != 0 {
+		message = fmt.Sprintf("master portal is gone, tenant %d was not cleaned up", r.tenantR.Status.TenantId)
+	}
+	err = r.updateDeletionStatus(policy, apiv1alpha1.TenantDeletionPhaseCompleted, message)
+	if err != nil {
+		return err
+	}
+
+	err = r.deleteAccessTokenSecret()
+	if err != nil {
+		return err
+	}
+
+	r.tenantR.RemoveFinalizer()
+	return r.k8sClient.Update(context.TODO(), r.tenantR)
+}
+
+func (r *InternalReconciler) finalizeTenant(policy apiv1alpha1.TenantDeletionPolicy) error {
+	tenantID := r.tenantR.Status.TenantId
+	if tenantID == 0 {
+		// Tenant was never created
+		return nil
+	}
+	if r.portaClient == nil {
+		// Master portal is gone, nothing left to clean up
+		return nil
+	}
+
+	var err error
+	switch policy {
+	case apiv1alpha1.TenantDeletionPolicyDelete:
+		r.logger.Info("Deleting tenant", "TenantId", tenantID)
+		err = r.portaClient.DeleteTenant(tenantID)
+	case apiv1alpha1.TenantDeletionPolicySuspend:
+		r.logger.Info("Suspending tenant", "TenantId", tenantID)
+		err = r.portaClient.SuspendTenant(tenantID)
+	default:
+		return fmt.Errorf("Unknown tenant deletion policy: %s", policy)
+	}
+
+	if err != nil && porta.IsNotFound(err) {
+		r.logger.Info("Tenant already removed", "TenantId", tenantID)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if policy == apiv1alpha1.TenantDeletionPolicySuspend {
+		r.recorder.Eventf(r.tenantR, v1.EventTypeNormal, EventReasonTenantSuspended, "Tenant %d suspended", tenantID)
+	} else {
+		r.recorder.Eventf(r.tenantR, v1.EventTypeNormal, EventReasonTenantDeleted, "Tenant %d deleted", tenantID)
+	}
+	return nil
+}
+
+func (r *InternalReconciler) deleteAccessTokenSecret() error {
+	tenantProviderKeySecretNN := r.tenantR.TenantSecretNamespacedName()
+	tenantProviderKeySecret, err := r.findAccessTokenSecret(tenantProviderKeySecretNN)
+	if err != nil || tenantProviderKeySecret == nil {
+		return err
+	}
+
+	// The secret may have been created by someone else before the tenant, only the
+	// one created by this tenant is deleted
+	if owner := metav1.GetControllerOf(tenantProviderKeySecret); owner == nil || owner.UID != r.tenantR.UID {
+		r.logger.Info("Admin access token secret not created by the tenant, keeping it",
+			"Secret NS", tenantProviderKeySecretNN.Namespace, "Secret name", tenantProviderKeySecretNN.Name)
+		return nil
+	}
+
+	r.logger.Info("Deleting admin access token secret",
+		"Secret NS", tenantProviderKeySecretNN.Namespace, "Secret name", tenantProviderKeySecretNN.Name)
+	err = r.k8sClient.Delete(context.TODO(), tenantProviderKeySecret)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *InternalReconciler) updateDeletionStatus(policy apiv1alpha1.TenantDeletionPolicy, phase apiv1alpha1.TenantDeletionPhase, message string) error {
+	tenantStatus := r.tenantR.Status.DeepCopy()
+	tenantStatus.Deletion = &apiv1alpha1.TenantDeletionStatus{
+		Policy:  policy,
+		Phase:   phase,
+		Message: message,
+	}
+	return r.updateTenantStatus(tenantStatus)
+}
+
 // This method makes sure that tenant exists, otherwise it will create one
 // On method completion:
 // * tenant will exist
@@ -97,7 +222,7 @@ func (r *InternalReconciler) fetchTenant() (*porta_client_pkg.Tenant, error) {
 	}
 
 	tenantDef, err := r.portaClient.ShowTenant(r.tenantR.Status.TenantId)
-	if err != nil && porta_client_pkg.IsNotFound(err) {
+	if err != nil && porta.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
 		return nil, err
@@ -131,6 +256,8 @@ func (r *InternalReconciler) syncTenant(tenantDef *porta_client_pkg.Tenant) erro
 		if err != nil {
 			return err
 		}
+		r.recorder.Eventf(r.tenantR, v1.EventTypeNormal, EventReasonTenantUpdated,
+			"Tenant %d updated", tenantDef.Signup.Account.ID)
 	}
 
 	return nil
@@ -157,10 +284,7 @@ func (r *InternalReconciler) reconcileAdminUser(tenantDef *porta_client_pkg.Tena
 
 // This method makes sure secret with tenant's access_token exists
 func (r *InternalReconciler) reconcileAccessTokenSecret(tenantDef *porta_client_pkg.Tenant) error {
-	tenantProviderKeySecretNN := types.NamespacedName{
-		Name:      r.tenantR.Spec.TenantSecretRef.Name,
-		Namespace: r.tenantR.Spec.TenantSecretRef.Namespace,
-	}
+	tenantProviderKeySecretNN := r.tenantR.TenantSecretNamespacedName()
 	tenantProviderKeySecret, err := r.findAccessTokenSecret(tenantProviderKeySecretNN)
 	if err != nil {
 		return err
@@ -187,12 +311,19 @@ func (r *InternalReconciler) createTenant() (*porta_client_pkg.Tenant, error) {
 
 	r.logger.Info("Creating a new tenant", "OrganizationName", r.tenantR.Spec.OrganizationName,
 		"Username", r.tenantR.Spec.Username, "Email", r.tenantR.Spec.Email)
-	return r.portaClient.CreateTenant(
+	tenantDef, err := r.portaClient.CreateTenant(
 		r.tenantR.Spec.OrganizationName,
 		r.tenantR.Spec.Username,
 		r.tenantR.Spec.Email,
 		password,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recorder.Eventf(r.tenantR, v1.EventTypeNormal, EventReasonTenantCreated,
+		"Tenant created with ID %d", tenantDef.Signup.Account.ID)
+	return tenantDef, nil
 }
 
 func (r *InternalReconciler) getAdminPassword() (string, error) {
@@ -287,6 +418,8 @@ func (r *InternalReconciler) syncAdminUser(tenantDef *porta_client_pkg.Tenant, a
 		if err != nil {
 			return err
 		}
+		r.recorder.Eventf(r.tenantR, v1.EventTypeNormal, EventReasonAdminUserUpdated,
+			"Admin user %d updated", adminUser.ID)
 	}
 
 	return nil
@@ -294,7 +427,14 @@ func (r *InternalReconciler) syncAdminUser(tenantDef *porta_client_pkg.Tenant, a
 
 func (r *InternalReconciler) activateAdminUser(tenantDef *porta_client_pkg.Tenant, adminUser *porta_client_pkg.User) error {
 	r.logger.Info("Activating pending admin user", "Account ID", tenantDef.Signup.Account.ID, "ID", adminUser.ID)
-	return r.portaClient.ActivateUser(tenantDef.Signup.Account.ID, adminUser.ID)
+	err := r.portaClient.ActivateUser(tenantDef.Signup.Account.ID, adminUser.ID)
+	if err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(r.tenantR, v1.EventTypeNormal, EventReasonAdminUserActivated,
+		"Admin user %d activated", adminUser.ID)
+	return nil
 }
 
 func (r *InternalReconciler) findAccessTokenSecret(nn types.NamespacedName) (*v1.Secret, error) {
@@ -343,7 +483,14 @@ func (r *InternalReconciler) createTenantProviderKeySecret(tenantDef *porta_clie
 		Type: v1.SecretTypeOpaque,
 	}
 	addOwnerRefToObject(secret, asOwner(r.tenantR))
-	return r.k8sClient.Create(context.TODO(), secret)
+	err = r.k8sClient.Create(context.TODO(), secret)
+	if err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(r.tenantR, v1.EventTypeNormal, EventReasonSecretCreated,
+		"Created Secret %s/%s", nn.Namespace, nn.Name)
+	return nil
 }
 
 func (r *InternalReconciler) findTenantProviderKey(tenantDef *porta_client_pkg.Tenant) (string, error) {
@@ -360,11 +507,25 @@ func (r *InternalReconciler) findTenantProviderKey(tenantDef *porta_client_pkg.T
 	return appList.Applications[0].Application.UserKey, nil
 }
 
-func (r *InternalReconciler) getTenantStatus(tenantDef *porta_client_pkg.Tenant, adminUserDef *porta_client_pkg.User) *apiv1alpha1.TenantStatus {
-	return &apiv1alpha1.TenantStatus{
-		TenantId: tenantDef.Signup.Account.ID,
-		AdminId:  adminUserDef.ID,
+func (r *InternalReconciler) getTenantStatus(tenantDef *porta_client_pkg.Tenant, adminUserDef *porta_client_pkg.User) (*apiv1alpha1.TenantStatus, error) {
+	adminURL, err := URLFromDomain(tenantDef.Signup.Account.AdminDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantStatus := r.tenantR.Status.DeepCopy()
+	tenantStatus.TenantId = tenantDef.Signup.Account.ID
+	tenantStatus.AdminId = adminUserDef.ID
+	tenantStatus.AdminURL = adminURL.String()
+	tenantSecretNN := r.tenantR.TenantSecretNamespacedName()
+	tenantStatus.ProviderKeySecretRef = &v1.SecretReference{
+		Name:      tenantSecretNN.Name,
+		Namespace: tenantSecretNN.Namespace,
 	}
+	tenantStatus.ObservedGeneration = r.tenantR.Generation
+	tenantStatus.SetCondition(apiv1alpha1.TenantReady, v1.ConditionTrue, "TenantReady", "")
+	tenantStatus.SetCondition(apiv1alpha1.TenantSynced, v1.ConditionTrue, "TenantSynced", "")
+	return tenantStatus, nil
 }
 
 func (r *InternalReconciler) updateTenantStatus(tenantStatus *apiv1alpha1.TenantStatus) error {
@@ -0,0 +1,196 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/porta/fake"
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func newTestTenant() *apiv1alpha1.Tenant {
+	tenantR := &apiv1alpha1.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "ecorp", Namespace: "operator-test"},
+		Spec: apiv1alpha1.TenantSpec{
+			Username:               "admin",
+			Email:                  "admin@ecorp.example.com",
+			OrganizationName:       "ECorp",
+			SystemMasterUrl:        "https://master.example.com",
+			PasswordCredentialsRef: v1.SecretReference{Name: "ecorp-admin-password"},
+			MasterCredentialsRef:   v1.SecretReference{Name: "system-seed"},
+		},
+	}
+	tenantR.SetDefaults()
+	return tenantR
+}
+
+func newTestK8sClient(t *testing.T, tenantR *apiv1alpha1.Tenant) client.Client {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := apiv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	passwordSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ecorp-admin-password", Namespace: tenantR.Namespace},
+		Data:       map[string][]byte{TenantAdminPasswordSecretField: []byte("p4ssw0rd")},
+	}
+	return k8sfake.NewFakeClientWithScheme(s, tenantR, passwordSecret)
+}
+
+func TestInternalReconcilerCreatesTenant(t *testing.T) {
+	tenantR := newTestTenant()
+	k8sClient := newTestK8sClient(t, tenantR)
+	portal := fake.NewAdminPortal()
+
+	err := NewInternalReconciler(k8sClient, tenantR, portal, logf.Log, record.NewFakeRecorder(10)).Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tenantR.Status.TenantId == 0 || tenantR.Status.AdminId == 0 {
+		t.Fatalf("tenant status not updated: %+v", tenantR.Status)
+	}
+	if tenantR.Status.AdminURL != "https://ecorp-admin.example.com" {
+		t.Errorf("status admin url = %s, expected https://ecorp-admin.example.com", tenantR.Status.AdminURL)
+	}
+	for _, condType := range []apiv1alpha1.TenantConditionType{apiv1alpha1.TenantReady, apiv1alpha1.TenantSynced} {
+		cond := tenantR.Status.GetCondition(condType)
+		if cond == nil || cond.Status != v1.ConditionTrue {
+			t.Errorf("condition %s not true: %+v", condType, cond)
+		}
+	}
+
+	adminUser, err := portal.ReadUser(tenantR.Status.TenantId, tenantR.Status.AdminId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if adminUser.State != "active" {
+		t.Errorf("admin user state = %s, expected active", adminUser.State)
+	}
+
+	secret := &v1.Secret{}
+	secretNN := types.NamespacedName{Name: tenantR.Spec.TenantSecretRef.Name, Namespace: tenantR.Spec.TenantSecretRef.Namespace}
+	if err := k8sClient.Get(context.TODO(), secretNN, secret); err != nil {
+		t.Fatalf("tenant secret not created: %v", err)
+	}
+	if secret.StringData[TenantAdminDomainKeySecretField] != "https://ecorp-admin.example.com" {
+		t.Errorf("unexpected admin url: %s", secret.StringData[TenantAdminDomainKeySecretField])
+	}
+}
+
+func TestInternalReconcilerSyncsTenant(t *testing.T) {
+	tenantR := newTestTenant()
+	k8sClient := newTestK8sClient(t, tenantR)
+	portal := fake.NewAdminPortal()
+
+	if err := NewInternalReconciler(k8sClient, tenantR, portal, logf.Log, record.NewFakeRecorder(10)).Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tenantR.Spec.OrganizationName = "ECorp Inc"
+	tenantR.Spec.Email = "support@ecorp.example.com"
+	if err := NewInternalReconciler(k8sClient, tenantR, portal, logf.Log, record.NewFakeRecorder(10)).Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tenantDef, err := portal.ShowTenant(tenantR.Status.TenantId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenantDef.Signup.Account.OrgName != "ECorp Inc" {
+		t.Errorf("org name = %s, expected ECorp Inc", tenantDef.Signup.Account.OrgName)
+	}
+
+	adminUser, err := portal.ReadUser(tenantR.Status.TenantId, tenantR.Status.AdminId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if adminUser.Email != "support@ecorp.example.com" {
+		t.Errorf("admin email = %s, expected support@ecorp.example.com", adminUser.Email)
+	}
+}
+
+func TestInternalReconcilerFinalizesTenant(t *testing.T) {
+	for _, policy := range []apiv1alpha1.TenantDeletionPolicy{apiv1alpha1.TenantDeletionPolicyDelete, apiv1alpha1.TenantDeletionPolicySuspend} {
+		tenantR := newTestTenant()
+		tenantR.Spec.DeletionPolicy = policy
+		tenantR.AddFinalizer()
+		k8sClient := newTestK8sClient(t, tenantR)
+		portal := fake.NewAdminPortal()
+
+		if err := NewInternalReconciler(k8sClient, tenantR, portal, logf.Log, record.NewFakeRecorder(10)).Run(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := NewInternalReconciler(k8sClient, tenantR, portal, logf.Log, record.NewFakeRecorder(10)).Finalize(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", policy, err)
+		}
+
+		tenantDef, err := portal.ShowTenant(tenantR.Status.TenantId)
+		switch policy {
+		case apiv1alpha1.TenantDeletionPolicyDelete:
+			if err == nil {
+				t.Errorf("%s: tenant %d still exists", policy, tenantR.Status.TenantId)
+			}
+		case apiv1alpha1.TenantDeletionPolicySuspend:
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tenantDef.Signup.Account.State != "suspended" {
+				t.Errorf("%s: tenant state = %s, expected suspended", policy, tenantDef.Signup.Account.State)
+			}
+		}
+
+		if tenantR.Status.Deletion == nil || tenantR.Status.Deletion.Phase != apiv1alpha1.TenantDeletionPhaseCompleted {
+			t.Errorf("%s: unexpected deletion status: %+v", policy, tenantR.Status.Deletion)
+		}
+
+		if tenantR.HasFinalizer() {
+			t.Errorf("%s: tenant finalizer not removed", policy)
+		}
+
+		secretNN := types.NamespacedName{Name: tenantR.Spec.TenantSecretRef.Name, Namespace: tenantR.Spec.TenantSecretRef.Namespace}
+		if err := k8sClient.Get(context.TODO(), secretNN, &v1.Secret{}); err == nil {
+			t.Errorf("%s: tenant secret not removed", policy)
+		}
+	}
+}
+
+func TestInternalReconcilerFinalizeKeepsUnownedSecret(t *testing.T) {
+	tenantR := newTestTenant()
+	tenantR.UID = types.UID("ecorp-uid")
+	tenantR.AddFinalizer()
+	k8sClient := newTestK8sClient(t, tenantR)
+
+	// A secret created before the tenant, e.g. by the user, at the tenant secret location
+	secretNN := types.NamespacedName{Name: tenantR.Spec.TenantSecretRef.Name, Namespace: tenantR.Spec.TenantSecretRef.Namespace}
+	userSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretNN.Name, Namespace: secretNN.Namespace},
+		Data:       map[string][]byte{TenantProviderKeySecretField: []byte("user-token")},
+	}
+	if err := k8sClient.Create(context.TODO(), userSecret); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewInternalReconciler(k8sClient, tenantR, fake.NewAdminPortal(), logf.Log, record.NewFakeRecorder(10)).Finalize(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := k8sClient.Get(context.TODO(), secretNN, &v1.Secret{}); err != nil {
+		t.Errorf("secret not created by the tenant was removed: %v", err)
+	}
+	if tenantR.HasFinalizer() {
+		t.Error("tenant finalizer not removed")
+	}
+}
@@ -0,0 +1,280 @@
+package tenant
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/apis/apps"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// MasterCredentialsAllowlistEnvVar is the operator environment variable listing the master
+// credentials secrets Tenants may reference from other namespaces.
+// It is a comma separated list of "source:namespace/name" entries, allowing Tenants in the
+// source namespace to reference the secret. "*" as source allows Tenants in every namespace,
+// and "namespace/*" allows every secret of the namespace. Cross-namespace references are
+// rejected when it is empty
+const MasterCredentialsAllowlistEnvVar = "MASTER_CREDENTIALS_ALLOWLIST"
+
+// NewAPIReader returns a client reading directly from the apiserver, not limited
+// to the namespaces watched by the manager cache.
+// Reads are not cached: every Tenant and TenantUser reconciliation issues a GET of the
+// master credentials secret, and of the APIManager and its CA bundle when referenced.
+// The load is bounded by the Tenant resync period, and TenantUsers are only
+// reconciled on changes
+func NewAPIReader(mgr manager.Manager) (client.Reader, error) {
+	return client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+}
+
+// masterCredentialsNamespacedName returns the location of the tenant master credentials secret
+func masterCredentialsNamespacedName(tenantR *apiv1alpha1.Tenant) (types.NamespacedName, error) {
+	nn := types.NamespacedName{
+		Name:      tenantR.Spec.MasterCredentialsRef.Name,
+		Namespace: tenantR.Spec.MasterCredentialsRef.Namespace,
+	}
+
+	if nn.Namespace == "" || nn.Namespace == tenantR.Namespace {
+		nn.Namespace = tenantR.Namespace
+		return nn, nil
+	}
+
+	if err := checkMasterCredentialsAllowed(tenantR, nn); err != nil {
+		return types.NamespacedName{}, err
+	}
+	return nn, nil
+}
+
+func checkMasterCredentialsAllowed(tenantR *apiv1alpha1.Tenant, nn types.NamespacedName) error {
+	if !masterCredentialsAllowed(os.Getenv(MasterCredentialsAllowlistEnvVar), tenantR.Namespace, nn) {
+		return fmt.Errorf("Master secret (ns: %s, name: %s) is not allowed to be referenced from namespace %s. "+
+			"Add it to the operator %s environment variable", nn.Namespace, nn.Name, tenantR.Namespace, MasterCredentialsAllowlistEnvVar)
+	}
+	return nil
+}
+
+// masterCredentialsAllowed checks the secret referenced from the source namespace against
+// the comma separated allowlist. Entries without a source namespace are ignored
+func masterCredentialsAllowed(allowlist, sourceNamespace string, nn types.NamespacedName) bool {
+	for _, entry := range strings.Split(allowlist, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		source, secret := parts[0], parts[1]
+		if source != "*" && source != sourceNamespace {
+			continue
+		}
+		if secret == nn.String() || secret == nn.Namespace+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// MasterPortal holds the location and credentials of the master portal managing a tenant
+type MasterPortal struct {
+	URL         string
+	AccessToken string
+	// TLS verifies the master portal certificate. Its CA bundle is read from TLSNamespace
+	TLS          *apiv1alpha1.AdminPortalTLSSpec
+	TLSNamespace string
+}
+
+// TLSConfig builds the tls config used to connect to the master portal
+func (m *MasterPortal) TLSConfig(k8sClient client.Reader) (*tls.Config, error) {
+	return m.TLS.TLSConfig(k8sClient, m.TLSNamespace)
+}
+
+// APIManagerNotReadyError is returned while the APIManager referenced by a Tenant is not ready
+type APIManagerNotReadyError struct {
+	Name      string
+	Namespace string
+}
+
+func (e *APIManagerNotReadyError) Error() string {
+	return fmt.Sprintf("APIManager (ns: %s, name: %s) is not ready", e.Namespace, e.Name)
+}
+
+// IsAPIManagerNotReady returns true if the error is an APIManagerNotReadyError
+func IsAPIManagerNotReady(err error) bool {
+	_, ok := err.(*APIManagerNotReadyError)
+	return ok
+}
+
+// apiManagerNamespacedName returns the APIManager referenced by spec.apiManagerRef,
+// defaulting to the tenant namespace
+func apiManagerNamespacedName(tenantR *apiv1alpha1.Tenant) types.NamespacedName {
+	apimanagerNN := types.NamespacedName{
+		Name:      tenantR.Spec.APIManagerRef.Name,
+		Namespace: tenantR.Spec.APIManagerRef.Namespace,
+	}
+	if apimanagerNN.Namespace == "" {
+		apimanagerNN.Namespace = tenantR.Namespace
+	}
+	return apimanagerNN
+}
+
+// FetchMasterPortal returns the master portal of the tenant.
+// When spec.apiManagerRef is set, the master portal URL is read from the APIManager
+// system-master route, honouring custom hosts, and the access token is read from its
+// system-seed secret. Unless
+// spec.tls is set, the master portal certificate is verified with the APIManager spec.portalTLS.
+// Otherwise spec.systemMasterUrl and spec.masterCredentialsRef are used.
+// Master credentials read from another namespace are only sent to the master portal of
+// the APIManager owning them, verified with its spec.portalTLS
+func FetchMasterPortal(k8sClient client.Reader, tenantR *apiv1alpha1.Tenant) (*MasterPortal, error) {
+	if tenantR.Spec.APIManagerRef == nil {
+		if tenantR.Spec.SystemMasterUrl == "" {
+			return nil, fmt.Errorf("Either spec.apiManagerRef or spec.systemMasterUrl must be set")
+		}
+		masterCredentialsNN, err := masterCredentialsNamespacedName(tenantR)
+		if err != nil {
+			return nil, err
+		}
+		if masterCredentialsNN.Namespace != tenantR.Namespace {
+			return fetchAllowlistedMasterPortal(k8sClient, tenantR, masterCredentialsNN)
+		}
+		masterAccessToken, err := FetchMasterCredentials(k8sClient, tenantR)
+		if err != nil {
+			return nil, err
+		}
+		return &MasterPortal{
+			URL:          tenantR.Spec.SystemMasterUrl,
+			AccessToken:  masterAccessToken,
+			TLS:          tenantR.Spec.TLS,
+			TLSNamespace: tenantR.Namespace,
+		}, nil
+	}
+
+	apimanagerNN := apiManagerNamespacedName(tenantR)
+	systemSeedNN := types.NamespacedName{Name: component.SystemSecretSystemSeedSecretName, Namespace: apimanagerNN.Namespace}
+	if systemSeedNN.Namespace != tenantR.Namespace {
+		if err := checkMasterCredentialsAllowed(tenantR, systemSeedNN); err != nil {
+			return nil, err
+		}
+		if tenantR.Spec.SystemMasterUrl != "" {
+			return nil, fmt.Errorf("spec.systemMasterUrl cannot be set when the master credentials are read from another namespace")
+		}
+		if err := checkMasterPortalTLSNotOverridden(tenantR); err != nil {
+			return nil, err
+		}
+	}
+
+	apimanager := &appsv1alpha1.APIManager{}
+	err := k8sClient.Get(context.TODO(), apimanagerNN, apimanager)
+	if err != nil {
+		return nil, err
+	}
+
+	systemSeedSecret := &v1.Secret{}
+	err = k8sClient.Get(context.TODO(), systemSeedNN, systemSeedSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiManagerMasterPortal(k8sClient, tenantR, apimanager, systemSeedSecret)
+}
+
+// fetchAllowlistedMasterPortal returns the master portal of a tenant reading the
+// master credentials from another namespace. The credentials secret must be owned by an
+// APIManager, and spec.systemMasterUrl must be the master portal of that APIManager
+func fetchAllowlistedMasterPortal(k8sClient client.Reader, tenantR *apiv1alpha1.Tenant, masterCredentialsNN types.NamespacedName) (*MasterPortal, error) {
+	if err := checkMasterPortalTLSNotOverridden(tenantR); err != nil {
+		return nil, err
+	}
+
+	masterCredentialsSecret := &v1.Secret{}
+	err := k8sClient.Get(context.TODO(), masterCredentialsNN, masterCredentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := metav1.GetControllerOf(masterCredentialsSecret)
+	if owner == nil || owner.Kind != apps.APIManagerKind {
+		return nil, fmt.Errorf("Master secret (ns: %s, name: %s) is not owned by an APIManager. "+
+			"Master credentials from another namespace can only be sent to the master portal of their APIManager",
+			masterCredentialsNN.Namespace, masterCredentialsNN.Name)
+	}
+	apimanager := &appsv1alpha1.APIManager{}
+	err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: owner.Name, Namespace: masterCredentialsNN.Namespace}, apimanager)
+	if err != nil {
+		return nil, err
+	}
+	if apimanager.UID != owner.UID {
+		return nil, fmt.Errorf("Master secret (ns: %s, name: %s) is not owned by APIManager %s",
+			masterCredentialsNN.Namespace, masterCredentialsNN.Name, owner.Name)
+	}
+
+	masterPortal, err := apiManagerMasterPortal(k8sClient, tenantR, apimanager, masterCredentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(tenantR.Spec.SystemMasterUrl, "/") != masterPortal.URL {
+		return nil, fmt.Errorf("spec.systemMasterUrl %s is not the master portal %s of the APIManager owning the master secret (ns: %s, name: %s)",
+			tenantR.Spec.SystemMasterUrl, masterPortal.URL, masterCredentialsNN.Namespace, masterCredentialsNN.Name)
+	}
+	return masterPortal, nil
+}
+
+// checkMasterPortalTLSNotOverridden rejects a tls override for master credentials read
+// from another namespace, which would allow sending them to an unverified host
+func checkMasterPortalTLSNotOverridden(tenantR *apiv1alpha1.Tenant) error {
+	if tenantR.Spec.TLS != nil {
+		return fmt.Errorf("spec.tls cannot be set when the master credentials are read from another namespace. " +
+			"The master portal certificate is verified with the APIManager spec.portalTLS")
+	}
+	return nil
+}
+
+// apiManagerMasterPortal returns the master portal of the APIManager, with the master
+// access token of the given secret
+func apiManagerMasterPortal(k8sClient client.Reader, tenantR *apiv1alpha1.Tenant, apimanager *appsv1alpha1.APIManager, masterCredentialsSecret *v1.Secret) (*MasterPortal, error) {
+	if !apiManagerReady(apimanager) {
+		return nil, &APIManagerNotReadyError{Name: apimanager.Name, Namespace: apimanager.Namespace}
+	}
+
+	masterAccessToken, ok := masterCredentialsSecret.Data[component.SystemSecretSystemSeedMasterAccessTokenFieldName]
+	if !ok {
+		return nil, fmt.Errorf("Key not found in master secret (ns: %s, name: %s) key: %s",
+			masterCredentialsSecret.Namespace, masterCredentialsSecret.Name, component.SystemSecretSystemSeedMasterAccessTokenFieldName)
+	}
+
+	masterRoute := &routev1.Route{}
+	err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: component.SystemMasterRouteName, Namespace: apimanager.Namespace}, masterRoute)
+	if err != nil {
+		return nil, err
+	}
+
+	masterPortal := &MasterPortal{
+		URL:          helper.RouteURL(masterRoute),
+		AccessToken:  string(masterAccessToken),
+		TLS:          tenantR.Spec.TLS,
+		TLSNamespace: tenantR.Namespace,
+	}
+	if masterPortal.TLS == nil {
+		masterPortal.TLS = apimanager.Spec.PortalTLS
+		masterPortal.TLSNamespace = apimanager.Namespace
+	}
+	return masterPortal, nil
+}
+
+func apiManagerReady(apimanager *appsv1alpha1.APIManager) bool {
+	for _, condition := range apimanager.Status.Conditions {
+		if condition.Type == appsv1alpha1.APIManagerReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
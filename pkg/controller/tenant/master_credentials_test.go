@@ -0,0 +1,242 @@
+package tenant
+
+import (
+	"os"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMasterCredentialsAllowed(t *testing.T) {
+	cases := []struct {
+		allowlist       string
+		sourceNamespace string
+		nn              types.NamespacedName
+		expected        bool
+	}{
+		{"", "team-a", types.NamespacedName{Namespace: "3scale", Name: "system-seed"}, false},
+		{"team-a:3scale/system-seed", "team-a", types.NamespacedName{Namespace: "3scale", Name: "system-seed"}, true},
+		{"team-a:3scale/system-seed", "team-b", types.NamespacedName{Namespace: "3scale", Name: "system-seed"}, false},
+		{"team-a:other/system-seed, team-b:3scale/system-seed", "team-b", types.NamespacedName{Namespace: "3scale", Name: "system-seed"}, true},
+		{"team-a:3scale/system-seed", "team-a", types.NamespacedName{Namespace: "3scale", Name: "other"}, false},
+		{"team-a:3scale/*", "team-a", types.NamespacedName{Namespace: "3scale", Name: "other"}, true},
+		{"team-a:3scale/*", "team-a", types.NamespacedName{Namespace: "team-a", Name: "system-seed"}, false},
+		{"*:3scale/system-seed", "team-c", types.NamespacedName{Namespace: "3scale", Name: "system-seed"}, true},
+		{"3scale/system-seed", "team-a", types.NamespacedName{Namespace: "3scale", Name: "system-seed"}, false},
+	}
+
+	for _, c := range cases {
+		if allowed := masterCredentialsAllowed(c.allowlist, c.sourceNamespace, c.nn); allowed != c.expected {
+			t.Errorf("allowlist %q, secret %s referenced from %s: allowed = %t, expected %t", c.allowlist, c.nn, c.sourceNamespace, allowed, c.expected)
+		}
+	}
+}
+
+func newAPIManagerTestScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := routev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// newTenantTestScheme returns a scheme with the Tenant and APIManager types
+func newTenantTestScheme(t *testing.T) *runtime.Scheme {
+	s := newAPIManagerTestScheme(t)
+	if err := apiv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// masterRoute returns the system-master route of an APIManager, served with edge TLS
+func masterRoute(namespace, host string) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemMasterRouteName, Namespace: namespace},
+		Spec: routev1.RouteSpec{
+			Host: host,
+			TLS:  &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge},
+		},
+	}
+}
+
+func TestFetchMasterPortalFromAPIManager(t *testing.T) {
+	s := newAPIManagerTestScheme(t)
+
+	tenantR := newTestTenant()
+	tenantR.Spec.SystemMasterUrl = ""
+	tenantR.Spec.MasterCredentialsRef = v1.SecretReference{}
+	tenantR.Spec.APIManagerRef = &apiv1alpha1.APIManagerReference{Name: "example-apimanager"}
+
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: tenantR.Namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{WildcardDomain: "apps.example.com"},
+		},
+	}
+	systemSeed := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemSecretSystemSeedSecretName, Namespace: tenantR.Namespace},
+		Data: map[string][]byte{
+			component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("m4st3r"),
+			component.SystemSecretSystemSeedMasterDomainFieldName:      []byte("master-portal"),
+		},
+	}
+	// The master route host is customized with spec.routes.systemMaster
+	route := masterRoute(tenantR.Namespace, "master.custom.example.com")
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, apimanager, systemSeed, route)
+
+	if _, err := FetchMasterPortal(k8sClient, tenantR); !IsAPIManagerNotReady(err) {
+		t.Fatalf("expected APIManager not ready error, got %v", err)
+	}
+
+	apimanager.Status.Conditions = []appsv1alpha1.APIManagerCondition{
+		{Type: appsv1alpha1.APIManagerReady, Status: v1.ConditionTrue},
+	}
+	k8sClient = k8sfake.NewFakeClientWithScheme(s, apimanager, systemSeed, route)
+
+	masterPortal, err := FetchMasterPortal(k8sClient, tenantR)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if masterPortal.URL != "https://master.custom.example.com" {
+		t.Errorf("master url = %s, expected https://master.custom.example.com", masterPortal.URL)
+	}
+	if masterPortal.AccessToken != "m4st3r" {
+		t.Errorf("master access token = %s, expected m4st3r", masterPortal.AccessToken)
+	}
+}
+
+func TestFetchMasterPortalTLSFromAPIManager(t *testing.T) {
+	s := newAPIManagerTestScheme(t)
+
+	tenantR := newTestTenant()
+	tenantR.Spec.APIManagerRef = &apiv1alpha1.APIManagerReference{Name: "example-apimanager"}
+
+	portalTLS := &apiv1alpha1.AdminPortalTLSSpec{InsecureSkipVerify: true}
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: tenantR.Namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{WildcardDomain: "apps.example.com"},
+			PortalTLS:            portalTLS,
+		},
+		Status: appsv1alpha1.APIManagerStatus{
+			Conditions: []appsv1alpha1.APIManagerCondition{{Type: appsv1alpha1.APIManagerReady, Status: v1.ConditionTrue}},
+		},
+	}
+	systemSeed := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemSecretSystemSeedSecretName, Namespace: tenantR.Namespace},
+		Data:       map[string][]byte{component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("m4st3r")},
+	}
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, apimanager, systemSeed, masterRoute(tenantR.Namespace, "master.apps.example.com"))
+
+	masterPortal, err := FetchMasterPortal(k8sClient, tenantR)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tlsConfig, err := masterPortal.TLSConfig(k8sClient)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("expected the APIManager portalTLS to be used when the tenant does not set tls")
+	}
+
+	tenantR.Spec.TLS = &apiv1alpha1.AdminPortalTLSSpec{}
+	masterPortal, err = FetchMasterPortal(k8sClient, tenantR)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if masterPortal.TLS != tenantR.Spec.TLS {
+		t.Error("expected the tenant tls to take precedence over the APIManager portalTLS")
+	}
+}
+
+func TestFetchMasterPortalFromAllowlistedNamespace(t *testing.T) {
+	s := newAPIManagerTestScheme(t)
+	defer os.Unsetenv(MasterCredentialsAllowlistEnvVar)
+	os.Setenv(MasterCredentialsAllowlistEnvVar, "operator-test:3scale/system-seed")
+
+	portalTLS := &apiv1alpha1.AdminPortalTLSSpec{InsecureSkipVerify: true}
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: "3scale", UID: "apimanager-uid"},
+		Spec:       appsv1alpha1.APIManagerSpec{PortalTLS: portalTLS},
+		Status: appsv1alpha1.APIManagerStatus{
+			Conditions: []appsv1alpha1.APIManagerCondition{{Type: appsv1alpha1.APIManagerReady, Status: v1.ConditionTrue}},
+		},
+	}
+	controller := true
+	systemSeed := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      component.SystemSecretSystemSeedSecretName,
+			Namespace: "3scale",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "APIManager", Name: apimanager.Name, UID: apimanager.UID, Controller: &controller},
+			},
+		},
+		Data: map[string][]byte{component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("m4st3r")},
+	}
+	route := masterRoute("3scale", "master.apps.example.com")
+	k8sClient := k8sfake.NewFakeClientWithScheme(s, apimanager, systemSeed, route)
+
+	newTenant := func() *apiv1alpha1.Tenant {
+		tenantR := newTestTenant()
+		tenantR.Spec.SystemMasterUrl = "https://master.apps.example.com"
+		tenantR.Spec.MasterCredentialsRef = v1.SecretReference{Name: component.SystemSecretSystemSeedSecretName, Namespace: "3scale"}
+		return tenantR
+	}
+
+	masterPortal, err := FetchMasterPortal(k8sClient, newTenant())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if masterPortal.AccessToken != "m4st3r" || masterPortal.TLS == nil || !masterPortal.TLS.InsecureSkipVerify || masterPortal.TLSNamespace != "3scale" {
+		t.Errorf("expected the master portal of the APIManager owning the credentials, got %+v", masterPortal)
+	}
+
+	// The master access token is never sent to another host, nor without
+	// verifying the certificate as configured in the APIManager
+	tenantR := newTenant()
+	tenantR.Spec.SystemMasterUrl = "https://master.attacker.example.com"
+	if _, err := FetchMasterPortal(k8sClient, tenantR); err == nil {
+		t.Error("expected error when spec.systemMasterUrl is not the master portal of the APIManager")
+	}
+	tenantR = newTenant()
+	tenantR.Spec.TLS = &apiv1alpha1.AdminPortalTLSSpec{InsecureSkipVerify: true}
+	if _, err := FetchMasterPortal(k8sClient, tenantR); err == nil {
+		t.Error("expected error when spec.tls is set for master credentials of another namespace")
+	}
+
+	tenantR = newTestTenant()
+	tenantR.Spec.SystemMasterUrl = "https://master.attacker.example.com"
+	tenantR.Spec.APIManagerRef = &apiv1alpha1.APIManagerReference{Name: apimanager.Name, Namespace: "3scale"}
+	if _, err := FetchMasterPortal(k8sClient, tenantR); err == nil {
+		t.Error("expected error when spec.systemMasterUrl is set with an APIManager of another namespace")
+	}
+	tenantR.Spec.SystemMasterUrl = ""
+	tenantR.Spec.TLS = &apiv1alpha1.AdminPortalTLSSpec{InsecureSkipVerify: true}
+	if _, err := FetchMasterPortal(k8sClient, tenantR); err == nil {
+		t.Error("expected error when spec.tls is set with an APIManager of another namespace")
+	}
+
+	// Credentials not owned by an APIManager cannot be referenced from
+	// another namespace
+	systemSeed.OwnerReferences = nil
+	k8sClient = k8sfake.NewFakeClientWithScheme(s, apimanager, systemSeed, route)
+	if _, err := FetchMasterPortal(k8sClient, newTenant()); err == nil {
+		t.Error("expected error when the master secret is not owned by an APIManager")
+	}
+}
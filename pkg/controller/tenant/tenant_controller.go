@@ -4,14 +4,20 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/metrics"
+	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -32,6 +38,30 @@ const TenantProviderKeySecretField = "token"
 // Tenant's credentials secret field name for admin domain url
 const TenantAdminDomainKeySecretField = "adminURL"
 
+// DefaultResyncPeriod is the interval between tenant reconciliations
+// when spec.resyncPeriod is not set
+const DefaultResyncPeriod = 10 * time.Minute
+
+// apiManagerNotReadyRequeueDelay is the interval between checks of the APIManager
+// referenced by the tenant while it is not ready
+const apiManagerNotReadyRequeueDelay = 30 * time.Second
+
+// Reasons of the events emitted on the Tenant resource
+const (
+	EventReasonTenantCreated      = "TenantCreated"
+	EventReasonTenantUpdated      = "TenantUpdated"
+	EventReasonAdminUserActivated = "AdminUserActivated"
+	EventReasonAdminUserUpdated   = "AdminUserUpdated"
+	EventReasonSecretCreated      = "SecretCreated"
+	EventReasonCredentialsError   = "MasterCredentialsError"
+	EventReasonReconcileFailed    = "ReconcileFailed"
+	EventReasonTenantDeleted      = "TenantDeleted"
+	EventReasonTenantSuspended    = "TenantSuspended"
+	EventReasonDeletionFailed     = "DeletionFailed"
+	EventReasonAPIManagerNotReady = "APIManagerNotReady"
+	EventReasonMasterPortalGone   = "MasterPortalGone"
+)
+
 /**
 * USER ACTION REQUIRED: This is a scaffold file intended for the user to modify with their own Controller
 * business logic.  Delete these comments after modifying this file.*
@@ -40,18 +70,33 @@ const TenantAdminDomainKeySecretField = "adminURL"
 // Add creates a new Tenant Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileTenant{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	apiReader, err := NewAPIReader(mgr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReconcileTenant{
+		client:             mgr.GetClient(),
+		apiReader:          apiReader,
+		scheme:             mgr.GetScheme(),
+		recorder:           mgr.GetRecorder("tenant-controller"),
+		portaClientFactory: helper.PortaClientFromURLString,
+	}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("tenant-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("tenant-controller", mgr, controller.Options{Reconciler: metrics.NewInstrumentedReconciler("tenant-controller", r)})
 	if err != nil {
 		return err
 	}
@@ -73,7 +118,12 @@ type ReconcileTenant struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
-	scheme *runtime.Scheme
+	// apiReader reads directly from the apiserver, so master credentials
+	// can be read from namespaces not watched by the manager cache
+	apiReader          client.Reader
+	scheme             *runtime.Scheme
+	recorder           record.EventRecorder
+	portaClientFactory porta.ClientFactory
 }
 
 // Reconcile reads that state of the cluster for a Tenant object and makes changes based on the state read
@@ -102,7 +152,15 @@ func (r *ReconcileTenant) Reconcile(request reconcile.Request) (reconcile.Result
 		return reconcile.Result{}, err
 	}
 
+	if tenantR.IsTerminating() {
+		return r.finalize(tenantR, reqLogger)
+	}
+
 	changed := tenantR.SetDefaults()
+	if !tenantR.HasFinalizer() {
+		tenantR.AddFinalizer()
+		changed = true
+	}
 	if changed {
 		err = r.client.Update(context.TODO(), tenantR)
 		if err != nil {
@@ -113,44 +171,155 @@ func (r *ReconcileTenant) Reconcile(request reconcile.Request) (reconcile.Result
 		return reconcile.Result{}, nil
 	}
 
-	masterAccessToken, err := FetchMasterCredentials(r.client, tenantR)
-	if err != nil {
+	masterPortal, err := FetchMasterPortal(r.apiReader, tenantR)
+	if err != nil && IsAPIManagerNotReady(err) {
+		reqLogger.Info("Waiting for APIManager to be ready", "apimanager", tenantR.Spec.APIManagerRef.Name)
+		r.updateSyncFailedStatus(tenantR, EventReasonAPIManagerNotReady, err)
+		return reconcile.Result{RequeueAfter: apiManagerNotReadyRequeueDelay}, nil
+	} else if err != nil {
 		log.Error(err, "Error fetching master credentials secret")
+		r.recorder.Eventf(tenantR, v1.EventTypeWarning, EventReasonCredentialsError, "Error fetching master credentials: %v", err)
+		r.updateSyncFailedStatus(tenantR, EventReasonCredentialsError, err)
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
-	portaClient, err := helper.PortaClientFromURLString(tenantR.Spec.SystemMasterUrl, masterAccessToken)
+	tlsConfig, err := masterPortal.TLSConfig(r.apiReader)
+	if err != nil {
+		log.Error(err, "Error building master portal TLS config")
+		r.recorder.Eventf(tenantR, v1.EventTypeWarning, EventReasonReconcileFailed, "Error reading master portal CA bundle: %v", err)
+		r.updateSyncFailedStatus(tenantR, EventReasonReconcileFailed, err)
+		return reconcile.Result{}, err
+	}
+
+	portaClient, err := r.portaClientFactory(masterPortal.URL, masterPortal.AccessToken, tlsConfig)
 	if err != nil {
 		log.Error(err, "Error creating porta client object")
+		r.recorder.Eventf(tenantR, v1.EventTypeWarning, EventReasonReconcileFailed, "Error creating master portal client: %v", err)
+		r.updateSyncFailedStatus(tenantR, EventReasonReconcileFailed, err)
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
-	internalReconciler := NewInternalReconciler(r.client, tenantR, portaClient, reqLogger)
+	internalReconciler := NewInternalReconciler(r.client, tenantR, portaClient, reqLogger, r.recorder)
 	err = internalReconciler.Run()
 	if err != nil {
 		log.Error(err, "Error in tenant reconciliation")
+		r.recorder.Eventf(tenantR, v1.EventTypeWarning, EventReasonReconcileFailed, "Tenant reconciliation failed: %v", err)
+		r.updateSyncFailedStatus(tenantR, EventReasonReconcileFailed, err)
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
 	reqLogger.Info("Tenant reconciled successfully")
+	// Requeue to correct any drift of the 3scale tenant
+	return reconcile.Result{RequeueAfter: resyncPeriod(tenantR)}, nil
+}
+
+// finalize cleans up the 3scale tenant of a Tenant being deleted. It runs before the
+// master portal is resolved, so the finalizer is removed without contacting the master
+// portal when the 3scale tenant was never created, or when the master portal is gone
+func (r *ReconcileTenant) finalize(tenantR *apiv1alpha1.Tenant, reqLogger logr.Logger) (reconcile.Result, error) {
+	if !tenantR.HasFinalizer() {
+		reqLogger.Info("Tenant resource is being deleted")
+		return reconcile.Result{}, nil
+	}
+
+	var portaClient porta.Client
+	if tenantR.Status.TenantId != 0 {
+		var err error
+		portaClient, err = r.masterPortaClient(tenantR)
+		if err != nil && errors.IsNotFound(err) && r.apiManagerGone(tenantR) {
+			reqLogger.Info("Master portal is gone, skipping 3scale tenant cleanup", "TenantId", tenantR.Status.TenantId, "reason", err.Error())
+			r.recorder.Eventf(tenantR, v1.EventTypeWarning, EventReasonMasterPortalGone,
+				"Tenant %d not cleaned up, master portal is gone: %v", tenantR.Status.TenantId, err)
+			portaClient = nil
+		} else if err != nil && IsAPIManagerNotReady(err) {
+			reqLogger.Info("Waiting for APIManager to be ready to finalize tenant", "apimanager", tenantR.Spec.APIManagerRef.Name)
+			return reconcile.Result{RequeueAfter: apiManagerNotReadyRequeueDelay}, nil
+		} else if err != nil {
+			log.Error(err, "Error creating master portal client")
+			r.recorder.Eventf(tenantR, v1.EventTypeWarning, EventReasonDeletionFailed, "Error creating master portal client: %v", err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	err := NewInternalReconciler(r.client, tenantR, portaClient, reqLogger, r.recorder).Finalize()
+	if err != nil {
+		log.Error(err, "Error in tenant finalization")
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("Tenant finalized successfully")
 	return reconcile.Result{}, nil
 }
 
-// FetchMasterCredentials get secret using k8s client
-func FetchMasterCredentials(k8sClient client.Client, tenantR *apiv1alpha1.Tenant) (string, error) {
-	masterCredentialsSecret := &v1.Secret{}
+// masterPortaClient builds a porta client for the master portal managing the tenant
+func (r *ReconcileTenant) masterPortaClient(tenantR *apiv1alpha1.Tenant) (porta.Client, error) {
+	masterPortal, err := FetchMasterPortal(r.apiReader, tenantR)
+	if err != nil {
+		return nil, err
+	}
 
-	err := k8sClient.Get(context.TODO(),
-		types.NamespacedName{
-			Name: tenantR.Spec.MasterCredentialsRef.Name,
-			// Master credential secret MUST be on same namespace as tenant CR
-			Namespace: tenantR.Namespace,
-		},
-		masterCredentialsSecret)
+	tlsConfig, err := masterPortal.TLSConfig(r.apiReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.portaClientFactory(masterPortal.URL, masterPortal.AccessToken, tlsConfig)
+}
+
+// apiManagerGone returns true when the APIManager referenced by spec.apiManagerRef
+// was deleted, so the master portal of the tenant is gone with it.
+// Any other missing object, like the master credentials secret, is reported as an error
+func (r *ReconcileTenant) apiManagerGone(tenantR *apiv1alpha1.Tenant) bool {
+	if tenantR.Spec.APIManagerRef == nil {
+		return false
+	}
+	err := r.apiReader.Get(context.TODO(), apiManagerNamespacedName(tenantR), &appsv1alpha1.APIManager{})
+	return errors.IsNotFound(err)
+}
+
+// updateSyncFailedStatus records the reconciliation error in the tenant Synced condition
+func (r *ReconcileTenant) updateSyncFailedStatus(tenantR *apiv1alpha1.Tenant, reason string, syncErr error) {
+	if tenantR.IsTerminating() {
+		return
+	}
+
+	tenantStatus := tenantR.Status.DeepCopy()
+	tenantStatus.ObservedGeneration = tenantR.Generation
+	tenantStatus.SetCondition(apiv1alpha1.TenantSynced, v1.ConditionFalse, reason, syncErr.Error())
+	if tenantStatus.TenantId == 0 {
+		tenantStatus.SetCondition(apiv1alpha1.TenantReady, v1.ConditionFalse, "TenantNotCreated", "3scale tenant has not been created")
+	}
 
+	if reflect.DeepEqual(tenantR.Status, *tenantStatus) {
+		return
+	}
+	tenantR.Status = *tenantStatus
+	err := r.client.Status().Update(context.TODO(), tenantR)
+	if err != nil {
+		log.Error(err, "Error updating tenant status")
+	}
+}
+
+func resyncPeriod(tenantR *apiv1alpha1.Tenant) time.Duration {
+	if tenantR.Spec.ResyncPeriod == nil {
+		return DefaultResyncPeriod
+	}
+	return tenantR.Spec.ResyncPeriod.Duration
+}
+
+// FetchMasterCredentials get secret using k8s client
+// The master credentials secret is looked up in the tenant namespace, unless
+// MasterCredentialsRef.Namespace references another namespace allowed by the operator
+func FetchMasterCredentials(k8sClient client.Reader, tenantR *apiv1alpha1.Tenant) (string, error) {
+	masterCredentialsSecretNN, err := masterCredentialsNamespacedName(tenantR)
+	if err != nil {
+		return "", err
+	}
+
+	masterCredentialsSecret := &v1.Secret{}
+	err = k8sClient.Get(context.TODO(), masterCredentialsSecretNN, masterCredentialsSecret)
 	if err != nil {
 		return "", err
 	}
@@ -158,7 +327,7 @@ func FetchMasterCredentials(k8sClient client.Client, tenantR *apiv1alpha1.Tenant
 	masterAccessTokenByteArray, ok := masterCredentialsSecret.Data[component.SystemSecretSystemSeedMasterAccessTokenFieldName]
 	if !ok {
 		return "", fmt.Errorf("Key not found in master secret (ns: %s, name: %s) key: %s",
-			tenantR.Spec.MasterCredentialsRef.Namespace, tenantR.Spec.MasterCredentialsRef.Name,
+			masterCredentialsSecretNN.Namespace, masterCredentialsSecretNN.Name,
 			component.SystemSecretSystemSeedMasterAccessTokenFieldName)
 	}
 
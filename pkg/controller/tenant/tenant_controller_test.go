@@ -0,0 +1,132 @@
+package tenant
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta/fake"
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// unreachableMaster fails the test if the master portal is contacted
+func unreachableMaster(t *testing.T) porta.ClientFactory {
+	return func(string, string, *tls.Config) (porta.Client, error) {
+		t.Error("master portal must not be contacted")
+		return nil, errors.New("master portal unreachable")
+	}
+}
+
+func newTestReconcileTenant(k8sClient client.Client, factory porta.ClientFactory) *ReconcileTenant {
+	return &ReconcileTenant{
+		client:             k8sClient,
+		apiReader:          k8sClient,
+		recorder:           record.NewFakeRecorder(10),
+		portaClientFactory: factory,
+	}
+}
+
+// reconcileTerminatingTenant marks the tenant for deletion and reconciles it
+func reconcileTerminatingTenant(t *testing.T, k8sClient client.Client, tenantR *apiv1alpha1.Tenant, factory porta.ClientFactory) *apiv1alpha1.Tenant {
+	tenantR.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+	if err := k8sClient.Update(context.TODO(), tenantR); err != nil {
+		t.Fatal(err)
+	}
+
+	nn := types.NamespacedName{Name: tenantR.Name, Namespace: tenantR.Namespace}
+	if _, err := newTestReconcileTenant(k8sClient, factory).Reconcile(reconcile.Request{NamespacedName: nn}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := &apiv1alpha1.Tenant{}
+	if err := k8sClient.Get(context.TODO(), nn, found); err != nil {
+		t.Fatal(err)
+	}
+	if found.HasFinalizer() {
+		t.Error("expected the tenant finalizer to be removed")
+	}
+	return found
+}
+
+func TestReconcileDeletedTenantNeverCreated(t *testing.T) {
+	tenantR := newTestTenant()
+	tenantR.AddFinalizer()
+	// Neither the master credentials nor the master portal are available
+	k8sClient := newTestK8sClient(t, tenantR)
+
+	reconcileTerminatingTenant(t, k8sClient, tenantR, unreachableMaster(t))
+}
+
+func TestReconcileDeletedTenantMasterPortalGone(t *testing.T) {
+	tenantR := newTestTenant()
+	tenantR.Spec.SystemMasterUrl = ""
+	tenantR.Spec.MasterCredentialsRef = v1.SecretReference{}
+	tenantR.Spec.APIManagerRef = &apiv1alpha1.APIManagerReference{Name: "example-apimanager"}
+	tenantR.AddFinalizer()
+	tenantR.Status.TenantId = 3
+	// The referenced APIManager was deleted
+	k8sClient := k8sfake.NewFakeClientWithScheme(newTenantTestScheme(t), tenantR)
+
+	found := reconcileTerminatingTenant(t, k8sClient, tenantR, unreachableMaster(t))
+	if found.Status.Deletion == nil || found.Status.Deletion.Phase != apiv1alpha1.TenantDeletionPhaseCompleted || found.Status.Deletion.Message == "" {
+		t.Errorf("expected a completed deletion status explaining the tenant was not cleaned up, got %+v", found.Status.Deletion)
+	}
+}
+
+func TestReconcileDeletedTenantMasterCredentialsNotFound(t *testing.T) {
+	tenantR := newTestTenant()
+	tenantR.AddFinalizer()
+	tenantR.Status.TenantId = 3
+	// The master credentials secret is missing, but the master portal may still exist
+	k8sClient := newTestK8sClient(t, tenantR)
+	tenantR.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+	if err := k8sClient.Update(context.TODO(), tenantR); err != nil {
+		t.Fatal(err)
+	}
+
+	nn := types.NamespacedName{Name: tenantR.Name, Namespace: tenantR.Namespace}
+	if _, err := newTestReconcileTenant(k8sClient, unreachableMaster(t)).Reconcile(reconcile.Request{NamespacedName: nn}); err == nil {
+		t.Fatal("expected the missing master credentials to be reported")
+	}
+
+	found := &apiv1alpha1.Tenant{}
+	if err := k8sClient.Get(context.TODO(), nn, found); err != nil {
+		t.Fatal(err)
+	}
+	if !found.HasFinalizer() {
+		t.Error("expected the tenant finalizer to be kept")
+	}
+}
+
+func TestReconcileDeletedTenant(t *testing.T) {
+	tenantR := newTestTenant()
+	tenantR.AddFinalizer()
+	k8sClient := newTestK8sClient(t, tenantR)
+	masterSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-seed", Namespace: tenantR.Namespace},
+		Data:       map[string][]byte{component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("m4st3r")},
+	}
+	if err := k8sClient.Create(context.TODO(), masterSecret); err != nil {
+		t.Fatal(err)
+	}
+	portal := fake.NewAdminPortal()
+	if err := NewInternalReconciler(k8sClient, tenantR, portal, logf.Log, record.NewFakeRecorder(10)).Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := reconcileTerminatingTenant(t, k8sClient, tenantR, portal.ClientFactory())
+	if _, err := portal.ShowTenant(found.Status.TenantId); err == nil {
+		t.Errorf("tenant %d still exists", found.Status.TenantId)
+	}
+}
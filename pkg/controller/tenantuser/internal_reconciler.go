@@ -0,0 +1,320 @@
+package tenantuser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	porta_client_pkg "github.com/3scale/3scale-porta-go-client/client"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InternalReconciler reconciles a TenantUser object
+type InternalReconciler struct {
+	k8sClient   client.Client
+	tenantUser  *apiv1alpha1.TenantUser
+	tenantID    int64
+	portaClient porta.Client
+	logger      logr.Logger
+	recorder    record.EventRecorder
+}
+
+// NewInternalReconciler constructs InternalReconciler object
+func NewInternalReconciler(k8sClient client.Client, tenantUser *apiv1alpha1.TenantUser, tenantID int64,
+	portaClient porta.Client, log logr.Logger, recorder record.EventRecorder) *InternalReconciler {
+	return &InternalReconciler{
+		k8sClient:   k8sClient,
+		tenantUser:  tenantUser,
+		tenantID:    tenantID,
+		portaClient: portaClient,
+		logger:      log,
+		recorder:    recorder,
+	}
+}
+
+// Run tenant user reconciliation logic
+// Facts to reconcile:
+// - Have 3scale user in the tenant account
+// - User attributes and role match the spec
+// - User state (active/suspended) matches the spec
+func (r *InternalReconciler) Run() error {
+	userDef, err := r.reconcileUser()
+	if err != nil {
+		return err
+	}
+
+	err = r.syncUser(userDef)
+	if err != nil {
+		return err
+	}
+
+	role, err := r.syncUserRole(userDef)
+	if err != nil {
+		return err
+	}
+
+	err = r.syncUserState(userDef)
+	if err != nil {
+		return err
+	}
+
+	userStatus := r.tenantUser.Status.DeepCopy()
+	userStatus.UserId = userDef.ID
+	userStatus.TenantId = r.tenantID
+	userStatus.State = userDef.State
+	userStatus.Role = role
+	userStatus.ObservedGeneration = r.tenantUser.Generation
+	userStatus.SetCondition(apiv1alpha1.TenantReady, v1.ConditionTrue, "UserReady", "")
+	return r.updateStatus(userStatus)
+}
+
+// Finalize tenant user cleanup logic, run when the tenant user object is being deleted.
+// Only the user recorded in the status is deleted, which is always a user created by the
+// tenant user object
+func (r *InternalReconciler) Finalize() error {
+	userID := r.tenantUser.Status.UserId
+	if userID != 0 && r.tenantUser.Status.TenantId == r.tenantID {
+		r.logger.Info("Deleting user", "TenantId", r.tenantID, "UserID", userID)
+		err := r.portaClient.DeleteUser(r.tenantID, userID)
+		if err != nil && !porta.IsNotFound(err) {
+			return err
+		}
+		r.recorder.Eventf(r.tenantUser, v1.EventTypeNormal, EventReasonUserDeleted, "User %d deleted", userID)
+	}
+
+	r.tenantUser.RemoveFinalizer()
+	return r.k8sClient.Update(context.TODO(), r.tenantUser)
+}
+
+// This method makes sure that the user exists, otherwise it will create one
+func (r *InternalReconciler) reconcileUser() (*porta_client_pkg.User, error) {
+	userDef, err := r.fetchUser()
+	if err != nil {
+		return nil, err
+	}
+
+	if userDef != nil {
+		return userDef, nil
+	}
+
+	existingUser, err := r.findUser()
+	if err != nil {
+		return nil, err
+	}
+	if existingUser != nil {
+		return nil, &UserExistsError{Username: existingUser.UserName, UserID: existingUser.ID}
+	}
+
+	userDef, err = r.createUser()
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the ownership of the user right away, so it is not taken
+	// for a foreign user if a later reconciliation step fails
+	userStatus := r.tenantUser.Status.DeepCopy()
+	userStatus.UserId = userDef.ID
+	userStatus.TenantId = r.tenantID
+	err = r.updateStatus(userStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	return userDef, nil
+}
+
+func (r *InternalReconciler) fetchUser() (*porta_client_pkg.User, error) {
+	if r.tenantUser.Status.UserId == 0 || r.tenantUser.Status.TenantId != r.tenantID {
+		// userId not in status field, or it belongs to another tenant
+		return nil, nil
+	}
+
+	userDef, err := r.portaClient.ReadUser(r.tenantID, r.tenantUser.Status.UserId)
+	if err != nil && porta.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return userDef, nil
+}
+
+// UserExistsError is returned when the tenant already has a user with the username of
+// the tenant user object that was not created by it. Such users are never adopted, so
+// deleting the tenant user object cannot delete them
+type UserExistsError struct {
+	Username string
+	UserID   int64
+}
+
+func (e *UserExistsError) Error() string {
+	return fmt.Sprintf("user %s already exists with ID %d and is not managed by this TenantUser", e.Username, e.UserID)
+}
+
+// IsUserExists returns true if the error is a UserExistsError
+func IsUserExists(err error) bool {
+	_, ok := err.(*UserExistsError)
+	return ok
+}
+
+// findUser looks up a user with the username of the tenant user object
+func (r *InternalReconciler) findUser() (*porta_client_pkg.User, error) {
+	userList, err := r.portaClient.ListUsers(r.tenantID, porta_client_pkg.Params{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range userList.Users {
+		if user.User.UserName == r.tenantUser.Spec.Username {
+			// user is already a copy from User slice element
+			return &user.User, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InternalReconciler) createUser() (*porta_client_pkg.User, error) {
+	password, err := r.getPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Info("Creating a new user", "TenantId", r.tenantID,
+		"Username", r.tenantUser.Spec.Username, "Email", r.tenantUser.Spec.Email)
+	userDef, err := r.portaClient.CreateUser(r.tenantID, r.tenantUser.Spec.Username, r.tenantUser.Spec.Email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recorder.Eventf(r.tenantUser, v1.EventTypeNormal, EventReasonUserCreated, "User created with ID %d", userDef.ID)
+	return userDef, nil
+}
+
+func (r *InternalReconciler) getPassword() (string, error) {
+	secret := &v1.Secret{}
+	err := r.k8sClient.Get(context.TODO(),
+		types.NamespacedName{
+			Name:      r.tenantUser.Spec.PasswordCredentialsRef.Name,
+			Namespace: r.tenantUser.Spec.PasswordCredentialsRef.Namespace,
+		},
+		secret)
+	if err != nil {
+		return "", err
+	}
+
+	password, ok := secret.Data[TenantUserPasswordSecretField]
+	if !ok {
+		return "", fmt.Errorf("Not found user password secret (ns: %s, name: %s) attribute: %s",
+			r.tenantUser.Spec.PasswordCredentialsRef.Namespace, r.tenantUser.Spec.PasswordCredentialsRef.Name,
+			TenantUserPasswordSecretField)
+	}
+
+	return string(password), nil
+}
+
+func (r *InternalReconciler) syncUser(userDef *porta_client_pkg.User) error {
+	if r.tenantUser.Spec.Username == userDef.UserName && r.tenantUser.Spec.Email == userDef.Email {
+		return nil
+	}
+
+	r.logger.Info("Syncing user", "TenantId", r.tenantID, "UserID", userDef.ID)
+	params := porta_client_pkg.Params{
+		"username": r.tenantUser.Spec.Username,
+		"email":    r.tenantUser.Spec.Email,
+	}
+	_, err := r.portaClient.UpdateUser(r.tenantID, userDef.ID, params)
+	if err != nil {
+		return err
+	}
+	userDef.UserName = r.tenantUser.Spec.Username
+	userDef.Email = r.tenantUser.Spec.Email
+
+	r.recorder.Eventf(r.tenantUser, v1.EventTypeNormal, EventReasonUserUpdated, "User %d updated", userDef.ID)
+	return nil
+}
+
+func (r *InternalReconciler) syncUserRole(userDef *porta_client_pkg.User) (apiv1alpha1.TenantUserRole, error) {
+	// porta user objects do not include the role, list the admins instead
+	adminList, err := r.portaClient.ListUsers(r.tenantID, porta_client_pkg.Params{"role": string(apiv1alpha1.TenantUserRoleAdmin)})
+	if err != nil {
+		return "", err
+	}
+
+	currentRole := apiv1alpha1.TenantUserRoleMember
+	for _, user := range adminList.Users {
+		if user.User.ID == userDef.ID {
+			currentRole = apiv1alpha1.TenantUserRoleAdmin
+		}
+	}
+
+	desiredRole := r.tenantUser.Spec.Role
+	if desiredRole != apiv1alpha1.TenantUserRoleAdmin && desiredRole != apiv1alpha1.TenantUserRoleMember {
+		return "", fmt.Errorf("Unknown tenant user role: %s", desiredRole)
+	}
+	if currentRole == desiredRole {
+		return currentRole, nil
+	}
+
+	r.logger.Info("Changing user role", "TenantId", r.tenantID, "UserID", userDef.ID, "Role", desiredRole)
+	err = r.portaClient.ChangeUserRole(r.tenantID, userDef.ID, string(desiredRole))
+	if err != nil {
+		return "", err
+	}
+
+	r.recorder.Eventf(r.tenantUser, v1.EventTypeNormal, EventReasonUserUpdated, "User %d role changed to %s", userDef.ID, desiredRole)
+	return desiredRole, nil
+}
+
+func (r *InternalReconciler) syncUserState(userDef *porta_client_pkg.User) error {
+	if userDef.State == "pending" {
+		r.logger.Info("Activating pending user", "TenantId", r.tenantID, "UserID", userDef.ID)
+		err := r.portaClient.ActivateUser(r.tenantID, userDef.ID)
+		if err != nil {
+			return err
+		}
+		userDef.State = "active"
+		r.recorder.Eventf(r.tenantUser, v1.EventTypeNormal, EventReasonUserActivated, "User %d activated", userDef.ID)
+	}
+
+	desiredState := string(r.tenantUser.Spec.State)
+	if userDef.State == desiredState {
+		return nil
+	}
+
+	switch r.tenantUser.Spec.State {
+	case apiv1alpha1.TenantUserStateSuspended:
+		r.logger.Info("Suspending user", "TenantId", r.tenantID, "UserID", userDef.ID)
+		err := r.portaClient.SuspendUser(r.tenantID, userDef.ID)
+		if err != nil {
+			return err
+		}
+		r.recorder.Eventf(r.tenantUser, v1.EventTypeNormal, EventReasonUserSuspended, "User %d suspended", userDef.ID)
+	case apiv1alpha1.TenantUserStateActive:
+		r.logger.Info("Unsuspending user", "TenantId", r.tenantID, "UserID", userDef.ID)
+		err := r.portaClient.UnsuspendUser(r.tenantID, userDef.ID)
+		if err != nil {
+			return err
+		}
+		r.recorder.Eventf(r.tenantUser, v1.EventTypeNormal, EventReasonUserActivated, "User %d activated", userDef.ID)
+	default:
+		return fmt.Errorf("Unknown tenant user state: %s", r.tenantUser.Spec.State)
+	}
+
+	userDef.State = desiredState
+	return nil
+}
+
+func (r *InternalReconciler) updateStatus(userStatus *apiv1alpha1.TenantUserStatus) error {
+	// don't update the status if there aren't any changes.
+	if reflect.DeepEqual(r.tenantUser.Status, *userStatus) {
+		return nil
+	}
+	r.logger.Info("update tenant user status", "status", userStatus)
+	r.tenantUser.Status = *userStatus
+	return r.k8sClient.Status().Update(context.TODO(), r.tenantUser)
+}
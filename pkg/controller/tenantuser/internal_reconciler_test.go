@@ -0,0 +1,120 @@
+package tenantuser
+
+import (
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/porta/fake"
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// newTestTenantUser returns a tenant user of a new tenant of the portal, and a client holding its password secret
+func newTestTenantUser(t *testing.T, portal *fake.AdminPortal) (*apiv1alpha1.TenantUser, int64, client.Client) {
+	tenantDef, err := portal.CreateTenant("ECorp", "admin", "admin@ecorp.example.com", "p4ssw0rd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tenantUser := &apiv1alpha1.TenantUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "jdoe", Namespace: "operator-test"},
+		Spec: apiv1alpha1.TenantUserSpec{
+			TenantRef:              v1.LocalObjectReference{Name: "ecorp"},
+			Username:               "jdoe",
+			Email:                  "jdoe@ecorp.example.com",
+			PasswordCredentialsRef: v1.SecretReference{Name: "jdoe-password"},
+		},
+	}
+	tenantUser.SetDefaults()
+	tenantUser.AddFinalizer()
+
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := apiv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	passwordSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "jdoe-password", Namespace: tenantUser.Namespace},
+		Data:       map[string][]byte{TenantUserPasswordSecretField: []byte("s3cr3t")},
+	}
+	return tenantUser, tenantDef.Signup.Account.ID, k8sfake.NewFakeClientWithScheme(s, tenantUser, passwordSecret)
+}
+
+func TestInternalReconcilerManagesUser(t *testing.T) {
+	portal := fake.NewAdminPortal()
+	tenantUser, tenantID, k8sClient := newTestTenantUser(t, portal)
+
+	run := func() {
+		err := NewInternalReconciler(k8sClient, tenantUser, tenantID, portal, logf.Log, record.NewFakeRecorder(10)).Run()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	run()
+	userDef, err := portal.ReadUser(tenantID, tenantUser.Status.UserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userDef.State != "active" || tenantUser.Status.Role != apiv1alpha1.TenantUserRoleMember {
+		t.Errorf("unexpected user state %s and role %s", userDef.State, tenantUser.Status.Role)
+	}
+
+	tenantUser.Spec.Role = apiv1alpha1.TenantUserRoleAdmin
+	tenantUser.Spec.State = apiv1alpha1.TenantUserStateSuspended
+	run()
+	userDef, err = portal.ReadUser(tenantID, tenantUser.Status.UserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userDef.State != "suspended" || tenantUser.Status.Role != apiv1alpha1.TenantUserRoleAdmin {
+		t.Errorf("unexpected user state %s and role %s", userDef.State, tenantUser.Status.Role)
+	}
+
+	err = NewInternalReconciler(k8sClient, tenantUser, tenantID, portal, logf.Log, record.NewFakeRecorder(10)).Finalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := portal.ReadUser(tenantID, tenantUser.Status.UserId); err == nil {
+		t.Errorf("user %d not deleted", tenantUser.Status.UserId)
+	}
+	if tenantUser.HasFinalizer() {
+		t.Error("tenant user finalizer not removed")
+	}
+}
+
+func TestInternalReconcilerDoesNotAdoptExistingUser(t *testing.T) {
+	portal := fake.NewAdminPortal()
+	tenantUser, tenantID, k8sClient := newTestTenantUser(t, portal)
+	existingUser, err := portal.CreateUser(tenantID, "jdoe", "john@example.com", "0th3r")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewInternalReconciler(k8sClient, tenantUser, tenantID, portal, logf.Log, record.NewFakeRecorder(10)).Run()
+	if !IsUserExists(err) {
+		t.Fatalf("expected a user exists error, got %v", err)
+	}
+	if tenantUser.Status.UserId != 0 {
+		t.Errorf("existing user %d recorded in the status", tenantUser.Status.UserId)
+	}
+
+	err = NewInternalReconciler(k8sClient, tenantUser, tenantID, portal, logf.Log, record.NewFakeRecorder(10)).Finalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	userDef, err := portal.ReadUser(tenantID, existingUser.ID)
+	if err != nil {
+		t.Fatalf("existing user deleted: %v", err)
+	}
+	if userDef.Email != "john@example.com" {
+		t.Errorf("existing user modified: %+v", userDef)
+	}
+}
@@ -0,0 +1,276 @@
+package tenantuser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
+	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/controller/tenant"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/metrics"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_tenantuser")
+
+// Secret field name with the tenant user password
+const TenantUserPasswordSecretField = "password"
+
+// Reasons of the events emitted on the TenantUser resource
+const (
+	EventReasonUserCreated     = "UserCreated"
+	EventReasonUserUpdated     = "UserUpdated"
+	EventReasonUserActivated   = "UserActivated"
+	EventReasonUserSuspended   = "UserSuspended"
+	EventReasonUserDeleted     = "UserDeleted"
+	EventReasonTenantNotReady  = "TenantNotReady"
+	EventReasonReconcileFailed = "ReconcileFailed"
+	EventReasonUserExists      = "UserExists"
+)
+
+// userExistsRequeueDelay is the interval between checks of a conflicting user
+// not created by the TenantUser, until it is removed from the tenant
+const userExistsRequeueDelay = 5 * time.Minute
+
+// Add creates a new TenantUser Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	apiReader, err := tenant.NewAPIReader(mgr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReconcileTenantUser{
+		client:             mgr.GetClient(),
+		apiReader:          apiReader,
+		scheme:             mgr.GetScheme(),
+		recorder:           mgr.GetRecorder("tenantuser-controller"),
+		portaClientFactory: helper.PortaClientFromURLString,
+	}, nil
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("tenantuser-controller", mgr, controller.Options{Reconciler: metrics.NewInstrumentedReconciler("tenantuser-controller", r)})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource TenantUser
+	err = c.Watch(&source.Kind{Type: &apiv1alpha1.TenantUser{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Tenants, so users are reconciled once their tenant is ready
+	err = c.Watch(&source.Kind{Type: &apiv1alpha1.Tenant{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			return tenantUserRequests(mgr.GetClient(), obj.Meta.GetNamespace(), obj.Meta.GetName())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// tenantUserRequests returns the requests for the TenantUsers referencing the given tenant
+func tenantUserRequests(c client.Client, namespace, tenantName string) []reconcile.Request {
+	tenantUserList := &apiv1alpha1.TenantUserList{}
+	err := c.List(context.TODO(), &client.ListOptions{Namespace: namespace}, tenantUserList)
+	if err != nil {
+		log.Error(err, "Error listing tenant users", "Namespace", namespace)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, tenantUser := range tenantUserList.Items {
+		if tenantUser.Spec.TenantRef.Name == tenantName {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: tenantUser.Name, Namespace: tenantUser.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// blank assignment to verify that ReconcileTenantUser implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileTenantUser{}
+
+// ReconcileTenantUser reconciles a TenantUser object
+type ReconcileTenantUser struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	// apiReader reads the master credentials, see tenant.FetchMasterPortal
+	apiReader          client.Reader
+	scheme             *runtime.Scheme
+	recorder           record.EventRecorder
+	portaClientFactory porta.ClientFactory
+}
+
+// Reconcile reads that state of the cluster for a TenantUser object and makes changes based on the state read
+// and what is in the TenantUser.Spec
+func (r *ReconcileTenantUser) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling TenantUser")
+
+	// Fetch the TenantUser instance
+	tenantUser := &apiv1alpha1.TenantUser{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, tenantUser)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			reqLogger.Info("TenantUser resource not found")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if tenantUser.IsTerminating() && !tenantUser.HasFinalizer() {
+		reqLogger.Info("TenantUser resource is being deleted")
+		return reconcile.Result{}, nil
+	}
+
+	if !tenantUser.IsTerminating() {
+		changed := tenantUser.SetDefaults()
+		if !tenantUser.HasFinalizer() {
+			tenantUser.AddFinalizer()
+			changed = true
+		}
+		if changed {
+			err = r.client.Update(context.TODO(), tenantUser)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			reqLogger.Info("TenantUser resource updated with defaults")
+			// Expect for re-trigger
+			return reconcile.Result{}, nil
+		}
+	}
+
+	tenantR := &apiv1alpha1.Tenant{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: tenantUser.Spec.TenantRef.Name, Namespace: tenantUser.Namespace}, tenantR)
+	if err != nil && errors.IsNotFound(err) {
+		if tenantUser.IsTerminating() {
+			// Tenant is gone, and so are its users
+			tenantUser.RemoveFinalizer()
+			return reconcile.Result{}, r.client.Update(context.TODO(), tenantUser)
+		}
+		reqLogger.Info("Tenant not found", "Tenant", tenantUser.Spec.TenantRef.Name)
+		r.updateFailedStatus(tenantUser, EventReasonTenantNotReady, "Tenant %s not found", tenantUser.Spec.TenantRef.Name)
+		// Tenant watch will re-trigger
+		return reconcile.Result{}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if tenantR.Status.TenantId == 0 {
+		if tenantUser.IsTerminating() {
+			tenantUser.RemoveFinalizer()
+			return reconcile.Result{}, r.client.Update(context.TODO(), tenantUser)
+		}
+		reqLogger.Info("Tenant not created yet", "Tenant", tenantR.Name)
+		r.updateFailedStatus(tenantUser, EventReasonTenantNotReady, "Tenant %s not created yet", tenantR.Name)
+		// Tenant watch will re-trigger
+		return reconcile.Result{}, nil
+	}
+
+	portaClient, err := r.masterPortaClient(tenantR)
+	if err != nil {
+		log.Error(err, "Error creating porta client object")
+		r.recorder.Eventf(tenantUser, v1.EventTypeWarning, EventReasonReconcileFailed, "Error creating master portal client: %v", err)
+		r.updateFailedStatus(tenantUser, EventReasonReconcileFailed, "%v", err)
+		return reconcile.Result{}, err
+	}
+
+	internalReconciler := NewInternalReconciler(r.client, tenantUser, tenantR.Status.TenantId, portaClient, reqLogger, r.recorder)
+	if tenantUser.IsTerminating() {
+		err = internalReconciler.Finalize()
+		if err != nil {
+			log.Error(err, "Error in tenant user finalization")
+			r.recorder.Eventf(tenantUser, v1.EventTypeWarning, EventReasonReconcileFailed, "Tenant user deletion failed: %v", err)
+			return reconcile.Result{}, err
+		}
+		reqLogger.Info("TenantUser finalized successfully")
+		return reconcile.Result{}, nil
+	}
+
+	err = internalReconciler.Run()
+	if err != nil && IsUserExists(err) {
+		reqLogger.Info("User already exists in the tenant, not adopting it", "Username", tenantUser.Spec.Username)
+		r.recorder.Eventf(tenantUser, v1.EventTypeWarning, EventReasonUserExists, "%v", err)
+		r.updateFailedStatus(tenantUser, EventReasonUserExists, "%v", err)
+		return reconcile.Result{RequeueAfter: userExistsRequeueDelay}, nil
+	} else if err != nil {
+		log.Error(err, "Error in tenant user reconciliation")
+		r.recorder.Eventf(tenantUser, v1.EventTypeWarning, EventReasonReconcileFailed, "Tenant user reconciliation failed: %v", err)
+		r.updateFailedStatus(tenantUser, EventReasonReconcileFailed, "%v", err)
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("TenantUser reconciled successfully")
+	return reconcile.Result{}, nil
+}
+
+// masterPortaClient builds a porta client for the master portal managing the tenant
+func (r *ReconcileTenantUser) masterPortaClient(tenantR *apiv1alpha1.Tenant) (porta.Client, error) {
+	masterPortal, err := tenant.FetchMasterPortal(r.apiReader, tenantR)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := masterPortal.TLSConfig(r.apiReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.portaClientFactory(masterPortal.URL, masterPortal.AccessToken, tlsConfig)
+}
+
+// updateFailedStatus records the reconciliation error in the tenant user Ready condition
+func (r *ReconcileTenantUser) updateFailedStatus(tenantUser *apiv1alpha1.TenantUser, reason string, format string, args ...interface{}) {
+	if tenantUser.IsTerminating() {
+		return
+	}
+
+	userStatus := tenantUser.Status.DeepCopy()
+	userStatus.ObservedGeneration = tenantUser.Generation
+	userStatus.SetCondition(apiv1alpha1.TenantReady, v1.ConditionFalse, reason, fmt.Sprintf(format, args...))
+
+	if reflect.DeepEqual(tenantUser.Status, *userStatus) {
+		return
+	}
+	tenantUser.Status = *userStatus
+	err := r.client.Status().Update(context.TODO(), tenantUser)
+	if err != nil {
+		log.Error(err, "Error updating tenant user status")
+	}
+}
@@ -0,0 +1,96 @@
+package crypto
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Charset names accepted by Policy
+const (
+	AlphanumericCharsetName          = "alphanumeric"
+	LowercaseAlphanumericCharsetName = "lowercaseAlphanumeric"
+	HexadecimalCharsetName           = "hexadecimal"
+)
+
+var charsets = map[string]string{
+	AlphanumericCharsetName:          alphanumericCharset,
+	LowercaseAlphanumericCharsetName: alphanumericLowercaseCharset,
+	HexadecimalCharsetName:           hexadecimalCharset,
+}
+
+// Policy defines how a secret value is generated and when an existing value is weak
+type Policy struct {
+	// Length is the minimum length of generated values
+	Length int
+	// Charset is the name of the charset of generated values
+	Charset string
+	// MinEntropyBits is the minimum estimated entropy of a value. Generated values
+	// are made longer than Length when needed to reach it
+	MinEntropyBits int
+}
+
+// Validate checks the policy can generate values
+func (p Policy) Validate() error {
+	if _, ok := charsets[p.Charset]; !ok {
+		return fmt.Errorf("unknown charset '%s'", p.Charset)
+	}
+	if p.Length <= 0 {
+		return fmt.Errorf("length must be positive, got %d", p.Length)
+	}
+	if p.MinEntropyBits < 0 {
+		return fmt.Errorf("minimum entropy must not be negative, got %d", p.MinEntropyBits)
+	}
+	return nil
+}
+
+// Generate returns a random value meeting the policy
+func (p Policy) Generate() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	charset := charsets[p.Charset]
+	length := p.Length
+	if minLength := int(math.Ceil(float64(p.MinEntropyBits) / math.Log2(float64(len(charset))))); minLength > length {
+		length = minLength
+	}
+
+	// The estimated entropy of a value depends on the character classes it contains.
+	// Unlucky values, i.e. without any letter, are generated again, so
+	// generated values are never detected as weak
+	for {
+		value := StringWithCharset(length, charset)
+		if !p.Weak(value) {
+			return value, nil
+		}
+	}
+}
+
+// Weak returns true when the estimated entropy of the value is below the policy minimum
+func (p Policy) Weak(value string) bool {
+	return EstimatedEntropyBits(value) < float64(p.MinEntropyBits)
+}
+
+// EstimatedEntropyBits estimates the entropy of a value from its length and
+// the character classes (lowercase, uppercase, digits and symbols) it contains
+func EstimatedEntropyBits(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	poolSize := 0
+	for _, class := range []string{lowercaseAlphabetCharset, uppercaseAlphabetCharset, numericCharset} {
+		if strings.ContainsAny(value, class) {
+			poolSize += len(class)
+		}
+	}
+	if strings.IndexFunc(value, func(r rune) bool {
+		return !strings.ContainsRune(alphanumericCharset, r)
+	}) >= 0 {
+		// printable ASCII symbols
+		poolSize += 32
+	}
+
+	return float64(len(value)) * math.Log2(float64(poolSize))
+}
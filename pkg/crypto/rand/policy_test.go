@@ -0,0 +1,57 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPolicyGenerate(t *testing.T) {
+	cases := []struct {
+		policy         Policy
+		expectedLength int
+	}{
+		{Policy{Length: 16, Charset: AlphanumericCharsetName, MinEntropyBits: 80}, 16},
+		{Policy{Length: 8, Charset: AlphanumericCharsetName, MinEntropyBits: 80}, 14},
+		{Policy{Length: 8, Charset: HexadecimalCharsetName, MinEntropyBits: 128}, 32},
+	}
+
+	for _, c := range cases {
+		value, err := c.policy.Generate()
+		if err != nil {
+			t.Fatalf("%+v: unexpected error: %v", c.policy, err)
+		}
+		if len(value) != c.expectedLength {
+			t.Errorf("%+v: length = %d, expected %d", c.policy, len(value), c.expectedLength)
+		}
+		if strings.Trim(value, charsets[c.policy.Charset]) != "" {
+			t.Errorf("%+v: value %s out of charset", c.policy, value)
+		}
+		if c.policy.Weak(value) {
+			t.Errorf("%+v: generated value %s is weak", c.policy, value)
+		}
+	}
+
+	if _, err := (Policy{Length: 8, Charset: "base64"}).Generate(); err == nil {
+		t.Error("expected unknown charset error")
+	}
+}
+
+func TestPolicyWeak(t *testing.T) {
+	policy := Policy{Length: 16, Charset: AlphanumericCharsetName, MinEntropyBits: 80}
+	cases := []struct {
+		value string
+		weak  bool
+	}{
+		{"", true},
+		{"aB3dE5gH", true},
+		{"aB3dE5gHiJ7lMn9p", false},
+		{"0123456789012345678901", true},
+		{"01234567890123456789012345", false},
+	}
+
+	for _, c := range cases {
+		if weak := policy.Weak(c.value); weak != c.weak {
+			t.Errorf("value %q: weak = %t, expected %t (%.1f bits)", c.value, weak, c.weak, EstimatedEntropyBits(c.value))
+		}
+	}
+}
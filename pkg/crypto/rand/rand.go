@@ -1,8 +1,8 @@
 package crypto
 
 import (
-	"math/rand"
-	"time"
+	"crypto/rand"
+	"math/big"
 )
 
 const lowercaseAlphabetCharset = "abcdefghijklmnopqrstuvwxyz"
@@ -14,8 +14,6 @@ const alphanumericLowercaseCharset = lowercaseAlphabetCharset + numericCharset
 const alphanumericCharset = lowercaseAlphabetCharset + uppercaseAlphabetCharset + numericCharset
 const hexadecimalCharset = numericCharset + "ABCDEF"
 
-var randomGenerator *rand.Rand = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
-
 // String generates random alphanumeric string of size 'size'.
 func String(length int) string {
 	return StringWithCharset(length, alphanumericCharset)
@@ -28,12 +26,18 @@ func HexadecimalString(length int) string {
 
 // StringWithCharset generates random string of length 'length' with all of its
 // random characters existing in and only in the 'charset' set of
-// strings
+// strings. Characters are read from the operating system CSPRNG.
+// It panics if the CSPRNG cannot be read
 func StringWithCharset(length int, charset string) string {
 	result := make([]byte, length)
+	max := big.NewInt(int64(len(charset)))
 
 	for i := range result {
-		result[i] = charset[randomGenerator.Int63()%int64(len(charset))]
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		result[i] = charset[n.Int64()]
 	}
 
 	return string(result)
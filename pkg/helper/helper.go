@@ -7,30 +7,34 @@ import (
 	"net/url"
 	"strconv"
 
-	"github.com/3scale/3scale-porta-go-client/client"
+	"github.com/3scale/3scale-operator/pkg/3scale/porta"
+	"github.com/3scale/3scale-operator/pkg/metrics"
+	routev1 "github.com/openshift/api/route/v1"
 )
 
-// PortaClientFromURLString instantiate porta_client.ThreeScaleClient from admin url string
-func PortaClientFromURLString(adminURLStr, masterAccessToken string) (*client.ThreeScaleClient, error) {
+// PortaClientFromURLString instantiate porta.ThreeScaleClient from admin url string
+// It satisfies porta.ClientFactory
+func PortaClientFromURLString(adminURLStr, masterAccessToken string, tlsConfig *tls.Config) (porta.Client, error) {
 	adminURL, err := url.Parse(adminURLStr)
 	if err != nil {
 		return nil, err
 	}
-	return PortaClient(adminURL, masterAccessToken)
+	return PortaClient(adminURL, masterAccessToken, tlsConfig)
 }
 
-// PortaClient instantiates porta_client.ThreeScaleClient from admin url object
-func PortaClient(url *url.URL, masterAccessToken string) (*client.ThreeScaleClient, error) {
-	adminPortal, err := client.NewAdminPortal(url.Scheme, url.Hostname(), PortFromURL(url))
-	if err != nil {
-		return nil, err
-	}
-
+// PortaClient instantiates porta.ThreeScaleClient from admin url object
+// A nil tlsConfig verifies the admin portal certificate against the system roots
+func PortaClient(url *url.URL, masterAccessToken string, tlsConfig *tls.Config) (porta.Client, error) {
 	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		TLSClientConfig: tlsConfig,
 	}
 
-	return client.NewThreeScale(adminPortal, masterAccessToken, &http.Client{Transport: tr}), nil
+	httpClient := &http.Client{Transport: metrics.NewInstrumentedRoundTripper(tr)}
+	portaClient, err := porta.NewThreeScaleClient(url.Scheme, url.Hostname(), PortFromURL(url), masterAccessToken, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return portaClient, nil
 }
 
 // PortFromURL infers port number if it is not explict
@@ -62,3 +66,12 @@ func SetURLDefaultPort(rawurl string) string {
 	portNum := PortFromURL(urlObj)
 	return fmt.Sprintf("%s:%d", urlObj.String(), portNum)
 }
+
+// RouteURL returns the base URL served by the route, https when the route terminates TLS
+func RouteURL(route *routev1.Route) string {
+	scheme := "https"
+	if route.Spec.TLS == nil {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s", scheme, route.Spec.Host)
+}
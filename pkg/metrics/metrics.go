@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const namespace = "threescale_operator"
+
+var (
+	// ReconcileDuration keeps track of the duration of reconciliations per controller
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of reconciliations per controller",
+	}, []string{"controller"})
+
+	// ReconcileErrors holds the total number of reconciliations per controller
+	// that ended with an error
+	ReconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Total number of reconciliation errors per controller",
+	}, []string{"controller"})
+
+	// BindingSyncStatus is 1 when the binding current state is in sync with
+	// the desired state, 0 otherwise
+	BindingSyncStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "binding_sync_status",
+		Help:      "Whether the Binding is in sync with 3scale (1) or not (0)",
+	}, []string{"namespace", "binding"})
+
+	// BindingAPIsManaged holds the number of APIs managed in 3scale per binding
+	BindingAPIsManaged = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "binding_apis_managed",
+		Help:      "Number of APIs managed in 3scale by the Binding",
+	}, []string{"namespace", "binding"})
+
+	// BindingLastSuccessfulSync holds the unix timestamp of the last
+	// successful sync per binding
+	BindingLastSuccessfulSync = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "binding_last_successful_sync_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful sync of the Binding with 3scale",
+	}, []string{"namespace", "binding"})
+
+	// PortaRequestDuration keeps track of the duration of the requests done
+	// to the 3scale admin portal API, labeled by operation and status code
+	PortaRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "porta_request_duration_seconds",
+		Help:      "Duration of requests to the 3scale admin portal API",
+	}, []string{"operation", "code"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ReconcileDuration,
+		ReconcileErrors,
+		BindingSyncStatus,
+		BindingAPIsManaged,
+		BindingLastSuccessfulSync,
+		PortaRequestDuration,
+	)
+}
+
+// InstrumentedReconciler wraps a reconcile.Reconciler recording the
+// duration and errors of every reconciliation
+type InstrumentedReconciler struct {
+	controllerName string
+	reconciler     reconcile.Reconciler
+}
+
+// NewInstrumentedReconciler returns a reconcile.Reconciler that records
+// metrics for the given reconciler under the given controller name
+func NewInstrumentedReconciler(controllerName string, r reconcile.Reconciler) *InstrumentedReconciler {
+	return &InstrumentedReconciler{controllerName: controllerName, reconciler: r}
+}
+
+// Reconcile implements reconcile.Reconciler
+func (i *InstrumentedReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	result, err := i.reconciler.Reconcile(request)
+	ReconcileDuration.WithLabelValues(i.controllerName).Observe(time.Since(start).Seconds())
+	if err != nil {
+		ReconcileErrors.WithLabelValues(i.controllerName).Inc()
+	}
+	return result, err
+}
+
+// SetBindingMetrics updates the gauges of the given binding
+func SetBindingMetrics(bindingNamespace, bindingName string, inSync bool, apisManaged int, lastSync *time.Time) {
+	syncStatus := 0.0
+	if inSync {
+		syncStatus = 1.0
+	}
+	BindingSyncStatus.WithLabelValues(bindingNamespace, bindingName).Set(syncStatus)
+	BindingAPIsManaged.WithLabelValues(bindingNamespace, bindingName).Set(float64(apisManaged))
+	if lastSync != nil {
+		BindingLastSuccessfulSync.WithLabelValues(bindingNamespace, bindingName).Set(float64(lastSync.Unix()))
+	}
+}
+
+// DeleteBindingMetrics removes the gauges of the given binding
+func DeleteBindingMetrics(bindingNamespace, bindingName string) {
+	BindingSyncStatus.DeleteLabelValues(bindingNamespace, bindingName)
+	BindingAPIsManaged.DeleteLabelValues(bindingNamespace, bindingName)
+	BindingLastSuccessfulSync.DeleteLabelValues(bindingNamespace, bindingName)
+}
+
+// InstrumentedRoundTripper records the duration and status code of every
+// request done to the 3scale admin portal API
+type InstrumentedRoundTripper struct {
+	next http.RoundTripper
+}
+
+// NewInstrumentedRoundTripper wraps the given http.RoundTripper
+func NewInstrumentedRoundTripper(next http.RoundTripper) *InstrumentedRoundTripper {
+	return &InstrumentedRoundTripper{next: next}
+}
+
+// RoundTrip implements http.RoundTripper
+func (i *InstrumentedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := i.next.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	PortaRequestDuration.WithLabelValues(PortaOperation(req), code).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+var idPathSegment = regexp.MustCompile(`^[0-9]+$`)
+
+// PortaOperation builds a low cardinality operation name out of a request
+// to the 3scale admin portal API, replacing resource ids by ":id"
+// and removing the response format extension.
+// For instance: "GET /admin/api/services/:id/proxy"
+func PortaOperation(req *http.Request) string {
+	path := strings.TrimSuffix(strings.TrimSuffix(req.URL.Path, ".json"), ".xml")
+	segments := strings.Split(path, "/")
+	for idx, segment := range segments {
+		if idPathSegment.MatchString(segment) {
+			segments[idx] = ":id"
+		}
+	}
+	return fmt.Sprintf("%s %s", req.Method, strings.Join(segments, "/"))
+}
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type reconcilerFunc func(reconcile.Request) (reconcile.Result, error)
+
+func (f reconcilerFunc) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	return f(request)
+}
+
+// scrapeMetrics returns the metrics of the controller-runtime registry in
+// the Prometheus text format
+func scrapeMetrics(t *testing.T) string {
+	recorder := httptest.NewRecorder()
+	promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := ioutil.ReadAll(recorder.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestPortaOperation(t *testing.T) {
+	cases := []struct {
+		method   string
+		url      string
+		expected string
+	}{
+		{"GET", "https://3scale-admin.example.com/admin/api/services.json", "GET /admin/api/services"},
+		{"PUT", "https://3scale-admin.example.com/admin/api/services/42/proxy.xml", "PUT /admin/api/services/:id/proxy"},
+		{"DELETE", "https://3scale-admin.example.com/admin/api/services/42/metrics/7.json?access_token=secret", "DELETE /admin/api/services/:id/metrics/:id"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.url, nil)
+		if operation := PortaOperation(req); operation != tc.expected {
+			t.Errorf("%s %s: expected operation %q, got %q", tc.method, tc.url, tc.expected, operation)
+		}
+	}
+}
+
+func TestInstrumentedRoundTripper(t *testing.T) {
+	PortaRequestDuration.Reset()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/api/services.json" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewInstrumentedRoundTripper(http.DefaultTransport)}
+	for _, path := range []string{"/admin/api/services.json", "/admin/api/services.json", "/admin/api/services/42.json"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	failingClient := &http.Client{Transport: NewInstrumentedRoundTripper(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}))}
+	req, _ := http.NewRequest("DELETE", server.URL+"/admin/api/services/42.json", nil)
+	if _, err := failingClient.Do(req); err == nil {
+		t.Fatal("expected an error from the failing transport")
+	}
+
+	scraped := scrapeMetrics(t)
+	expected := []string{
+		`threescale_operator_porta_request_duration_seconds_count{code="200",operation="GET /admin/api/services"} 2`,
+		`threescale_operator_porta_request_duration_seconds_count{code="404",operation="GET /admin/api/services/:id"} 1`,
+		`threescale_operator_porta_request_duration_seconds_count{code="error",operation="DELETE /admin/api/services/:id"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(scraped, line) {
+			t.Errorf("expected metric %s in:\n%s", line, scraped)
+		}
+	}
+}
+
+func TestInstrumentedReconciler(t *testing.T) {
+	ReconcileDuration.Reset()
+	ReconcileErrors.Reset()
+
+	fail := false
+	r := NewInstrumentedReconciler("test-controller", reconcilerFunc(func(reconcile.Request) (reconcile.Result, error) {
+		if fail {
+			return reconcile.Result{}, errors.New("reconcile failed")
+		}
+		return reconcile.Result{}, nil
+	}))
+
+	if _, err := r.Reconcile(reconcile.Request{}); err != nil {
+		t.Fatal(err)
+	}
+	fail = true
+	if _, err := r.Reconcile(reconcile.Request{}); err == nil {
+		t.Fatal("expected the reconcile error to be returned")
+	}
+
+	if errorCount := testutil.ToFloat64(ReconcileErrors.WithLabelValues("test-controller")); errorCount != 1 {
+		t.Errorf("expected 1 reconcile error, got %v", errorCount)
+	}
+	line := `threescale_operator_reconcile_duration_seconds_count{controller="test-controller"} 2`
+	if scraped := scrapeMetrics(t); !strings.Contains(scraped, line) {
+		t.Errorf("expected metric %s in:\n%s", line, scraped)
+	}
+}